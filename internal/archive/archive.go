@@ -0,0 +1,360 @@
+// Package archive reads and writes zepctl project archives: gzipped tar files
+// holding a manifest and one NDJSON file per resource type.
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// FormatVersion is the archive layout version written to the manifest.
+// Readers reject archives with a newer version.
+const FormatVersion = 1
+
+// ManifestName is the name of the manifest entry, always first in the archive.
+const ManifestName = "manifest.json"
+
+// Resource types stored in an archive.
+const (
+	Users               = "users"
+	Threads             = "threads"
+	Messages            = "messages"
+	Graphs              = "graphs"
+	Episodes            = "episodes"
+	Nodes               = "nodes"
+	Edges               = "edges"
+	Ontology            = "ontology"
+	SummaryInstructions = "summary_instructions"
+)
+
+// Resources lists every resource type in the order its file is written.
+var Resources = []string{Users, Threads, Messages, Graphs, Episodes, Nodes, Edges, Ontology, SummaryInstructions}
+
+// ErrInvalidArchive is returned when an archive is malformed, fails checksum
+// verification or was written by a newer version of zepctl.
+var ErrInvalidArchive = errors.New("invalid archive")
+
+// Manifest describes the contents of an archive.
+type Manifest struct {
+	FormatVersion int             `json:"format_version"`
+	CreatedAt     string          `json:"created_at"`
+	ZepctlVersion string          `json:"zepctl_version"`
+	Project       json.RawMessage `json:"project,omitempty"`
+	Files         []File          `json:"files"`
+}
+
+// File describes one resource file in the archive.
+type File struct {
+	Name     string `json:"name"`
+	Resource string `json:"resource"`
+	Records  int    `json:"records"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+}
+
+// File returns the file entry for a resource, if present.
+func (m *Manifest) File(resource string) (File, bool) {
+	i := slices.IndexFunc(m.Files, func(f File) bool { return f.Resource == resource })
+	if i < 0 {
+		return File{}, false
+	}
+	return m.Files[i], true
+}
+
+// ID identifies an archive by its creation time and file checksums, e.g. to
+// match an import checkpoint to the archive it was written for.
+func (m *Manifest) ID() string {
+	h := sha256.New()
+	fmt.Fprintln(h, m.CreatedAt)
+	for _, f := range m.Files {
+		fmt.Fprintln(h, f.Name, f.SHA256)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Record is one line of a resource file. Items that belong to a user graph,
+// standalone graph or thread carry the owning ID alongside the item.
+type Record[T any] struct {
+	UserID   string `json:"user_id,omitempty"`
+	GraphID  string `json:"graph_id,omitempty"`
+	ThreadID string `json:"thread_id,omitempty"`
+	Data     T      `json:"data"`
+}
+
+func fileName(resource string) string {
+	return resource + ".ndjson"
+}
+
+// Writer builds an archive. Records are spooled to temporary files, since tar
+// entries need their size up front, and assembled by Close. Every resource
+// type gets a file, even if it has no records.
+type Writer struct {
+	dir   string
+	files map[string]*spoolFile
+}
+
+type spoolFile struct {
+	f       *os.File
+	buf     *bufio.Writer
+	hash    hash.Hash
+	size    int64
+	records int
+}
+
+// NewWriter creates a writer that spools records in a temporary directory.
+func NewWriter() (*Writer, error) {
+	dir, err := os.MkdirTemp("", "zepctl-export-")
+	if err != nil {
+		return nil, fmt.Errorf("creating spool directory: %w", err)
+	}
+
+	w := &Writer{dir: dir, files: make(map[string]*spoolFile)}
+	for _, resource := range Resources {
+		f, err := os.Create(filepath.Join(dir, fileName(resource)))
+		if err != nil {
+			w.Discard()
+			return nil, fmt.Errorf("creating spool file: %w", err)
+		}
+		w.files[resource] = &spoolFile{f: f, buf: bufio.NewWriter(f), hash: sha256.New()}
+	}
+	return w, nil
+}
+
+// Add appends a record to the resource's NDJSON file.
+func (w *Writer) Add(resource string, record any) error {
+	sf, ok := w.files[resource]
+	if !ok {
+		return fmt.Errorf("unknown resource type %q", resource)
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encoding %s record: %w", resource, err)
+	}
+	line = append(line, '\n')
+	if _, err := io.MultiWriter(sf.buf, sf.hash).Write(line); err != nil {
+		return fmt.Errorf("writing %s record: %w", resource, err)
+	}
+	sf.size += int64(len(line))
+	sf.records++
+	return nil
+}
+
+// Close writes the archive to path with the manifest first, followed by
+// every resource file, and removes the spool directory. It returns m with
+// FormatVersion and Files filled in from the records added.
+func (w *Writer) Close(path string, m Manifest) (Manifest, error) {
+	defer w.Discard()
+
+	m.FormatVersion = FormatVersion
+	m.Files = nil
+	for _, resource := range Resources {
+		sf := w.files[resource]
+		if err := sf.buf.Flush(); err != nil {
+			return m, fmt.Errorf("writing %s records: %w", resource, err)
+		}
+		m.Files = append(m.Files, File{
+			Name:     fileName(resource),
+			Resource: resource,
+			Records:  sf.records,
+			Size:     sf.size,
+			SHA256:   hex.EncodeToString(sf.hash.Sum(nil)),
+		})
+	}
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return m, fmt.Errorf("encoding manifest: %w", err)
+	}
+
+	// Write to a temporary file first so a failed export never leaves a
+	// truncated archive at path.
+	out, err := os.CreateTemp(filepath.Dir(path), ".zepctl-export-*")
+	if err != nil {
+		return m, fmt.Errorf("creating archive: %w", err)
+	}
+	defer os.Remove(out.Name())
+
+	if err := w.writeTar(out, manifest, m.Files); err != nil {
+		_ = out.Close()
+		return m, err
+	}
+	if err := out.Close(); err != nil {
+		return m, fmt.Errorf("writing archive: %w", err)
+	}
+	if err := os.Rename(out.Name(), path); err != nil {
+		return m, fmt.Errorf("writing archive: %w", err)
+	}
+	return m, nil
+}
+
+func (w *Writer) writeTar(out io.Writer, manifest []byte, files []File) error {
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	modTime := time.Now()
+
+	if err := tw.WriteHeader(&tar.Header{Name: ManifestName, Mode: 0o644, Size: int64(len(manifest)), ModTime: modTime}); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	if _, err := tw.Write(manifest); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+
+	for _, file := range files {
+		sf := w.files[file.Resource]
+		if _, err := sf.f.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("reading %s records: %w", file.Resource, err)
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: file.Name, Mode: 0o644, Size: file.Size, ModTime: modTime}); err != nil {
+			return fmt.Errorf("writing archive: %w", err)
+		}
+		if _, err := io.Copy(tw, sf.f); err != nil {
+			return fmt.Errorf("writing archive: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	return nil
+}
+
+// Discard removes the spool directory without writing an archive.
+func (w *Writer) Discard() {
+	for _, sf := range w.files {
+		_ = sf.f.Close()
+	}
+	_ = os.RemoveAll(w.dir)
+}
+
+// Reader reads records from an archive.
+type Reader struct {
+	path     string
+	manifest Manifest
+}
+
+// Open reads an archive's manifest and verifies the checksum of every file it
+// lists, so a corrupted archive is rejected before any records are used.
+func Open(path string) (*Reader, error) {
+	r := &Reader{path: path}
+	sums := make(map[string]string)
+	sawManifest := false
+
+	err := r.walk(func(name string, body io.Reader) error {
+		if name == ManifestName {
+			if err := json.NewDecoder(body).Decode(&r.manifest); err != nil {
+				return fmt.Errorf("%w: reading manifest: %v", ErrInvalidArchive, err)
+			}
+			sawManifest = true
+			return nil
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, body); err != nil {
+			return fmt.Errorf("%w: reading %s: %v", ErrInvalidArchive, name, err)
+		}
+		sums[name] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !sawManifest {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, ManifestName)
+	}
+	if r.manifest.FormatVersion < 1 || r.manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d (this zepctl reads up to %d)",
+			ErrInvalidArchive, r.manifest.FormatVersion, FormatVersion)
+	}
+	for _, file := range r.manifest.Files {
+		sum, ok := sums[file.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, file.Name)
+		}
+		if sum != file.SHA256 {
+			return nil, fmt.Errorf("%w: checksum mismatch for %s", ErrInvalidArchive, file.Name)
+		}
+	}
+	return r, nil
+}
+
+// Manifest returns the archive's manifest.
+func (r *Reader) Manifest() Manifest {
+	return r.manifest
+}
+
+// Each calls fn with every record of a resource, in the order they were
+// written. Resources missing from the archive have no records.
+func (r *Reader) Each(resource string, fn func(line int, record json.RawMessage) error) error {
+	file, ok := r.manifest.File(resource)
+	if !ok {
+		return nil
+	}
+	return r.walk(func(name string, body io.Reader) error {
+		if name != file.Name {
+			return nil
+		}
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 64<<20)
+		line := 0
+		for scanner.Scan() {
+			line++
+			if len(scanner.Bytes()) == 0 {
+				continue
+			}
+			if err := fn(line, json.RawMessage(slices.Clone(scanner.Bytes()))); err != nil {
+				return err
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("%w: reading %s: %v", ErrInvalidArchive, name, err)
+		}
+		return nil
+	})
+}
+
+// walk calls fn for each regular file in the archive.
+func (r *Reader) walk(fn func(name string, body io.Reader) error) error {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return fmt.Errorf("opening archive: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
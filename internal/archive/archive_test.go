@@ -0,0 +1,185 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type item struct {
+	Name string `json:"name"`
+}
+
+func writeArchive(t *testing.T, records map[string][]Record[item]) string {
+	t.Helper()
+	w, err := NewWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, resource := range Resources {
+		for _, rec := range records[resource] {
+			if err := w.Add(resource, rec); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+	if _, err := w.Close(path, Manifest{ZepctlVersion: "test"}); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return path
+}
+
+func TestRoundTrip(t *testing.T) {
+	path := writeArchive(t, map[string][]Record[item]{
+		Users:    {{Data: item{"alice"}}, {Data: item{"bob"}}},
+		Messages: {{ThreadID: "t1", Data: item{"hello"}}},
+	})
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	m := r.Manifest()
+	if m.FormatVersion != FormatVersion || m.ZepctlVersion != "test" {
+		t.Errorf("unexpected manifest: %+v", m)
+	}
+	if len(m.Files) != len(Resources) {
+		t.Errorf("manifest lists %d files, want one per resource (%d)", len(m.Files), len(Resources))
+	}
+
+	tests := []struct {
+		resource string
+		want     []Record[item]
+	}{
+		{resource: Users, want: []Record[item]{{Data: item{"alice"}}, {Data: item{"bob"}}}},
+		{resource: Messages, want: []Record[item]{{ThreadID: "t1", Data: item{"hello"}}}},
+		{resource: Edges},
+		{resource: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resource, func(t *testing.T) {
+			var got []Record[item]
+			err := r.Each(tt.resource, func(_ int, raw json.RawMessage) error {
+				var rec Record[item]
+				if err := json.Unmarshal(raw, &rec); err != nil {
+					return err
+				}
+				got = append(got, rec)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Each: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d records, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("record %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+			if f, ok := m.File(tt.resource); ok && f.Records != len(tt.want) {
+				t.Errorf("manifest records = %d, want %d", f.Records, len(tt.want))
+			}
+		})
+	}
+}
+
+// rewriteArchive copies an archive, letting edit change each entry's body.
+func rewriteArchive(t *testing.T, src string, edit func(name string, body []byte) []byte) string {
+	t.Helper()
+	in, err := os.Open(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	dst := filepath.Join(t.TempDir(), "edited.tar.gz")
+	out, err := os.Create(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(tr)
+		body = edit(hdr.Name, body)
+		hdr.Size = int64(len(body))
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = tw.Write(body)
+	}
+	_ = tw.Close()
+	_ = gw.Close()
+	return dst
+}
+
+func TestOpenRejectsInvalidArchives(t *testing.T) {
+	path := writeArchive(t, map[string][]Record[item]{Users: {{Data: item{"alice"}}}})
+
+	tests := []struct {
+		name string
+		edit func(name string, body []byte) []byte
+	}{
+		{
+			name: "tampered file",
+			edit: func(name string, body []byte) []byte {
+				if name == "users.ndjson" {
+					return []byte(`{"data":{"name":"mallory"}}` + "\n")
+				}
+				return body
+			},
+		},
+		{
+			name: "newer format version",
+			edit: func(name string, body []byte) []byte {
+				if name != ManifestName {
+					return body
+				}
+				var m Manifest
+				_ = json.Unmarshal(body, &m)
+				m.FormatVersion = FormatVersion + 1
+				data, _ := json.Marshal(m)
+				return data
+			},
+		},
+		{
+			name: "missing manifest",
+			edit: func(name string, body []byte) []byte {
+				if name == ManifestName {
+					return []byte("not json")
+				}
+				return body
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Open(rewriteArchive(t, path, tt.edit))
+			if !errors.Is(err, ErrInvalidArchive) {
+				t.Errorf("Open error = %v, want ErrInvalidArchive", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Package cache stores API responses on disk so repeated lookups skip the
+// network. Entries live under ~/.zepctl/cache, in one directory per scope
+// (the project being talked to) with a subdirectory per resource type.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache reads and writes the entries of one scope. A nil *Cache caches
+// nothing, so callers need not check whether caching is enabled.
+type Cache struct {
+	dir string
+}
+
+// entry is one cached value as stored on disk.
+type entry struct {
+	ExpiresAt time.Time       `json:"expires_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Dir returns the root cache directory, ~/.zepctl/cache.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	return filepath.Join(home, ".zepctl", "cache"), nil
+}
+
+// Open returns the cache for a scope. The scope is hashed into the directory
+// name, so it may include secrets such as an API key.
+func Open(scope string) (*Cache, error) {
+	root, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{dir: filepath.Join(root, hash(scope))}, nil
+}
+
+// Get decodes the cached value of a resource into v. It reports false if
+// there is no entry, or it has expired or cannot be read.
+func (c *Cache) Get(resource, id string, v any) bool {
+	if c == nil {
+		return false
+	}
+	data, err := os.ReadFile(c.path(resource, id))
+	if err != nil {
+		return false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || time.Now().After(e.ExpiresAt) {
+		return false
+	}
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// Put caches v as the value of a resource for ttl.
+func (c *Cache) Put(resource, id string, v any, ttl time.Duration) error {
+	if c == nil {
+		return nil
+	}
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+	data, err := json.Marshal(entry{ExpiresAt: time.Now().Add(ttl), Value: value})
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+
+	path := c.path(resource, id)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	// Write to a temporary file first so concurrent readers never see a
+	// partial entry.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	return nil
+}
+
+// Invalidate removes the cached entries of a resource with the given IDs, or
+// every entry of the resource if no IDs are given.
+func (c *Cache) Invalidate(resource string, ids ...string) error {
+	if c == nil {
+		return nil
+	}
+	if len(ids) == 0 {
+		if err := os.RemoveAll(filepath.Join(c.dir, resource)); err != nil {
+			return fmt.Errorf("invalidating cache: %w", err)
+		}
+		return nil
+	}
+	for _, id := range ids {
+		if err := os.Remove(c.path(resource, id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("invalidating cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// Clear removes the cache directory with the entries of every scope.
+func Clear() error {
+	root, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(root); err != nil {
+		return fmt.Errorf("clearing cache: %w", err)
+	}
+	return nil
+}
+
+// path returns the file of an entry. IDs are hashed, since they can contain
+// characters that are not valid in file names.
+func (c *Cache) path(resource, id string) string {
+	return filepath.Join(c.dir, resource, hash(id)+".json")
+}
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	c, err := Open("profile\nhttps://api.example.com\nkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := Open("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type user struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	put := func(c *Cache, resource, id string, v any, ttl time.Duration) {
+		t.Helper()
+		if err := c.Put(resource, id, v, ttl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put(c, "users", "alice", user{ID: "alice", Name: "Alice"}, time.Minute)
+	put(c, "users", "bob", user{ID: "bob"}, time.Minute)
+	put(c, "users", "expired", user{ID: "expired"}, -time.Second)
+	put(c, "ids", "", []string{"a", "b/c"}, time.Minute)
+
+	var u user
+	if !c.Get("users", "alice", &u) || u.Name != "Alice" {
+		t.Errorf("Get(alice) = %+v, want cached Alice", u)
+	}
+	if c.Get("users", "expired", &u) {
+		t.Error("Get returned an expired entry")
+	}
+	if c.Get("users", "carol", &u) {
+		t.Error("Get returned a missing entry")
+	}
+	if other.Get("users", "alice", &u) {
+		t.Error("Get returned an entry of another scope")
+	}
+	var ids []string
+	if !c.Get("ids", "", &ids) || len(ids) != 2 {
+		t.Errorf("Get(ids) = %q", ids)
+	}
+
+	if err := c.Invalidate("users", "alice", "missing"); err != nil {
+		t.Fatal(err)
+	}
+	if c.Get("users", "alice", &u) {
+		t.Error("Get returned an invalidated entry")
+	}
+	if !c.Get("users", "bob", &u) {
+		t.Error("Invalidate removed an entry with another ID")
+	}
+	if err := c.Invalidate("users"); err != nil {
+		t.Fatal(err)
+	}
+	if c.Get("users", "bob", &u) {
+		t.Error("Get returned an entry of an invalidated resource")
+	}
+	if !c.Get("ids", "", &ids) {
+		t.Error("Invalidate removed an entry of another resource")
+	}
+
+	if err := Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Get("ids", "", &ids) {
+		t.Error("Get returned an entry after Clear")
+	}
+	dir, err := Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("cache directory still exists after Clear: %v", err)
+	}
+}
+
+func TestNilCache(t *testing.T) {
+	var c *Cache
+	if err := c.Put("users", "alice", "Alice", time.Minute); err != nil {
+		t.Errorf("Put: %v", err)
+	}
+	var v string
+	if c.Get("users", "alice", &v) {
+		t.Error("nil cache returned an entry")
+	}
+	if err := c.Invalidate("users"); err != nil {
+		t.Errorf("Invalidate: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package chatimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+func init() {
+	Register("chatgpt", AdapterFunc(parseChatGPT))
+}
+
+// chatGPTConversation is a conversation of a ChatGPT data export. Its
+// messages form a tree, since editing a prompt or regenerating a response
+// starts a new branch; current_node is the last message of the branch that
+// was shown.
+type chatGPTConversation struct {
+	ID             string                 `json:"id"`
+	ConversationID string                 `json:"conversation_id"`
+	Title          string                 `json:"title"`
+	CurrentNode    string                 `json:"current_node"`
+	Mapping        map[string]chatGPTNode `json:"mapping"`
+}
+
+type chatGPTNode struct {
+	Parent  string          `json:"parent"`
+	Message *chatGPTMessage `json:"message"`
+}
+
+type chatGPTMessage struct {
+	Author struct {
+		Role string `json:"role"`
+		Name string `json:"name"`
+	} `json:"author"`
+	CreateTime float64 `json:"create_time"`
+	Content    struct {
+		ContentType string `json:"content_type"`
+		Parts       []any  `json:"parts"`
+		Text        string `json:"text"`
+	} `json:"content"`
+	Metadata struct {
+		Hidden bool `json:"is_visually_hidden_from_conversation"`
+	} `json:"metadata"`
+}
+
+// parseChatGPT reads conversations.json of a ChatGPT data export, or the
+// unzipped export directory that contains it.
+func parseChatGPT(path string, opts Options) ([]*Conversation, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, "conversations.json")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var exported []chatGPTConversation
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var convs []*Conversation
+	for _, ec := range exported {
+		conv := &Conversation{ID: ec.ConversationID, Title: ec.Title}
+		if conv.ID == "" {
+			conv.ID = ec.ID
+		}
+		// Walk the shown branch back to the root. seen guards against
+		// malformed exports with a cycle.
+		seen := make(map[string]bool)
+		for id := ec.CurrentNode; id != "" && !seen[id]; id = ec.Mapping[id].Parent {
+			seen[id] = true
+			if m, ok := ec.Mapping[id].Message.convert(opts); ok {
+				conv.Messages = append(conv.Messages, m)
+			}
+		}
+		slices.Reverse(conv.Messages)
+		if len(conv.Messages) == 0 {
+			continue
+		}
+		if conv.ID == "" {
+			conv.ID = contentID(conv.Messages)
+		}
+		convs = append(convs, conv)
+	}
+	return convs, nil
+}
+
+// convert returns the message as shown in the conversation, or false for
+// messages ChatGPT does not show, such as the empty system prompt.
+func (m *chatGPTMessage) convert(opts Options) (Message, bool) {
+	if m == nil || m.Metadata.Hidden {
+		return Message{}, false
+	}
+	var parts []string
+	for _, p := range m.Content.Parts {
+		// Non-text parts are images and other attachments.
+		if s, ok := p.(string); ok && strings.TrimSpace(s) != "" {
+			parts = append(parts, s)
+		}
+	}
+	if m.Content.Text != "" {
+		parts = append(parts, m.Content.Text)
+	}
+	content := strings.Join(parts, "\n\n")
+	if strings.TrimSpace(content) == "" {
+		return Message{}, false
+	}
+
+	msg := Message{Name: m.Author.Name, Content: content, CreatedAt: unixTime(m.CreateTime)}
+	switch m.Author.Role {
+	case RoleAssistant, RoleSystem, RoleTool:
+		msg.Role = m.Author.Role
+	default:
+		msg.Role = RoleUser
+	}
+	if opts.isAssistant(m.Author.Name) {
+		msg.Role = RoleAssistant
+	}
+	return msg, true
+}
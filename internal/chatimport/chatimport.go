@@ -0,0 +1,164 @@
+// Package chatimport parses chat history exports from other products into
+// conversations that can be loaded into Zep threads.
+//
+// Each export format is an Adapter registered under a name. The built-in
+// adapters are chatgpt, slack, discord and openai-messages; a new format is
+// added by implementing Adapter and calling Register from an init function.
+package chatimport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message roles. They match Zep's role types.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleSystem    = "system"
+	RoleTool      = "tool"
+	RoleFunction  = "function"
+	RoleNone      = "norole"
+)
+
+// Message is one message of a conversation.
+type Message struct {
+	Role    string
+	Name    string
+	Content string
+	// CreatedAt is when the message was sent, or zero if the export does
+	// not record it.
+	CreatedAt time.Time
+}
+
+// Conversation is a sequence of messages that becomes one thread.
+type Conversation struct {
+	// ID identifies the conversation within its export, e.g. a channel ID.
+	// It is stable across exports so re-importing finds the same thread.
+	ID       string
+	Title    string
+	Messages []Message
+}
+
+// Options are the adapter settings given on the command line.
+type Options struct {
+	// Assistants are speakers, by name or ID, whose messages have the
+	// assistant role. Formats without roles treat everyone else as a user.
+	Assistants []string
+}
+
+// isAssistant reports whether any of a speaker's names or IDs is listed in
+// Assistants.
+func (o Options) isAssistant(names ...string) bool {
+	for _, name := range names {
+		if name != "" && slices.Contains(o.Assistants, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Adapter parses one export format.
+type Adapter interface {
+	// Parse reads the export at path, a file or a directory, and returns its
+	// conversations with their messages in chronological order.
+	Parse(path string, opts Options) ([]*Conversation, error)
+}
+
+// AdapterFunc adapts a function to the Adapter interface.
+type AdapterFunc func(path string, opts Options) ([]*Conversation, error)
+
+// Parse calls f.
+func (f AdapterFunc) Parse(path string, opts Options) ([]*Conversation, error) {
+	return f(path, opts)
+}
+
+var (
+	mu       sync.RWMutex
+	adapters = make(map[string]Adapter)
+)
+
+// Register makes an adapter available under a format name. It panics if
+// the name is already registered.
+func Register(name string, a Adapter) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := adapters[name]; ok {
+		panic(fmt.Sprintf("chatimport: format %q registered twice", name))
+	}
+	adapters[name] = a
+}
+
+// Lookup returns the adapter registered under a format name.
+func Lookup(name string) (Adapter, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	a, ok := adapters[name]
+	return a, ok
+}
+
+// Formats lists the registered format names, sorted.
+func Formats() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return slices.Sorted(maps.Keys(adapters))
+}
+
+// contentID derives a stable conversation ID from its messages, for formats
+// that do not carry one.
+func contentID(messages []Message) string {
+	h := sha256.New()
+	for _, m := range messages {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00", m.Role, m.Name, m.Content)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// unixTime converts fractional Unix seconds to a time.
+func unixTime(seconds float64) time.Time {
+	if seconds <= 0 {
+		return time.Time{}
+	}
+	sec := int64(seconds)
+	return time.Unix(sec, int64((seconds-float64(sec))*1e9)).UTC().Truncate(time.Microsecond)
+}
+
+// sortByTime orders messages by CreatedAt, keeping the export order of
+// messages sent at the same time. A message without a time sorts as if sent
+// at the time of the message before it, so it stays right after that message.
+func sortByTime(messages []Message) {
+	type timed struct {
+		at time.Time
+		m  Message
+	}
+	sorted := make([]timed, len(messages))
+	var at time.Time
+	for i, m := range messages {
+		if !m.CreatedAt.IsZero() {
+			at = m.CreatedAt
+		}
+		sorted[i] = timed{at, m}
+	}
+	slices.SortStableFunc(sorted, func(a, b timed) int {
+		return a.at.Compare(b.at)
+	})
+	for i, t := range sorted {
+		messages[i] = t.m
+	}
+}
+
+// firstLine returns the first line of s, shortened to n characters, for
+// conversation titles.
+func firstLine(s string, n int) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-3]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,236 @@
+package chatimport
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFiles creates files under a temporary directory and returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const chatGPTJSON = `[{
+  "id": "c1", "title": "Tea", "current_node": "n4",
+  "mapping": {
+    "n0": {"parent": null, "message": null},
+    "n1": {"parent": "n0", "message": {"author": {"role": "system"}, "content": {"content_type": "text", "parts": [""]}, "metadata": {"is_visually_hidden_from_conversation": true}}},
+    "n2": {"parent": "n1", "message": {"author": {"role": "user"}, "create_time": 1700000000.5, "content": {"content_type": "multimodal_text", "parts": [{"asset_pointer": "file"}, "What tea is this?"]}}},
+    "n3": {"parent": "n2", "message": {"author": {"role": "assistant"}, "create_time": 1700000010, "content": {"content_type": "text", "parts": ["An old answer"]}}},
+    "n4": {"parent": "n2", "message": {"author": {"role": "assistant"}, "create_time": 1700000020, "content": {"content_type": "text", "parts": ["Oolong"]}}}
+  }
+}]`
+
+const discordJSON = `{
+  "guild": {"name": "Acme"}, "channel": {"id": "900", "name": "help"},
+  "messages": [
+    {"type": "Default", "timestamp": "2024-01-15T10:00:05+01:00", "content": "Thanks", "author": {"id": "1", "name": "alice", "nickname": "Alice"}},
+    {"type": "Default", "timestamp": "2024-01-15T09:00:00+00:00", "content": "How do I reset?", "author": {"id": "1", "name": "alice", "nickname": "Alice"}},
+    {"type": "GuildMemberJoin", "timestamp": "2024-01-15T09:00:01+00:00", "content": "", "author": {"id": "2", "name": "bob"}},
+    {"type": "Reply", "timestamp": "2024-01-15T09:00:02+00:00", "content": "Hold the button", "author": {"id": "3", "name": "helper", "isBot": true}}
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		files   map[string]string
+		file    string
+		opts    Options
+		wantIDs []string
+		// want lists the messages of the first conversation as
+		// "role/name: content".
+		want      []string
+		wantTitle string
+		wantStart time.Time
+	}{
+		{
+			name:      "chatgpt follows the shown branch",
+			format:    "chatgpt",
+			files:     map[string]string{"conversations.json": chatGPTJSON},
+			wantIDs:   []string{"c1"},
+			want:      []string{"user/: What tea is this?", "assistant/: Oolong"},
+			wantTitle: "Tea",
+			wantStart: time.Date(2023, 11, 14, 22, 13, 20, 500000000, time.UTC),
+		},
+		{
+			name:   "slack workspace",
+			format: "slack",
+			files: map[string]string{
+				"users.json":               `[{"id": "U1", "name": "alice", "profile": {"real_name": "Alice Smith"}}, {"id": "U2", "name": "sam"}]`,
+				"channels.json":            `[{"id": "C1", "name": "general"}, {"id": "C2", "name": "empty"}]`,
+				"general/2024-01-16.json":  `[{"type": "message", "user": "U2", "text": "Ask <@U1>", "ts": "1705400000.000200"}]`,
+				"general/2024-01-15.json":  `[{"type": "message", "subtype": "channel_join", "user": "U1", "text": "joined", "ts": "1705300000.000000"}, {"type": "message", "user": "U1", "text": "Hi", "ts": "1705300001.000100"}, {"type": "message", "subtype": "bot_message", "username": "deploybot", "bot_id": "B1", "text": "Deployed", "ts": "1705300002.000000"}]`,
+				"empty/2024-01-15.json":    `[]`,
+				"general/notes/ignored.md": "not a day",
+			},
+			opts:      Options{Assistants: []string{"U2"}},
+			wantIDs:   []string{"C1"},
+			want:      []string{"user/Alice Smith: Hi", "assistant/deploybot: Deployed", "assistant/sam: Ask @Alice Smith"},
+			wantTitle: "#general",
+			wantStart: time.Date(2024, 1, 15, 6, 26, 41, 100000, time.UTC),
+		},
+		{
+			name:      "slack channel directory",
+			format:    "slack",
+			files:     map[string]string{"random/2024-01-15.json": `[{"type": "message", "user": "U9", "user_profile": {"real_name": "Zoe"}, "text": "Hey", "ts": "1705300000.5"}]`},
+			file:      "random",
+			wantIDs:   []string{"random"},
+			want:      []string{"user/Zoe: Hey"},
+			wantTitle: "#random",
+			wantStart: time.Date(2024, 1, 15, 6, 26, 40, 500000000, time.UTC),
+		},
+		{
+			name:      "discord sorts by time",
+			format:    "discord",
+			files:     map[string]string{"help.json": discordJSON},
+			wantIDs:   []string{"900"},
+			want:      []string{"user/Alice: How do I reset?", "assistant/helper: Hold the button", "user/Alice: Thanks"},
+			wantTitle: "Acme #help",
+			wantStart: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "openai messages array",
+			format: "openai-messages",
+			files: map[string]string{"chat.json": `[
+  {"role": "developer", "content": "Be brief"},
+  {"role": "user", "name": "alice", "content": [{"type": "text", "text": "Hello there"}, {"type": "image_url", "image_url": {"url": "x"}}]},
+  {"role": "assistant", "content": "Hi", "created_at": "2024-01-15T09:00:00Z"}
+]`},
+			file:      "chat.json",
+			want:      []string{"system/: Be brief", "user/alice: Hello there", "assistant/: Hi"},
+			wantTitle: "Hello there",
+		},
+		{
+			name:   "openai messages jsonl",
+			format: "openai-messages",
+			files: map[string]string{"chats.jsonl": `{"id": "a", "title": "First", "messages": [{"role": "user", "content": "One"}]}
+
+{"id": "b", "messages": [{"role": "user", "content": "Two"}, {"role": "bot", "content": "?"}]}
+`},
+			file:      "chats.jsonl",
+			wantIDs:   []string{"a", "b"},
+			want:      []string{"user/: One"},
+			wantTitle: "First",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter, ok := Lookup(tt.format)
+			if !ok {
+				t.Fatalf("format %q is not registered", tt.format)
+			}
+			convs, err := adapter.Parse(filepath.Join(writeFiles(t, tt.files), tt.file), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(convs) == 0 {
+				t.Fatal("no conversations")
+			}
+
+			var ids []string
+			for _, conv := range convs {
+				ids = append(ids, conv.ID)
+			}
+			if tt.wantIDs != nil && !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("conversation IDs = %v, want %v", ids, tt.wantIDs)
+			}
+			conv := convs[0]
+			if conv.ID == "" {
+				t.Error("conversation has no ID")
+			}
+			var got []string
+			for _, m := range conv.Messages {
+				got = append(got, m.Role+"/"+m.Name+": "+m.Content)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+			if conv.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", conv.Title, tt.wantTitle)
+			}
+			if start := conv.Messages[0].CreatedAt; !start.Equal(tt.wantStart) {
+				t.Errorf("first message created at %v, want %v", start, tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestSortByTime(t *testing.T) {
+	at := func(sec int) time.Time { return time.Unix(int64(sec), 0) }
+	messages := []Message{
+		{Content: "c", CreatedAt: at(30)},
+		{Content: "c2"},
+		{Content: "a", CreatedAt: at(10)},
+		{Content: "b", CreatedAt: at(20)},
+		{Content: "b2"},
+		{Content: "b3", CreatedAt: at(20)},
+	}
+	sortByTime(messages)
+	var got []string
+	for _, m := range messages {
+		got = append(got, m.Content)
+	}
+	if want := []string{"a", "b", "b2", "b3", "c", "c2"}; !slices.Equal(got, want) {
+		t.Errorf("sorted messages = %q, want %q", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"bad.json":      `{"messages": [`,
+		"bad.jsonl":     "{\"messages\": []}\nnot json\n",
+		"users.json":    `{}`,
+		"discord.json":  `[]`,
+		"chatgpt.json":  `{}`,
+		"channels.json": `[]`,
+	})
+	tests := []struct {
+		format, file string
+		wantErr      string
+	}{
+		{"openai-messages", "bad.json", "bad.json"},
+		{"openai-messages", "bad.jsonl", "line 2"},
+		{"openai-messages", "missing.json", "no such file"},
+		{"slack", "", "users.json"},
+		{"discord", "discord.json", "discord.json"},
+		{"chatgpt", "chatgpt.json", "chatgpt.json"},
+	}
+	for _, tt := range tests {
+		adapter, _ := Lookup(tt.format)
+		_, err := adapter.Parse(filepath.Join(dir, tt.file), Options{})
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s %s: error = %v, want one containing %q", tt.format, tt.file, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	want := []string{"chatgpt", "discord", "openai-messages", "slack"}
+	if got := Formats(); !slices.Equal(got, want) {
+		t.Errorf("Formats() = %v, want %v", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a format twice did not panic")
+		}
+	}()
+	Register("slack", AdapterFunc(parseSlack))
+}
@@ -0,0 +1,89 @@
+package chatimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register("discord", AdapterFunc(parseDiscord))
+}
+
+// discordExport is a channel exported as JSON by DiscordChatExporter.
+type discordExport struct {
+	Guild struct {
+		Name string `json:"name"`
+	} `json:"guild"`
+	Channel struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"channel"`
+	Messages []struct {
+		Type      string    `json:"type"`
+		Timestamp time.Time `json:"timestamp"`
+		Content   string    `json:"content"`
+		Author    struct {
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			Nickname string `json:"nickname"`
+			IsBot    bool   `json:"isBot"`
+		} `json:"author"`
+	} `json:"messages"`
+}
+
+// parseDiscord reads a DiscordChatExporter JSON file, or a directory of
+// them, one conversation per channel.
+func parseDiscord(path string, opts Options) ([]*Conversation, error) {
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.json")); err != nil {
+			return nil, err
+		}
+	}
+
+	var convs []*Conversation
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var export discordExport
+		if err := json.Unmarshal(data, &export); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		conv := &Conversation{ID: export.Channel.ID, Title: "#" + export.Channel.Name}
+		if export.Guild.Name != "" {
+			conv.Title = export.Guild.Name + " " + conv.Title
+		}
+		for _, dm := range export.Messages {
+			// Other types are joins, pins, calls and similar events.
+			if dm.Type != "Default" && dm.Type != "Reply" || strings.TrimSpace(dm.Content) == "" {
+				continue
+			}
+			msg := Message{Role: RoleUser, Name: dm.Author.Nickname, Content: dm.Content, CreatedAt: dm.Timestamp.UTC()}
+			if msg.Name == "" {
+				msg.Name = dm.Author.Name
+			}
+			if dm.Author.IsBot || opts.isAssistant(dm.Author.ID, dm.Author.Name, dm.Author.Nickname) {
+				msg.Role = RoleAssistant
+			}
+			conv.Messages = append(conv.Messages, msg)
+		}
+		if len(conv.Messages) == 0 {
+			continue
+		}
+		if conv.ID == "" {
+			conv.ID = contentID(conv.Messages)
+		}
+		sortByTime(conv.Messages)
+		convs = append(convs, conv)
+	}
+	return convs, nil
+}
@@ -0,0 +1,186 @@
+package chatimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register("openai-messages", AdapterFunc(parseOpenAIMessages))
+}
+
+// openAIConversation is a list of Chat Completions messages, optionally
+// with an ID and title of its own.
+type openAIConversation struct {
+	ID       string          `json:"id"`
+	Title    string          `json:"title"`
+	Messages []openAIMessage `json:"messages"`
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Name    string `json:"name"`
+	Content any    `json:"content"`
+	// CreatedAt is not part of the OpenAI format, but is kept when present.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// parseOpenAIMessages reads conversations in the OpenAI Chat Completions
+// message format. A JSON file holds a list of messages, a {"messages": [...]}
+// object or a list of such objects; a JSONL file, as used for fine-tuning,
+// holds one object per line. A directory is read file by file.
+func parseOpenAIMessages(path string, opts Options) ([]*Conversation, error) {
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		files = nil
+		for _, pattern := range []string{"*.json", "*.jsonl"} {
+			matches, err := filepath.Glob(filepath.Join(path, pattern))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, matches...)
+		}
+	}
+
+	var convs []*Conversation
+	for _, file := range files {
+		exported, err := readOpenAIFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, ec := range exported {
+			if conv := ec.convert(opts); conv != nil {
+				convs = append(convs, conv)
+			}
+		}
+	}
+	return convs, nil
+}
+
+func readOpenAIFile(file string) ([]openAIConversation, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	fail := func(err error) error { return fmt.Errorf("%s: %w", file, err) }
+
+	if strings.EqualFold(filepath.Ext(file), ".jsonl") {
+		var convs []openAIConversation
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			var conv openAIConversation
+			if err := json.Unmarshal(scanner.Bytes(), &conv); err != nil {
+				return nil, fail(fmt.Errorf("line %d: %w", line, err))
+			}
+			convs = append(convs, conv)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fail(err)
+		}
+		return convs, nil
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var conv openAIConversation
+		if err := json.Unmarshal(data, &conv); err != nil {
+			return nil, fail(err)
+		}
+		return []openAIConversation{conv}, nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fail(err)
+	}
+	// A list of conversations has objects with a messages key; a list of
+	// messages has objects with a role.
+	var probe struct {
+		Messages json.RawMessage `json:"messages"`
+	}
+	if len(items) > 0 {
+		if err := json.Unmarshal(items[0], &probe); err != nil {
+			return nil, fail(err)
+		}
+	}
+	if probe.Messages != nil {
+		var convs []openAIConversation
+		if err := json.Unmarshal(data, &convs); err != nil {
+			return nil, fail(err)
+		}
+		return convs, nil
+	}
+	var conv openAIConversation
+	if err := json.Unmarshal(data, &conv.Messages); err != nil {
+		return nil, fail(err)
+	}
+	return []openAIConversation{conv}, nil
+}
+
+func (ec openAIConversation) convert(opts Options) *Conversation {
+	conv := &Conversation{ID: ec.ID, Title: ec.Title}
+	for _, om := range ec.Messages {
+		content := openAIContent(om.Content)
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		msg := Message{Role: om.Role, Name: om.Name, Content: content, CreatedAt: om.CreatedAt.UTC()}
+		switch om.Role {
+		case RoleUser, RoleAssistant, RoleSystem, RoleTool, RoleFunction:
+		case "developer":
+			msg.Role = RoleSystem
+		default:
+			msg.Role = RoleNone
+		}
+		if opts.isAssistant(om.Name) {
+			msg.Role = RoleAssistant
+		}
+		conv.Messages = append(conv.Messages, msg)
+	}
+	if len(conv.Messages) == 0 {
+		return nil
+	}
+	if conv.ID == "" {
+		conv.ID = contentID(conv.Messages)
+	}
+	if conv.Title == "" {
+		for _, m := range conv.Messages {
+			if m.Role == RoleUser {
+				conv.Title = firstLine(m.Content, 60)
+				break
+			}
+		}
+	}
+	return conv
+}
+
+// openAIContent returns the text of a message's content, which is either a
+// string or a list of parts of which only text parts are kept.
+func openAIContent(content any) string {
+	switch c := content.(type) {
+	case string:
+		return c
+	case []any:
+		var texts []string
+		for _, part := range c {
+			if p, ok := part.(map[string]any); ok && p["type"] == "text" {
+				if text, ok := p["text"].(string); ok {
+					texts = append(texts, text)
+				}
+			}
+		}
+		return strings.Join(texts, "\n\n")
+	}
+	return ""
+}
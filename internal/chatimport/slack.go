@@ -0,0 +1,195 @@
+package chatimport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register("slack", AdapterFunc(parseSlack))
+}
+
+type slackUser struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name"`
+	Profile slackProfile `json:"profile"`
+}
+
+// displayName returns the name Slack shows for the user.
+func (u slackUser) displayName() string {
+	if name := u.Profile.displayName(); name != "" {
+		return name
+	}
+	return u.Name
+}
+
+// slackProfile is the profile of a user, which messages also carry as of
+// when they were sent.
+type slackProfile struct {
+	Name        string `json:"name"`
+	RealName    string `json:"real_name"`
+	DisplayName string `json:"display_name"`
+}
+
+func (p slackProfile) displayName() string {
+	switch {
+	case p.DisplayName != "":
+		return p.DisplayName
+	case p.RealName != "":
+		return p.RealName
+	}
+	return p.Name
+}
+
+type slackChannel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type slackMessage struct {
+	Type        string       `json:"type"`
+	Subtype     string       `json:"subtype"`
+	User        string       `json:"user"`
+	Username    string       `json:"username"`
+	BotID       string       `json:"bot_id"`
+	Text        string       `json:"text"`
+	TS          string       `json:"ts"`
+	UserProfile slackProfile `json:"user_profile"`
+}
+
+// slackSubtypes are the message subtypes that carry conversation, as
+// opposed to joins, topic changes and other channel events.
+var slackSubtypes = map[string]bool{
+	"":                 true,
+	"bot_message":      true,
+	"file_share":       true,
+	"me_message":       true,
+	"thread_broadcast": true,
+}
+
+var slackMention = regexp.MustCompile(`<@([A-Z0-9]+)(?:\|[^>]*)?>`)
+
+// parseSlack reads an unzipped Slack workspace export: users.json,
+// channels.json and a directory of daily JSON files per channel. A single
+// channel directory is read as one conversation.
+func parseSlack(path string, opts Options) ([]*Conversation, error) {
+	users := make(map[string]slackUser)
+	var list []slackUser
+	if err := readSlackJSON(filepath.Join(path, "users.json"), &list); err != nil {
+		return nil, err
+	}
+	for _, u := range list {
+		users[u.ID] = u
+	}
+
+	var channels []slackChannel
+	for _, name := range []string{"channels.json", "groups.json", "mpims.json", "dms.json"} {
+		var cs []slackChannel
+		if err := readSlackJSON(filepath.Join(path, name), &cs); err != nil {
+			return nil, err
+		}
+		channels = append(channels, cs...)
+	}
+	if len(channels) == 0 {
+		// Without a channel list, path is itself a channel directory.
+		channels = []slackChannel{{ID: filepath.Base(path), Name: filepath.Base(path)}}
+		path = filepath.Dir(path)
+	}
+
+	var convs []*Conversation
+	for _, ch := range channels {
+		// Channels are exported under their name; DMs, which have none,
+		// under their ID.
+		dir := ch.Name
+		if dir == "" {
+			dir = ch.ID
+		}
+		days, err := filepath.Glob(filepath.Join(path, dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		conv := &Conversation{ID: ch.ID, Title: "#" + dir}
+		// The files are named by date, so Glob's sorted order is chronological.
+		for _, day := range days {
+			var messages []slackMessage
+			if err := readSlackJSON(day, &messages); err != nil {
+				return nil, err
+			}
+			for _, sm := range messages {
+				if m, ok := sm.convert(users, opts); ok {
+					conv.Messages = append(conv.Messages, m)
+				}
+			}
+		}
+		if len(conv.Messages) == 0 {
+			continue
+		}
+		sortByTime(conv.Messages)
+		convs = append(convs, conv)
+	}
+	return convs, nil
+}
+
+func (sm slackMessage) convert(users map[string]slackUser, opts Options) (Message, bool) {
+	if sm.Type != "message" || !slackSubtypes[sm.Subtype] || strings.TrimSpace(sm.Text) == "" {
+		return Message{}, false
+	}
+	name := sm.UserProfile.displayName()
+	if u, ok := users[sm.User]; ok && name == "" {
+		name = u.displayName()
+	}
+	if name == "" {
+		name = sm.Username
+	}
+
+	msg := Message{
+		Role: RoleUser,
+		Name: name,
+		Content: slackMention.ReplaceAllStringFunc(sm.Text, func(s string) string {
+			if u, ok := users[slackMention.FindStringSubmatch(s)[1]]; ok {
+				return "@" + u.displayName()
+			}
+			return s
+		}),
+		CreatedAt: slackTime(sm.TS),
+	}
+	if sm.BotID != "" || sm.Subtype == "bot_message" || opts.isAssistant(sm.User, name, sm.Username) {
+		msg.Role = RoleAssistant
+	}
+	return msg, true
+}
+
+// slackTime parses a Slack message timestamp, "seconds.microseconds".
+func slackTime(ts string) time.Time {
+	sec, frac, _ := strings.Cut(ts, ".")
+	s, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	us, _ := strconv.ParseInt((frac + "000000")[:6], 10, 64)
+	return time.Unix(s, us*1000).UTC()
+}
+
+// readSlackJSON decodes a file of the export. Missing files are skipped, as
+// exports only include the lists the exporting user may see.
+func readSlackJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,823 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/pagination"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of manifest documents, and of the entity types, edge types and
+// summary instructions they declare.
+const (
+	kindUser                = "User"
+	kindGraph               = "Graph"
+	kindOntology            = "Ontology"
+	kindSummaryInstructions = "SummaryInstructions"
+
+	kindEntityType         = "EntityType"
+	kindEdgeType           = "EdgeType"
+	kindSummaryInstruction = "SummaryInstruction"
+)
+
+// Actions in an apply plan.
+const (
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionDelete = "delete"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <file>",
+	Short: "Create or update resources from manifest files",
+	Long: `Bring users, standalone graphs, the ontology and summary instructions in line
+with manifest files. A manifest is a YAML file of one or more documents
+separated by "---", each with a kind:
+
+  User                 user_id, email, first_name, last_name, metadata
+  Graph                graph_id, name, description
+  Ontology             entities and edges, as in 'zepctl ontology set'
+  SummaryInstructions  instructions (list of name and text) and optional
+                       user_ids; without user_ids they are project-wide
+
+apply compares the manifests with the project and only creates or updates
+what differs. Fields left out of a User or Graph document are not changed.
+Running apply again after a failure picks up the remaining changes.
+
+Resources that are not declared are kept unless --prune is given. --prune
+only deletes resources of the kinds the manifests declare: users when there
+is a User document, graphs when there is a Graph document, entity and edge
+types when there is an Ontology document, and summary instructions of the
+users (or the project) that a SummaryInstructions document names.
+
+Use --dry-run to print the plan without changing anything.`,
+	Example: `  zepctl apply -f environment.yaml --dry-run
+  zepctl apply -f users.yaml -f ontology.yaml --prune`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files, _ := cmd.Flags().GetStringArray("file")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		prune, _ := cmd.Flags().GetBool("prune")
+		force, _ := cmd.Flags().GetBool("force")
+
+		if len(files) == 0 {
+			return invalidArgsf("--file is required")
+		}
+
+		m, err := loadManifests(files)
+		if err != nil {
+			return err
+		}
+
+		c, err := client.New()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		a := &applier{c: c, prune: prune}
+		if err := a.plan(ctx, m); err != nil {
+			return err
+		}
+		result := a.result(dryRun)
+		s := result.Summary
+
+		if dryRun {
+			output.Info("Plan: %d to create, %d to update, %d to delete, %d unchanged", s.Create, s.Update, s.Delete, s.Unchanged)
+			return printList(applyColumns, result.Changes, result)
+		}
+
+		if s.Delete > 0 && !force {
+			fmt.Printf("Delete %d resources not declared in the manifests? This cannot be undone. [y/N]: ", s.Delete)
+			reader := bufio.NewReader(os.Stdin)
+			response, _ := reader.ReadString('\n')
+			response = strings.TrimSpace(strings.ToLower(response))
+			if response != "y" && response != "yes" {
+				output.Info("Aborted")
+				return nil
+			}
+		}
+
+		if err := a.run(ctx); err != nil {
+			return err
+		}
+
+		output.Info("Applied: %d created, %d updated, %d deleted, %d unchanged", s.Create, s.Update, s.Delete, s.Unchanged)
+		return printList(applyColumns, result.Changes, result)
+	},
+}
+
+// applyColumns is the column registry for apply plans.
+var applyColumns = output.Columns[*applyChange]{
+	{Name: "KIND", Value: func(c *applyChange) string { return c.Kind }},
+	{Name: "NAME", Value: func(c *applyChange) string {
+		if c.UserID != "" {
+			return fmt.Sprintf("%s (user %s)", c.Name, c.UserID)
+		}
+		return c.Name
+	}},
+	{Name: "ACTION", Value: func(c *applyChange) string { return c.Action }},
+	{Name: "CHANGED FIELDS", Value: func(c *applyChange) string {
+		fields := make([]string, len(c.Fields))
+		for i, f := range c.Fields {
+			fields[i] = f.Field
+		}
+		return strings.Join(fields, ",")
+	}},
+}
+
+// applyResult is the plan of an apply, printed before or after applying it.
+type applyResult struct {
+	DryRun  bool           `json:"dry_run"`
+	Changes []*applyChange `json:"changes"`
+	Summary applySummary   `json:"summary"`
+}
+
+type applySummary struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Delete    int `json:"delete"`
+	Unchanged int `json:"unchanged"`
+}
+
+// applyChange is one resource that apply creates, updates or deletes. Fields
+// holds the declared values of created resources, the changed values of
+// updated ones and the current values of deleted ones.
+type applyChange struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	// UserID is the user of a summary instruction, or empty for project-wide
+	// instructions and other kinds.
+	UserID string        `json:"user_id,omitempty"`
+	Fields []fieldChange `json:"fields,omitempty"`
+}
+
+// manifest holds the resources declared by a set of manifest files.
+type manifest struct {
+	users        []*zep.CreateUserRequest
+	graphs       []*zep.CreateGraphRequest
+	ontology     *OntologyDefinition
+	instructions []*zep.AddUserInstructionsRequest
+
+	// declared records every resource seen, to reject duplicates.
+	declared map[string]bool
+}
+
+// loadManifests reads and validates manifest files; "-" reads standard input.
+func loadManifests(paths []string) (*manifest, error) {
+	m := &manifest{declared: make(map[string]bool)}
+	for _, path := range paths {
+		var data []byte
+		var err error
+		if path == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading manifest: %w", err)
+		}
+		if err := m.parse(path, data); err != nil {
+			return nil, asInvalidArgs(err)
+		}
+	}
+	if len(m.declared) == 0 {
+		return nil, invalidArgsf("no resources declared in %s", strings.Join(paths, ", "))
+	}
+	return m, nil
+}
+
+func (m *manifest) parse(path string, data []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for n := 1; ; n++ {
+		var doc map[string]any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: document %d: %w", path, n, err)
+		}
+		if doc == nil {
+			// An empty document, e.g. after a trailing "---".
+			continue
+		}
+		if err := m.add(doc); err != nil {
+			return fmt.Errorf("%s: document %d: %w", path, n, err)
+		}
+	}
+}
+
+// add decodes a document into the request type of its kind.
+func (m *manifest) add(doc map[string]any) error {
+	kind, _ := doc["kind"].(string)
+	delete(doc, "kind")
+
+	switch kind {
+	case kindUser:
+		var u zep.CreateUserRequest
+		if err := decodeManifest(doc, &u); err != nil {
+			return err
+		}
+		if u.UserID == "" {
+			return errors.New("user_id is required")
+		}
+		if err := m.declare(kindUser, u.UserID); err != nil {
+			return err
+		}
+		m.users = append(m.users, &u)
+	case kindGraph:
+		var g zep.CreateGraphRequest
+		if err := decodeManifest(doc, &g); err != nil {
+			return err
+		}
+		if g.GraphID == "" {
+			return errors.New("graph_id is required")
+		}
+		if err := m.declare(kindGraph, g.GraphID); err != nil {
+			return err
+		}
+		m.graphs = append(m.graphs, &g)
+	case kindOntology:
+		var def OntologyDefinition
+		if err := decodeManifest(doc, &def); err != nil {
+			return err
+		}
+		if err := m.declare(kindOntology, ""); err != nil {
+			return errors.New("only one Ontology document is allowed")
+		}
+		m.ontology = &def
+	case kindSummaryInstructions:
+		var req zep.AddUserInstructionsRequest
+		if err := decodeManifest(doc, &req); err != nil {
+			return err
+		}
+		if len(req.Instructions) == 0 {
+			return errors.New("instructions is required")
+		}
+		scopes := req.UserIDs
+		if len(scopes) == 0 {
+			scopes = []string{""}
+		}
+		for _, inst := range req.Instructions {
+			if inst == nil || inst.Name == "" || inst.Text == "" {
+				return errors.New("every instruction needs a name and text")
+			}
+			if len(inst.Text) > maxInstructionLength {
+				return fmt.Errorf("instruction %q exceeds maximum length of %d characters (got %d)", inst.Name, maxInstructionLength, len(inst.Text))
+			}
+			for _, scope := range scopes {
+				if err := m.declare(kindSummaryInstruction, scope+"/"+inst.Name); err != nil {
+					return fmt.Errorf("summary instruction %q is declared more than once for %s", inst.Name, instructionScope(scope))
+				}
+			}
+		}
+		m.instructions = append(m.instructions, &req)
+	case "":
+		return errors.New("kind is required")
+	default:
+		return fmt.Errorf("unknown kind %q: must be User, Graph, Ontology or SummaryInstructions", kind)
+	}
+	return nil
+}
+
+func (m *manifest) declare(kind, id string) error {
+	key := kind + "/" + id
+	if m.declared[key] {
+		return fmt.Errorf("%s %q is declared more than once", kind, id)
+	}
+	m.declared[key] = true
+	return nil
+}
+
+// decodeManifest decodes a YAML document into v through JSON, so the JSON
+// field names of the API types apply and unknown fields are rejected.
+func decodeManifest(doc map[string]any, v any) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return errors.New(strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return nil
+}
+
+func instructionScope(userID string) string {
+	if userID == "" {
+		return "the project"
+	}
+	return fmt.Sprintf("user %q", userID)
+}
+
+// applier compares manifests with the project and applies the difference.
+type applier struct {
+	c     *client.Client
+	prune bool
+
+	changes   []*applyChange
+	unchanged int
+	// steps make the changes, in the order they are listed.
+	steps []func(context.Context) error
+	// created holds the IDs of the users the plan creates.
+	created map[string]bool
+}
+
+// plan fills in the changes and the steps that make them. Creates and updates
+// come first, in dependency order, followed by deletions of graphs and then
+// users.
+func (a *applier) plan(ctx context.Context, m *manifest) error {
+	if m.ontology != nil {
+		if err := a.planOntology(ctx, m.ontology); err != nil {
+			return err
+		}
+	}
+	if err := a.planUsers(ctx, m.users); err != nil {
+		return err
+	}
+	if err := a.planGraphs(ctx, m.graphs); err != nil {
+		return err
+	}
+	if err := a.planInstructions(ctx, m.instructions); err != nil {
+		return err
+	}
+	if !a.prune {
+		return nil
+	}
+	if len(m.graphs) > 0 {
+		if err := a.pruneGraphs(ctx, m.graphs); err != nil {
+			return err
+		}
+	}
+	if len(m.users) > 0 {
+		if err := a.pruneUsers(ctx, m.users); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add records a change and the step that makes it. Changes made together by
+// a later step pass a nil step.
+func (a *applier) add(change *applyChange, step func(context.Context) error) {
+	a.changes = append(a.changes, change)
+	if step != nil {
+		a.steps = append(a.steps, step)
+	}
+}
+
+func (a *applier) run(ctx context.Context) error {
+	for _, step := range a.steps {
+		if err := step(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *applier) result(dryRun bool) *applyResult {
+	result := &applyResult{DryRun: dryRun, Changes: a.changes, Summary: applySummary{Unchanged: a.unchanged}}
+	if result.Changes == nil {
+		result.Changes = []*applyChange{}
+	}
+	for _, c := range a.changes {
+		switch c.Action {
+		case actionCreate:
+			result.Summary.Create++
+		case actionUpdate:
+			result.Summary.Update++
+		case actionDelete:
+			result.Summary.Delete++
+		}
+	}
+	return result
+}
+
+func (a *applier) planUsers(ctx context.Context, users []*zep.CreateUserRequest) error {
+	a.created = make(map[string]bool)
+	for _, u := range users {
+		current, err := a.c.User.Get(ctx, u.UserID)
+		if isNotFound(err) {
+			fields := presentFields([]fieldChange{
+				{Field: "email", To: stringValue(u.Email)},
+				{Field: "first_name", To: stringValue(u.FirstName)},
+				{Field: "last_name", To: stringValue(u.LastName)},
+				{Field: "metadata", To: attributesString(u.Metadata)},
+			}, true)
+			a.created[u.UserID] = true
+			a.add(&applyChange{Kind: kindUser, Name: u.UserID, Action: actionCreate, Fields: fields}, func(ctx context.Context) error {
+				if _, err := a.c.User.Add(ctx, u); err != nil {
+					return fmt.Errorf("creating user %q: %w", u.UserID, err)
+				}
+				invalidateCache(cacheUserIDs)
+				return nil
+			})
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("getting user %q: %w", u.UserID, err)
+		}
+
+		var fields []fieldChange
+		fields = changedField(fields, "email", stringValue(current.Email), u.Email)
+		fields = changedField(fields, "first_name", stringValue(current.FirstName), u.FirstName)
+		fields = changedField(fields, "last_name", stringValue(current.LastName), u.LastName)
+		if u.Metadata != nil {
+			fields = changedField(fields, "metadata", attributesString(current.Metadata), zep.String(attributesString(u.Metadata)))
+		}
+		if len(fields) == 0 {
+			a.unchanged++
+			continue
+		}
+		a.add(&applyChange{Kind: kindUser, Name: u.UserID, Action: actionUpdate, Fields: fields}, func(ctx context.Context) error {
+			_, err := a.c.User.Update(ctx, u.UserID, &zep.UpdateUserRequest{
+				Email:     u.Email,
+				FirstName: u.FirstName,
+				LastName:  u.LastName,
+				Metadata:  u.Metadata,
+			})
+			if err != nil {
+				return fmt.Errorf("updating user %q: %w", u.UserID, err)
+			}
+			invalidateCache(cacheUsers, u.UserID)
+			invalidateCache(cacheUserIDs)
+			return nil
+		})
+	}
+	return nil
+}
+
+// pruneUsers deletes the users that are not declared.
+func (a *applier) pruneUsers(ctx context.Context, declared []*zep.CreateUserRequest) error {
+	keep := make(map[string]bool, len(declared))
+	for _, u := range declared {
+		keep[u.UserID] = true
+	}
+
+	users, err := pagination.NewPageIterator(1, exportPageSize, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.User, error) {
+		users, err := a.c.User.ListOrdered(ctx, &zep.UserListOrderedRequest{
+			PageNumber: zep.Int(pageNumber),
+			PageSize:   zep.Int(pageSize),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing users: %w", err)
+		}
+		return users.Users, nil
+	}).All(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range users {
+		userID := stringValue(u.UserID)
+		if keep[userID] {
+			continue
+		}
+		a.add(&applyChange{Kind: kindUser, Name: userID, Action: actionDelete}, func(ctx context.Context) error {
+			if _, err := a.c.User.Delete(ctx, userID); err != nil {
+				return fmt.Errorf("deleting user %q: %w", userID, err)
+			}
+			// Deleting a user also deletes its threads.
+			invalidateCache(cacheUsers, userID)
+			invalidateCache(cacheUserIDs)
+			invalidateCache(cacheThreadIDs)
+			return nil
+		})
+	}
+	return nil
+}
+
+func (a *applier) planGraphs(ctx context.Context, graphs []*zep.CreateGraphRequest) error {
+	for _, g := range graphs {
+		current, err := a.c.Graph.Get(ctx, g.GraphID)
+		if isNotFound(err) {
+			fields := presentFields([]fieldChange{
+				{Field: "name", To: stringValue(g.Name)},
+				{Field: "description", To: stringValue(g.Description)},
+			}, true)
+			a.add(&applyChange{Kind: kindGraph, Name: g.GraphID, Action: actionCreate, Fields: fields}, func(ctx context.Context) error {
+				if _, err := a.c.Graph.Create(ctx, g); err != nil {
+					return fmt.Errorf("creating graph %q: %w", g.GraphID, err)
+				}
+				invalidateCache(cacheGraphIDs)
+				return nil
+			})
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("getting graph %q: %w", g.GraphID, err)
+		}
+
+		var fields []fieldChange
+		fields = changedField(fields, "name", stringValue(current.Name), g.Name)
+		fields = changedField(fields, "description", stringValue(current.Description), g.Description)
+		if len(fields) == 0 {
+			a.unchanged++
+			continue
+		}
+		a.add(&applyChange{Kind: kindGraph, Name: g.GraphID, Action: actionUpdate, Fields: fields}, func(ctx context.Context) error {
+			_, err := a.c.Graph.Update(ctx, g.GraphID, &zep.UpdateGraphRequest{
+				Name:        g.Name,
+				Description: g.Description,
+			})
+			if err != nil {
+				return fmt.Errorf("updating graph %q: %w", g.GraphID, err)
+			}
+			invalidateCache(cacheGraphIDs)
+			return nil
+		})
+	}
+	return nil
+}
+
+// pruneGraphs deletes the standalone graphs that are not declared.
+func (a *applier) pruneGraphs(ctx context.Context, declared []*zep.CreateGraphRequest) error {
+	keep := make(map[string]bool, len(declared))
+	for _, g := range declared {
+		keep[g.GraphID] = true
+	}
+
+	graphs, err := pagination.NewPageIterator(1, exportPageSize, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.Graph, error) {
+		graphs, err := a.c.Graph.ListAll(ctx, &zep.GraphListAllRequest{
+			PageNumber: zep.Int(pageNumber),
+			PageSize:   zep.Int(pageSize),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing graphs: %w", err)
+		}
+		return graphs.Graphs, nil
+	}).All(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range graphs {
+		graphID := stringValue(g.GraphID)
+		if keep[graphID] {
+			continue
+		}
+		a.add(&applyChange{Kind: kindGraph, Name: graphID, Action: actionDelete}, func(ctx context.Context) error {
+			if _, err := a.c.Graph.Delete(ctx, graphID); err != nil {
+				return fmt.Errorf("deleting graph %q: %w", graphID, err)
+			}
+			invalidateCache(cacheGraphIDs)
+			return nil
+		})
+	}
+	return nil
+}
+
+// planOntology compares entity and edge types by name. The API replaces the
+// whole ontology at once, so a single step sets the declared types together
+// with the undeclared ones that are kept.
+func (a *applier) planOntology(ctx context.Context, def *OntologyDefinition) error {
+	current, err := a.c.Graph.ListEntityTypes(ctx, &zep.GraphListEntityTypesRequest{})
+	if err != nil {
+		return fmt.Errorf("getting ontology: %w", err)
+	}
+	desired := def.request()
+
+	entityTypes, entitiesChanged := planTypes(a, kindEntityType, current.EntityTypes, desired.EntityTypes, entityTypeItem)
+	edgeTypes, edgesChanged := planTypes(a, kindEdgeType, current.EdgeTypes, desired.EdgeTypes, edgeTypeItem)
+	if !entitiesChanged && !edgesChanged {
+		return nil
+	}
+
+	a.steps = append(a.steps, func(ctx context.Context) error {
+		_, err := a.c.Graph.SetEntityTypesInternal(ctx, &zep.EntityTypeRequest{
+			EntityTypes: entityTypes,
+			EdgeTypes:   edgeTypes,
+		})
+		if err != nil {
+			return fmt.Errorf("setting ontology: %w", err)
+		}
+		invalidateCache(cacheOntology)
+		return nil
+	})
+	return nil
+}
+
+// planTypes records the changes between the current and declared entity or
+// edge types. It returns the types to set, which are the declared ones plus,
+// unless pruning, the current ones that are not declared, and whether any
+// type changed.
+func planTypes[T any](a *applier, kind string, current, desired []T, item func(T) diffItem) ([]T, bool) {
+	index := func(types []T) ([]diffItem, map[string]T) {
+		items := make([]diffItem, len(types))
+		byName := make(map[string]T, len(types))
+		for i, t := range types {
+			items[i] = item(t)
+			byName[items[i].key] = t
+		}
+		slices.SortFunc(items, func(a, b diffItem) int { return strings.Compare(a.key, b.key) })
+		return items, byName
+	}
+	from, currentTypes := index(current)
+	to, _ := index(desired)
+
+	types := slices.Clone(desired)
+	declaredChanges, deleted := 0, 0
+	for _, c := range diffItems(kind, from, to) {
+		change := &applyChange{Kind: kind, Name: c.Name, Fields: c.Fields}
+		switch c.Change {
+		case diffAdded:
+			change.Action = actionCreate
+			declaredChanges++
+		case diffChanged:
+			change.Action = actionUpdate
+			declaredChanges++
+		case diffRemoved:
+			if !a.prune {
+				types = append(types, currentTypes[c.Name])
+				continue
+			}
+			change.Action = actionDelete
+			deleted++
+		}
+		a.add(change, nil)
+	}
+	a.unchanged += len(to) - declaredChanges
+	return types, declaredChanges+deleted > 0
+}
+
+func entityTypeItem(t *zep.EntityType) diffItem {
+	return diffItem{
+		key:  t.Name,
+		name: t.Name,
+		fields: []fieldChange{
+			{Field: "description", To: t.Description},
+			{Field: "properties", To: propertiesString(t.Properties)},
+		},
+	}
+}
+
+func edgeTypeItem(t *zep.EdgeType) diffItem {
+	sourceTargets := make([]string, len(t.SourceTargets))
+	for i, st := range t.SourceTargets {
+		sourceTargets[i] = stringValue(st.Source) + "->" + stringValue(st.Target)
+	}
+	slices.Sort(sourceTargets)
+	return diffItem{
+		key:  t.Name,
+		name: t.Name,
+		fields: []fieldChange{
+			{Field: "description", To: t.Description},
+			{Field: "properties", To: propertiesString(t.Properties)},
+			{Field: "source_targets", To: strings.Join(sourceTargets, ",")},
+		},
+	}
+}
+
+// propertiesString formats properties for comparison, sorted by name.
+func propertiesString(props []*zep.EntityProperty) string {
+	parts := make([]string, len(props))
+	for i, p := range props {
+		parts[i] = fmt.Sprintf("%s %s %q", p.Name, p.Type, p.Description)
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, ", ")
+}
+
+// changedField appends the change of a declared field. Fields left out of a
+// manifest are nil and never change.
+func changedField(fields []fieldChange, name, current string, desired *string) []fieldChange {
+	if desired == nil || *desired == current {
+		return fields
+	}
+	return append(fields, fieldChange{Field: name, From: current, To: *desired})
+}
+
+// planInstructions compares summary instructions by name, separately for each
+// user and the project-wide scope, and adds or deletes the instructions of a
+// scope in one request.
+func (a *applier) planInstructions(ctx context.Context, reqs []*zep.AddUserInstructionsRequest) error {
+	// Declared instruction texts by name, per user ID or "" for project-wide.
+	declared := make(map[string]map[string]string)
+	for _, req := range reqs {
+		scopes := req.UserIDs
+		if len(scopes) == 0 {
+			scopes = []string{""}
+		}
+		for _, scope := range scopes {
+			if declared[scope] == nil {
+				declared[scope] = make(map[string]string)
+			}
+			for _, inst := range req.Instructions {
+				declared[scope][inst.Name] = inst.Text
+			}
+		}
+	}
+
+	for _, scope := range slices.Sorted(maps.Keys(declared)) {
+		// A user the plan creates has no instructions yet, and listing them
+		// fails until the user exists.
+		current := make(map[string]string)
+		if !a.created[scope] {
+			req := &zep.UserListUserSummaryInstructionsRequest{}
+			if scope != "" {
+				req.UserID = zep.String(scope)
+			}
+			existing, err := a.c.User.ListUserSummaryInstructions(ctx, req)
+			if err != nil {
+				return fmt.Errorf("listing summary instructions: %w", err)
+			}
+			for _, inst := range existing.Instructions {
+				current[inst.Name] = inst.Text
+			}
+		}
+
+		var userIDs []string
+		if scope != "" {
+			userIDs = []string{scope}
+		}
+
+		var add []*zep.UserInstruction
+		for _, name := range slices.Sorted(maps.Keys(declared[scope])) {
+			text := declared[scope][name]
+			change := &applyChange{Kind: kindSummaryInstruction, Name: name, UserID: scope}
+			from, ok := current[name]
+			switch {
+			case !ok:
+				change.Action = actionCreate
+				change.Fields = []fieldChange{{Field: "text", To: text}}
+			case from != text:
+				change.Action = actionUpdate
+				change.Fields = []fieldChange{{Field: "text", From: from, To: text}}
+			default:
+				a.unchanged++
+				continue
+			}
+			a.add(change, nil)
+			add = append(add, &zep.UserInstruction{Name: name, Text: text})
+		}
+		if len(add) > 0 {
+			a.steps = append(a.steps, func(ctx context.Context) error {
+				_, err := a.c.User.AddUserSummaryInstructions(ctx, &zep.AddUserInstructionsRequest{
+					Instructions: add,
+					UserIDs:      userIDs,
+				})
+				if err != nil {
+					return fmt.Errorf("adding summary instructions for %s: %w", instructionScope(scope), err)
+				}
+				return nil
+			})
+		}
+
+		if !a.prune {
+			continue
+		}
+		var remove []string
+		for _, name := range slices.Sorted(maps.Keys(current)) {
+			if _, ok := declared[scope][name]; ok {
+				continue
+			}
+			a.add(&applyChange{
+				Kind:   kindSummaryInstruction,
+				Name:   name,
+				Action: actionDelete,
+				UserID: scope,
+				Fields: []fieldChange{{Field: "text", From: current[name]}},
+			}, nil)
+			remove = append(remove, name)
+		}
+		if len(remove) > 0 {
+			a.steps = append(a.steps, func(ctx context.Context) error {
+				_, err := a.c.User.DeleteUserSummaryInstructions(ctx, &zep.DeleteUserInstructionsRequest{
+					InstructionNames: remove,
+					UserIDs:          userIDs,
+				})
+				if err != nil {
+					return fmt.Errorf("deleting summary instructions for %s: %w", instructionScope(scope), err)
+				}
+				return nil
+			})
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().StringArrayP("file", "f", nil, "Manifest file to apply, or - for stdin (repeatable)")
+	applyCmd.Flags().Bool("dry-run", false, "Print the plan without changing anything")
+	applyCmd.Flags().Bool("prune", false, "Delete resources of the declared kinds that are not declared")
+	applyCmd.Flags().Bool("force", false, "Skip the confirmation prompt before deleting")
+}
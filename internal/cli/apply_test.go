@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const applyManifest = `kind: User
+user_id: alice
+email: alice@example.com
+metadata:
+  plan: pro
+---
+kind: Graph
+graph_id: kb
+name: Knowledge base
+---
+kind: Ontology
+entities:
+  Customer:
+    description: A customer
+    fields:
+      tier:
+        description: Customer tier
+edges:
+  PURCHASED:
+    description: Customer bought a product
+    source_types: [Customer]
+    target_types: [Customer]
+---
+kind: SummaryInstructions
+user_ids: [alice]
+instructions:
+  - name: tone
+    text: Be brief
+`
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApply(t *testing.T) {
+	server := newSandbox(t)
+	path := writeManifest(t, applyManifest)
+
+	apply := func(args ...string) *applyResult {
+		t.Helper()
+		out, err := runCLI(t, server, append([]string{"apply", "-f", path, "-o", "json"}, args...)...)
+		if err != nil {
+			t.Fatalf("apply %v: %v", args, err)
+		}
+		var result applyResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("parsing apply output %q: %v", out, err)
+		}
+		return &result
+	}
+
+	if _, err := runCLI(t, server, "user", "create", "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := apply("--dry-run").Summary, (applySummary{Create: 5}); got != want {
+		t.Errorf("dry run summary = %+v, want %+v", got, want)
+	}
+	if _, err := runCLI(t, server, "user", "get", "alice"); ExitCode(err) != ExitNotFound {
+		t.Fatalf("dry run created user alice (err: %v)", err)
+	}
+
+	if got, want := apply().Summary, (applySummary{Create: 5}); got != want {
+		t.Errorf("apply summary = %+v, want %+v", got, want)
+	}
+	if got, want := apply().Summary, (applySummary{Unchanged: 5}); got != want {
+		t.Errorf("second apply summary = %+v, want %+v", got, want)
+	}
+
+	updated := strings.Replace(applyManifest, "plan: pro", "plan: team", 1)
+	if err := os.WriteFile(path, []byte(updated), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	result := apply("--prune", "--force")
+	if got, want := result.Summary, (applySummary{Update: 1, Delete: 1, Unchanged: 4}); got != want {
+		t.Errorf("prune summary = %+v, want %+v", got, want)
+	}
+	for _, c := range result.Changes {
+		switch {
+		case c.Kind == kindUser && c.Name == "alice":
+			if c.Action != actionUpdate || len(c.Fields) != 1 || c.Fields[0].Field != "metadata" {
+				t.Errorf("alice change = %+v, want metadata update", c)
+			}
+		case c.Kind == kindUser && c.Name == "bob":
+			if c.Action != actionDelete {
+				t.Errorf("bob change = %+v, want delete", c)
+			}
+		default:
+			t.Errorf("unexpected change %+v", c)
+		}
+	}
+
+	out, err := runCLI(t, server, "user", "get", "alice", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `"plan": "team"`) {
+		t.Errorf("alice was not updated:\n%s", out)
+	}
+	if _, err := runCLI(t, server, "user", "get", "bob"); ExitCode(err) != ExitNotFound {
+		t.Errorf("bob was not pruned (err: %v)", err)
+	}
+}
+
+func TestApplyInvalidManifest(t *testing.T) {
+	server := newSandbox(t)
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{"missing kind", "user_id: alice\n", "kind is required"},
+		{"unknown kind", "kind: Thread\n", `unknown kind "Thread"`},
+		{"unknown field", "kind: User\nuser_id: alice\nemial: a@example.com\n", `unknown field "emial"`},
+		{"missing user ID", "kind: User\nemail: a@example.com\n", "user_id is required"},
+		{"duplicate user", "kind: User\nuser_id: alice\n---\nkind: User\nuser_id: alice\n", "declared more than once"},
+		{"long instruction", "kind: SummaryInstructions\ninstructions:\n  - name: tone\n    text: " + strings.Repeat("x", maxInstructionLength+1) + "\n", "exceeds maximum length"},
+		{"empty", "---\n", "no resources declared"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runCLI(t, server, "apply", "-f", writeManifest(t, tt.manifest))
+			if ExitCode(err) != ExitInvalidArgs {
+				t.Fatalf("exit code %d, want %d (err: %v)", ExitCode(err), ExitInvalidArgs, err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getzep/zepctl/internal/cache"
+	"github.com/getzep/zepctl/internal/config"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Cached resource types and how long their entries are reused.
+const (
+	cacheProject   = "project"
+	cacheOntology  = "ontology"
+	cacheUsers     = "users"
+	cacheNodeNames = "node-names"
+	cacheUserIDs   = "user-ids"
+	cacheThreadIDs = "thread-ids"
+	cacheGraphIDs  = "graph-ids"
+
+	projectCacheTTL  = 10 * time.Minute
+	ontologyCacheTTL = 5 * time.Minute
+	userCacheTTL     = time.Minute
+	nodeNameCacheTTL = 10 * time.Minute
+
+	// idCacheTTL is short since completions should offer new resources soon
+	// after they are created elsewhere.
+	idCacheTTL = 30 * time.Second
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local response cache",
+	Long: `zepctl caches project info, ontology definitions, user lookups, node names
+and the IDs offered by shell completions under ~/.zepctl/cache, separately for
+each profile and API URL. Entries expire after a few seconds to a few minutes,
+and commands that change a cached resource remove its entries.
+
+Pass --no-cache to any command to bypass the cache.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached response",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cache.Clear(); err != nil {
+			return err
+		}
+		output.Info("Cleared cache")
+		return nil
+	},
+}
+
+// apiKeyScope identifies the API key in cache scopes, so overriding the key
+// of a profile with --api-key does not reuse another project's entries. It
+// is computed once per process, since reading the key may query the system
+// keychain.
+var apiKeyScope = sync.OnceValue(func() string {
+	sum := sha256.Sum256([]byte(config.GetAPIKey()))
+	return hex.EncodeToString(sum[:])
+})
+
+// responseCache returns the cache for the project zepctl is talking to, or
+// nil in sandbox mode, whose state is local and cheap to query.
+func responseCache() *cache.Cache {
+	if config.IsSandbox() {
+		return nil
+	}
+	scope := strings.Join([]string{config.GetProfileName(), config.GetAPIURL(), apiKeyScope()}, "\n")
+	rc, err := cache.Open(scope)
+	if err != nil {
+		return nil
+	}
+	return rc
+}
+
+// cached returns the cached value of a resource, or calls fetch and caches
+// its result for ttl. With --no-cache the cache is neither read nor written.
+func cached[T any](resource, id string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	if viper.GetBool("no-cache") {
+		return fetch()
+	}
+
+	rc := responseCache()
+	var v T
+	if rc.Get(resource, id, &v) {
+		return v, nil
+	}
+	v, err := fetch()
+	if err != nil {
+		return v, err
+	}
+	// A failed write only means the next lookup calls the API again.
+	_ = rc.Put(resource, id, v, ttl)
+	return v, nil
+}
+
+// invalidateCache removes cached entries of a resource after a command
+// changed it, or every entry of the resource if no IDs are given. It runs
+// even with --no-cache so later cached lookups are not stale.
+func invalidateCache(resource string, ids ...string) {
+	_ = responseCache().Invalidate(resource, ids...)
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/pagination"
+)
+
+// printList writes one page of items. Tabular formats render the resource's
+// columns and NDJSON writes one line per item; other formats print data, the
+// response the items came from, after sorting the items in place.
+func printList[T any](cols output.Columns[T], items []T, data any) error {
+	if output.IsTabular() {
+		return cols.Render(items)
+	}
+	if err := cols.Sort(items); err != nil {
+		return err
+	}
+	if output.GetFormat() == output.FormatNDJSON {
+		return output.Print(items)
+	}
+	return output.Print(data)
+}
+
+// stringValue returns the value of an optional string field, or "".
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// boolValue formats an optional bool field, or returns "" if unset.
+func boolValue(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+// mapKeys lists the keys of a metadata or attributes map, sorted and
+// comma-separated.
+func mapKeys(m map[string]any) string {
+	return strings.Join(slices.Sorted(maps.Keys(m)), ",")
+}
+
+// maxNodeNameLookup is the most nodes listed to resolve the names of the edge
+// SOURCE and TARGET columns for the edges of a graph.
+const maxNodeNameLookup = 1000
+
+// nodeNames resolves node UUIDs to names for the edge SOURCE and TARGET
+// columns. For the edges of a known graph, the graph's nodes are listed once,
+// up to maxNodeNameLookup; otherwise each node is fetched at most once and
+// its name is cached on disk. Nodes that cannot be resolved are shown by
+// UUID.
+type nodeNames struct {
+	c     *client.Client
+	names map[string]string
+	// list lists the nodes of the graph the edges belong to, or is nil if
+	// the graph is not known.
+	list func(ctx context.Context) ([]*zep.EntityNode, error)
+	// listed is set once list was called, and partial if the graph has more
+	// nodes than were listed.
+	listed, partial bool
+}
+
+func newNodeNames(c *client.Client) *nodeNames {
+	return &nodeNames{c: c, names: make(map[string]string)}
+}
+
+// newGraphNodeNames returns a nodeNames for the edges of a user graph or
+// standalone graph.
+func newGraphNodeNames(c *client.Client, userID, graphID string) *nodeNames {
+	n := newNodeNames(c)
+	n.list = func(ctx context.Context) ([]*zep.EntityNode, error) {
+		it := pagination.NewCursorIterator("", exportPageSize, nodePageFetcher(c, userID, graphID), func(node *zep.EntityNode) string {
+			return node.UUID
+		})
+		return it.WithMaxItems(maxNodeNameLookup + 1).All(ctx)
+	}
+	return n
+}
+
+func (n *nodeNames) name(uuid string) string {
+	if uuid == "" {
+		return ""
+	}
+	if n.list != nil && !n.listed {
+		n.listGraph()
+	}
+	if name, ok := n.names[uuid]; ok {
+		return name
+	}
+	if n.partial {
+		n.names[uuid] = uuid
+		return uuid
+	}
+	// A node missing from a complete listing was added since, e.g. while
+	// watching.
+	name, err := cached(cacheNodeNames, uuid, nodeNameCacheTTL, func() (string, error) {
+		node, err := n.c.Graph.Node.Get(context.Background(), uuid)
+		if err != nil {
+			return "", err
+		}
+		return node.Name, nil
+	})
+	if err != nil || name == "" {
+		name = uuid
+	}
+	n.names[uuid] = name
+	return name
+}
+
+// listGraph resolves the names of the graph's nodes with one listing. If it
+// fails, or the graph has more than maxNodeNameLookup nodes, the nodes that
+// were not listed are shown by UUID.
+func (n *nodeNames) listGraph() {
+	n.listed = true
+	nodes, err := n.list(context.Background())
+	if err != nil {
+		output.Warn("could not list nodes for the SOURCE and TARGET columns: %v", err)
+		n.partial = true
+		return
+	}
+	if len(nodes) > maxNodeNameLookup {
+		output.Warn("graph has more than %d nodes; SOURCE and TARGET show the UUIDs of the others", maxNodeNameLookup)
+		nodes = nodes[:maxNodeNameLookup]
+		n.partial = true
+	}
+	for _, node := range nodes {
+		name := node.Name
+		if name == "" {
+			name = node.UUID
+		}
+		n.names[node.UUID] = name
+	}
+}
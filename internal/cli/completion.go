@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/config"
+	"github.com/getzep/zepctl/internal/pagination"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// completionTimeout bounds the API calls made while completing.
+	completionTimeout = 5 * time.Second
+
+	// completionMaxItems caps the IDs fetched for completion.
+	completionMaxItems = 1000
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for bash, zsh, fish or PowerShell.
+
+Besides commands and flags, the script completes user IDs, thread IDs, graph
+IDs and profile names, both as arguments and as values of flags such as
+--user and --graph. IDs are fetched from the API and cached for 30 seconds
+(see zepctl cache).
+
+  bash:        source <(zepctl completion bash)
+  zsh:         source <(zepctl completion zsh)
+  fish:        zepctl completion fish | source
+  powershell:  zepctl completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			err = rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+		if err != nil {
+			return fmt.Errorf("generating %s completion: %w", args[0], err)
+		}
+		return nil
+	},
+}
+
+// completer completes a flag value or positional argument.
+type completer func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// flagCompleters maps flag names to the completer for their values. They
+// apply to every command with a flag of that name.
+var flagCompleters = map[string]completer{
+	"user":         completeUserIDs,
+	"source-user":  completeUserIDs,
+	"target-user":  completeUserIDs,
+	"thread":       completeThreadIDs,
+	"graph":        completeGraphIDs,
+	"source-graph": completeGraphIDs,
+	"target-graph": completeGraphIDs,
+}
+
+// registerFlagCompletions registers the completers in flagCompleters on cmd
+// and its subcommands, and the profile completer on --profile.
+func registerFlagCompletions(cmd *cobra.Command) {
+	if cmd == rootCmd {
+		// Registering twice fails, which is expected when Execute is called
+		// more than once in tests.
+		_ = cmd.RegisterFlagCompletionFunc("profile", completeProfileNames)
+	}
+	for name, complete := range flagCompleters {
+		if cmd.LocalNonPersistentFlags().Lookup(name) != nil {
+			_ = cmd.RegisterFlagCompletionFunc(name, complete)
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		registerFlagCompletions(sub)
+	}
+}
+
+// firstArg restricts a completer to the first positional argument.
+func firstArg(complete completer) completer {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+func completeUserIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeIDs(cacheUserIDs, toComplete, func(ctx context.Context, c *client.Client) ([]string, error) {
+		it := pagination.NewPageIterator(1, exportPageSize, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.User, error) {
+			users, err := c.User.ListOrdered(ctx, &zep.UserListOrderedRequest{
+				PageNumber: zep.Int(pageNumber),
+				PageSize:   zep.Int(pageSize),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("listing users: %w", err)
+			}
+			return users.Users, nil
+		}).WithMaxItems(completionMaxItems)
+		users, err := it.All(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, 0, len(users))
+		for _, u := range users {
+			name := strings.TrimSpace(stringValue(u.FirstName) + " " + stringValue(u.LastName))
+			if name == "" {
+				name = stringValue(u.Email)
+			}
+			ids = append(ids, completion(stringValue(u.UserID), name))
+		}
+		return ids, nil
+	})
+}
+
+func completeThreadIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeIDs(cacheThreadIDs, toComplete, func(ctx context.Context, c *client.Client) ([]string, error) {
+		it := pagination.NewPageIterator(1, exportPageSize, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.Thread, error) {
+			threads, err := c.Thread.ListAll(ctx, &zep.ThreadListAllRequest{
+				PageNumber: zep.Int(pageNumber),
+				PageSize:   zep.Int(pageSize),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("listing threads: %w", err)
+			}
+			return threads.Threads, nil
+		}).WithMaxItems(completionMaxItems)
+		threads, err := it.All(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, 0, len(threads))
+		for _, t := range threads {
+			desc := ""
+			if userID := stringValue(t.UserID); userID != "" {
+				desc = "user " + userID
+			}
+			ids = append(ids, completion(stringValue(t.ThreadID), desc))
+		}
+		return ids, nil
+	})
+}
+
+func completeGraphIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeIDs(cacheGraphIDs, toComplete, func(ctx context.Context, c *client.Client) ([]string, error) {
+		it := pagination.NewPageIterator(1, exportPageSize, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.Graph, error) {
+			graphs, err := c.Graph.ListAll(ctx, &zep.GraphListAllRequest{
+				PageNumber: zep.Int(pageNumber),
+				PageSize:   zep.Int(pageSize),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("listing graphs: %w", err)
+			}
+			return graphs.Graphs, nil
+		}).WithMaxItems(completionMaxItems)
+		graphs, err := it.All(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, 0, len(graphs))
+		for _, g := range graphs {
+			ids = append(ids, completion(stringValue(g.GraphID), stringValue(g.Name)))
+		}
+		return ids, nil
+	})
+}
+
+// completeProfileNames completes profile names from the config file, which
+// is local and needs no caching.
+func completeProfileNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load()
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, p := range cfg.Profiles {
+		desc := p.APIURL
+		if p.Type == config.ProfileTypeSandbox {
+			desc = "sandbox"
+		}
+		names = append(names, completion(p.Name, desc))
+	}
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeIDs completes the IDs of one resource type, which are cached for
+// idCacheTTL so pressing tab repeatedly does not wait for the API every
+// time. Failures are only logged to the completion debug log, since anything
+// printed would end up on the user's command line.
+func completeIDs(resource, toComplete string, fetch func(context.Context, *client.Client) ([]string, error)) ([]string, cobra.ShellCompDirective) {
+	ids, err := cached(resource, "", idCacheTTL, func() ([]string, error) {
+		c, err := client.New()
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+		defer cancel()
+		return fetch(ctx, c)
+	})
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterCompletions(ids, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completion formats a completion with an optional description, which shells
+// that support it show next to the value.
+func completion(value, desc string) string {
+	if desc == "" {
+		return value
+	}
+	return value + "\t" + desc
+}
+
+func filterCompletions(completions []string, toComplete string) []string {
+	var matches []string
+	for _, c := range completions {
+		if strings.HasPrefix(c, toComplete) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	server := newSandbox(t)
+	setup := [][]string{
+		{"user", "create", "alice", "--first-name", "Alice"},
+		{"user", "create", "bob"},
+		{"thread", "create", "t1", "--user", "alice"},
+		{"graph", "create", "g1"},
+	}
+	for _, args := range setup {
+		if _, err := runCLI(t, server, args...); err != nil {
+			t.Fatalf("zepctl %v: %v", args, err)
+		}
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"user", "get", ""}, []string{"alice\tAlice", "bob"}},
+		{[]string{"user", "get", "a"}, []string{"alice\tAlice"}},
+		{[]string{"user", "get", "alice", ""}, nil},
+		{[]string{"thread", "delete", ""}, []string{"t1\tuser alice"}},
+		{[]string{"graph", "delete", ""}, []string{"g1"}},
+		{[]string{"node", "list", "--user", "b"}, []string{"bob"}},
+		{[]string{"graph", "diff", "--source-graph", ""}, []string{"g1"}},
+		{[]string{"completion", ""}, []string{"bash", "zsh", "fish", "powershell"}},
+	}
+	for _, tt := range tests {
+		out, err := runCLI(t, server, append([]string{"__complete"}, tt.args...)...)
+		if err != nil {
+			t.Fatalf("zepctl __complete %v: %v", tt.args, err)
+		}
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if got := lines[:len(lines)-1]; !slices.Equal(got, tt.want) {
+			t.Errorf("completing %q = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+
+	// IDs are cached, so a user created behind zepctl's back is not offered
+	// until the cache expires or is bypassed.
+	resp, err := http.Post(server.URL()+"/users", "application/json", strings.NewReader(`{"user_id":"carol"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("creating carol: %s", resp.Status)
+	}
+	out, err := runCLI(t, server, "__complete", "user", "delete", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "carol") {
+		t.Errorf("completion was not cached:\n%s", out)
+	}
+	out, err = runCLI(t, server, "__complete", "user", "delete", "--no-cache", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "carol") {
+		t.Errorf("--no-cache completion is missing carol:\n%s", out)
+	}
+
+	// Creating a user with zepctl invalidates the cached IDs.
+	if _, err := runCLI(t, server, "user", "create", "dave"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runCLI(t, server, "__complete", "user", "delete", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "dave") {
+		t.Errorf("completion cache was not invalidated:\n%s", out)
+	}
+}
@@ -41,15 +41,19 @@ var configGetProfilesCmd = &cobra.Command{
 			return fmt.Errorf("loading config: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("NAME", "API URL", "CURRENT")
+		if output.IsTabular() {
+			tbl := output.NewTable("NAME", "TYPE", "API URL", "CURRENT")
 			tbl.WriteHeader()
 			for _, p := range cfg.Profiles {
 				current := ""
 				if p.Name == cfg.CurrentProfile {
 					current = "*"
 				}
-				tbl.WriteRow(p.Name, p.APIURL, current)
+				profileType := p.Type
+				if profileType == "" {
+					profileType = "api"
+				}
+				tbl.WriteRow(p.Name, profileType, p.APIURL, current)
 			}
 			return tbl.Flush()
 		}
@@ -59,9 +63,10 @@ var configGetProfilesCmd = &cobra.Command{
 }
 
 var configUseProfileCmd = &cobra.Command{
-	Use:   "use-profile <name>",
-	Short: "Switch active profile",
-	Args:  cobra.ExactArgs(1),
+	Use:               "use-profile <name>",
+	Short:             "Switch active profile",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeProfileNames),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 
@@ -71,7 +76,7 @@ var configUseProfileCmd = &cobra.Command{
 		}
 
 		if cfg.GetProfile(name) == nil {
-			return fmt.Errorf("profile %q not found", name)
+			return notFoundf("profile", name, "profile %q not found", name)
 		}
 
 		cfg.CurrentProfile = name
@@ -102,6 +107,26 @@ var configAddProfileCmd = &cobra.Command{
 
 		apiKey, _ := cmd.Flags().GetString("api-key")
 		apiURL, _ := cmd.Flags().GetString("api-url")
+		sandbox, _ := cmd.Flags().GetBool("sandbox")
+		sandboxState, _ := cmd.Flags().GetString("sandbox-state")
+
+		if sandbox {
+			// Sandbox profiles need no API key
+			cfg.Profiles = append(cfg.Profiles, config.Profile{
+				Name:         name,
+				Type:         config.ProfileTypeSandbox,
+				SandboxState: sandboxState,
+			})
+			if cfg.CurrentProfile == "" {
+				cfg.CurrentProfile = name
+			}
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("saving config: %w", err)
+			}
+
+			output.Info("Added sandbox profile %q", name)
+			return nil
+		}
 
 		if apiKey == "" {
 			fmt.Print("API Key: ")
@@ -121,7 +146,7 @@ var configAddProfileCmd = &cobra.Command{
 		}
 
 		if apiKey == "" {
-			return fmt.Errorf("API key cannot be empty")
+			return invalidArgsf("API key cannot be empty")
 		}
 
 		// Store API key in system keychain
@@ -149,9 +174,10 @@ var configAddProfileCmd = &cobra.Command{
 }
 
 var configDeleteProfileCmd = &cobra.Command{
-	Use:   "delete-profile <name>",
-	Short: "Remove a profile",
-	Args:  cobra.ExactArgs(1),
+	Use:               "delete-profile <name>",
+	Short:             "Remove a profile",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeProfileNames),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 		force, _ := cmd.Flags().GetBool("force")
@@ -161,9 +187,11 @@ var configDeleteProfileCmd = &cobra.Command{
 			return fmt.Errorf("loading config: %w", err)
 		}
 
-		if cfg.GetProfile(name) == nil {
-			return fmt.Errorf("profile %q not found", name)
+		profile := cfg.GetProfile(name)
+		if profile == nil {
+			return notFoundf("profile", name, "profile %q not found", name)
 		}
+		isSandbox := profile.Type == config.ProfileTypeSandbox
 
 		if !force {
 			fmt.Printf("Delete profile %q? [y/N]: ", name)
@@ -195,9 +223,12 @@ var configDeleteProfileCmd = &cobra.Command{
 			return fmt.Errorf("saving config: %w", err)
 		}
 
-		// Remove API key from keychain (best-effort, after config is saved)
-		if err := keyring.Delete(name); err != nil {
-			output.Warn("Could not remove API key from keychain: %v", err)
+		// Remove API key from keychain (best-effort, after config is saved).
+		// Sandbox profiles have no API key.
+		if !isSandbox {
+			if err := keyring.Delete(name); err != nil {
+				output.Warn("Could not remove API key from keychain: %v", err)
+			}
 		}
 
 		output.Info("Deleted profile %q", name)
@@ -215,5 +246,7 @@ func init() {
 
 	configAddProfileCmd.Flags().String("api-key", "", "API key for the profile")
 	configAddProfileCmd.Flags().String("api-url", "", "API URL for the profile (uses SDK default if not set)")
+	configAddProfileCmd.Flags().Bool("sandbox", false, "Create a sandbox profile backed by the local Zep emulator")
+	configAddProfileCmd.Flags().String("sandbox-state", "", "File to persist the sandbox profile's state to")
 	configDeleteProfileCmd.Flags().Bool("force", false, "Skip confirmation prompt")
 }
@@ -5,11 +5,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/getzep/zep-go/v3"
 	"github.com/getzep/zepctl/internal/client"
 	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/pagination"
 	"github.com/spf13/cobra"
 )
 
@@ -27,7 +29,11 @@ var edgeListCmd = &cobra.Command{
 		graphID, _ := cmd.Flags().GetString("graph")
 
 		if userID == "" && graphID == "" {
-			return fmt.Errorf("either --user or --graph is required")
+			return invalidArgsf("either --user or --graph is required")
+		}
+		watch, interval, err := wantsWatch(cmd)
+		if err != nil {
+			return err
 		}
 
 		c, err := client.New()
@@ -35,11 +41,59 @@ var edgeListCmd = &cobra.Command{
 			return err
 		}
 
-		var edges []*zep.EntityEdge
-
 		limit, _ := cmd.Flags().GetInt("limit")
 		cursor, _ := cmd.Flags().GetString("cursor")
+		fetch := edgePageFetcher(c, userID, graphID)
+		cols := edgeColumns(newGraphNodeNames(c, userID, graphID))
+		pages := func(maxItems int) *pagination.Iterator[*zep.EntityEdge] {
+			return pagination.NewCursorIterator(cursor, limit, fetch, func(e *zep.EntityEdge) string {
+				return e.UUID
+			}).WithMaxItems(maxItems)
+		}
+
+		if watch {
+			_, maxItems := wantsAllPages(cmd)
+			return watchList(context.Background(), interval, cols, func(ctx context.Context) ([]*zep.EntityEdge, error) {
+				return pages(maxItems).All(ctx)
+			}, func(e *zep.EntityEdge) string { return e.UUID })
+		}
 
+		if paginate, maxItems := wantsAllPages(cmd); paginate {
+			return printPages(pages(maxItems), cols)
+		}
+
+		edges, err := fetch(context.Background(), cursor, limit)
+		if err != nil {
+			return err
+		}
+
+		return printList(cols, edges, edges)
+	},
+}
+
+// edgeColumns returns the column registry for graph edges. The wide SOURCE
+// and TARGET columns look up node names with nodes.
+func edgeColumns(nodes *nodeNames) output.Columns[*zep.EntityEdge] {
+	return output.Columns[*zep.EntityEdge]{
+		{Name: "UUID", Value: func(e *zep.EntityEdge) string { return e.UUID }},
+		{Name: "NAME", Value: func(e *zep.EntityEdge) string { return e.Name }},
+		{Name: "FACT", Value: func(e *zep.EntityEdge) string { return output.Truncate(e.Fact, 40) }},
+		{Name: "VALID AT", Value: func(e *zep.EntityEdge) string { return stringValue(e.ValidAt) }},
+		{Name: "INVALID AT", Value: func(e *zep.EntityEdge) string { return stringValue(e.InvalidAt) }},
+		{Name: "SOURCE", Wide: true, Value: func(e *zep.EntityEdge) string { return nodes.name(e.SourceNodeUUID) }},
+		{Name: "TARGET", Wide: true, Value: func(e *zep.EntityEdge) string { return nodes.name(e.TargetNodeUUID) }},
+		{Name: "SOURCE UUID", Wide: true, Value: func(e *zep.EntityEdge) string { return e.SourceNodeUUID }},
+		{Name: "TARGET UUID", Wide: true, Value: func(e *zep.EntityEdge) string { return e.TargetNodeUUID }},
+		{Name: "EPISODES", Wide: true, Value: func(e *zep.EntityEdge) string { return strconv.Itoa(len(e.Episodes)) }},
+		{Name: "EXPIRED AT", Wide: true, Value: func(e *zep.EntityEdge) string { return stringValue(e.ExpiredAt) }},
+		{Name: "CREATED AT", Wide: true, Value: func(e *zep.EntityEdge) string { return e.CreatedAt }},
+	}
+}
+
+// edgePageFetcher returns a function that fetches one page of edges from a
+// user graph or standalone graph, starting after the given UUID cursor.
+func edgePageFetcher(c *client.Client, userID, graphID string) func(ctx context.Context, cursor string, limit int) ([]*zep.EntityEdge, error) {
+	return func(ctx context.Context, cursor string, limit int) ([]*zep.EntityEdge, error) {
 		req := &zep.GraphEdgesRequest{}
 		if limit > 0 {
 			req.Limit = zep.Int(limit)
@@ -48,43 +102,18 @@ var edgeListCmd = &cobra.Command{
 			req.UUIDCursor = zep.String(cursor)
 		}
 
+		var edges []*zep.EntityEdge
+		var err error
 		if userID != "" {
-			result, err := c.Graph.Edge.GetByUserID(context.Background(), userID, req)
-			if err != nil {
-				return fmt.Errorf("listing edges: %w", err)
-			}
-			edges = result
+			edges, err = c.Graph.Edge.GetByUserID(ctx, userID, req)
 		} else {
-			result, err := c.Graph.Edge.GetByGraphID(context.Background(), graphID, req)
-			if err != nil {
-				return fmt.Errorf("listing edges: %w", err)
-			}
-			edges = result
+			edges, err = c.Graph.Edge.GetByGraphID(ctx, graphID, req)
 		}
-
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("UUID", "NAME", "FACT", "VALID AT", "INVALID AT")
-			tbl.WriteHeader()
-			for _, e := range edges {
-				fact := e.Fact
-				if len(fact) > 40 {
-					fact = fact[:40] + "..."
-				}
-				validAt := ""
-				if e.ValidAt != nil {
-					validAt = *e.ValidAt
-				}
-				invalidAt := ""
-				if e.InvalidAt != nil {
-					invalidAt = *e.InvalidAt
-				}
-				tbl.WriteRow(e.UUID, e.Name, fact, validAt, invalidAt)
-			}
-			return tbl.Flush()
+		if err != nil {
+			return nil, fmt.Errorf("listing edges: %w", err)
 		}
-
-		return output.Print(edges)
-	},
+		return edges, nil
+	}
 }
 
 var edgeGetCmd = &cobra.Command{
@@ -104,7 +133,7 @@ var edgeGetCmd = &cobra.Command{
 			return fmt.Errorf("getting edge: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
+		if output.IsTabular() {
 			tbl := output.NewTable("FIELD", "VALUE")
 			tbl.WriteHeader()
 			tbl.WriteRow("UUID", edge.UUID)
@@ -170,6 +199,8 @@ func init() {
 	edgeListCmd.Flags().String("graph", "", "List edges for standalone graph")
 	edgeListCmd.Flags().Int("limit", 50, "Maximum number of results to return")
 	edgeListCmd.Flags().String("cursor", "", "UUID cursor for pagination (last UUID from previous page)")
+	addPaginationFlags(edgeListCmd)
+	addWatchFlags(edgeListCmd)
 
 	// Delete flags
 	edgeDeleteCmd.Flags().Bool("force", false, "Skip confirmation prompt")
@@ -23,71 +23,94 @@ var episodeCmd = &cobra.Command{
 var episodeListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List episodes",
+	Long: `List the episodes of a user graph or standalone graph.
+
+The episodes API is not paginated: one request returns the most recent
+episodes, or the last N with --last. -o ndjson therefore writes them once
+the whole response has arrived rather than page by page.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		userID, _ := cmd.Flags().GetString("user")
 		graphID, _ := cmd.Flags().GetString("graph")
 		lastN, _ := cmd.Flags().GetInt("last")
 
 		if userID == "" && graphID == "" {
-			return fmt.Errorf("either --user or --graph is required")
+			return invalidArgsf("either --user or --graph is required")
 		}
-
-		c, err := client.New()
+		watch, interval, err := wantsWatch(cmd)
 		if err != nil {
 			return err
 		}
 
-		var episodeResp *zep.EpisodeResponse
-
-		if userID != "" {
-			req := &graph.EpisodeGetByUserIDRequest{}
-			if lastN > 0 {
-				req.Lastn = zep.Int(lastN)
-			}
-			result, err := c.Graph.Episode.GetByUserID(context.Background(), userID, req)
-			if err != nil {
-				return fmt.Errorf("listing episodes: %w", err)
-			}
-			episodeResp = result
-		} else {
-			req := &graph.EpisodeGetByGraphIDRequest{}
-			if lastN > 0 {
-				req.Lastn = zep.Int(lastN)
-			}
-			result, err := c.Graph.Episode.GetByGraphID(context.Background(), graphID, req)
-			if err != nil {
-				return fmt.Errorf("listing episodes: %w", err)
-			}
-			episodeResp = result
+		c, err := client.New()
+		if err != nil {
+			return err
 		}
 
-		episodes := episodeResp.Episodes
+		fetch := func(ctx context.Context) ([]*zep.Episode, error) {
+			var episodeResp *zep.EpisodeResponse
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("UUID", "SOURCE", "ROLE", "CONTENT", "CREATED AT")
-			tbl.WriteHeader()
-			for _, ep := range episodes {
-				source := ""
-				if ep.Source != nil {
-					source = string(*ep.Source)
+			if userID != "" {
+				req := &graph.EpisodeGetByUserIDRequest{}
+				if lastN > 0 {
+					req.Lastn = zep.Int(lastN)
+				}
+				result, err := c.Graph.Episode.GetByUserID(ctx, userID, req)
+				if err != nil {
+					return nil, fmt.Errorf("listing episodes: %w", err)
 				}
-				role := ""
-				if ep.Role != nil {
-					role = *ep.Role
+				episodeResp = result
+			} else {
+				req := &graph.EpisodeGetByGraphIDRequest{}
+				if lastN > 0 {
+					req.Lastn = zep.Int(lastN)
 				}
-				content := ep.Content
-				if len(content) > 40 {
-					content = content[:40] + "..."
+				result, err := c.Graph.Episode.GetByGraphID(ctx, graphID, req)
+				if err != nil {
+					return nil, fmt.Errorf("listing episodes: %w", err)
 				}
-				tbl.WriteRow(ep.UUID, source, role, content, ep.CreatedAt)
+				episodeResp = result
 			}
-			return tbl.Flush()
+
+			return episodeResp.Episodes, nil
+		}
+
+		if watch {
+			return watchList(context.Background(), interval, episodeColumns, fetch, func(ep *zep.Episode) string {
+				return ep.UUID
+			})
+		}
+
+		episodes, err := fetch(context.Background())
+		if err != nil {
+			return err
 		}
 
-		return output.Print(episodes)
+		return printList(episodeColumns, episodes, episodes)
 	},
 }
 
+// episodeColumns is the column registry for graph episodes.
+var episodeColumns = output.Columns[*zep.Episode]{
+	{Name: "UUID", Value: func(ep *zep.Episode) string { return ep.UUID }},
+	{Name: "SOURCE", Value: func(ep *zep.Episode) string {
+		if ep.Source == nil {
+			return ""
+		}
+		return string(*ep.Source)
+	}},
+	{Name: "ROLE", Value: func(ep *zep.Episode) string { return stringValue(ep.Role) }},
+	{Name: "CONTENT", Value: func(ep *zep.Episode) string { return output.Truncate(ep.Content, 40) }},
+	{Name: "CREATED AT", Value: func(ep *zep.Episode) string { return ep.CreatedAt }},
+	{Name: "ROLE TYPE", Wide: true, Value: func(ep *zep.Episode) string {
+		if ep.RoleType == nil {
+			return ""
+		}
+		return string(*ep.RoleType)
+	}},
+	{Name: "SOURCE DESCRIPTION", Wide: true, Value: func(ep *zep.Episode) string { return stringValue(ep.SourceDescription) }},
+	{Name: "PROCESSED", Wide: true, Value: func(ep *zep.Episode) string { return boolValue(ep.Processed) }},
+}
+
 var episodeGetCmd = &cobra.Command{
 	Use:   "get <uuid>",
 	Short: "Get episode details",
@@ -105,7 +128,7 @@ var episodeGetCmd = &cobra.Command{
 			return fmt.Errorf("getting episode: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
+		if output.IsTabular() {
 			tbl := output.NewTable("FIELD", "VALUE")
 			tbl.WriteHeader()
 			tbl.WriteRow("UUID", episode.UUID)
@@ -196,6 +219,7 @@ func init() {
 	episodeListCmd.Flags().String("user", "", "List episodes for user graph")
 	episodeListCmd.Flags().String("graph", "", "List episodes for standalone graph")
 	episodeListCmd.Flags().Int("last", 0, "Get last N episodes")
+	addWatchFlags(episodeListCmd)
 
 	// Delete flags
 	episodeDeleteCmd.Flags().Bool("force", false, "Skip confirmation prompt")
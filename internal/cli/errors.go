@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getzep/zep-go/v3/core"
+	"github.com/getzep/zepctl/internal/archive"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by zepctl, as documented in docs/cli-specification.md.
+const (
+	ExitOK          = 0
+	ExitGeneral     = 1
+	ExitInvalidArgs = 2
+	ExitAuth        = 3
+	ExitNotFound    = 4
+	ExitRateLimit   = 5
+	ExitServer      = 6
+	ExitTimeout     = 7
+)
+
+// Error codes used in the structured error envelope.
+const (
+	codeGeneral     = "GENERAL_ERROR"
+	codeInvalidArgs = "INVALID_ARGUMENTS"
+	codeAuth        = "AUTHENTICATION_ERROR"
+	codeNotFound    = "RESOURCE_NOT_FOUND"
+	codeRateLimit   = "RATE_LIMIT_EXCEEDED"
+	codeServer      = "SERVER_ERROR"
+	codeTimeout     = "TIMEOUT"
+)
+
+// CommandError is a classified command failure carrying an exit code and
+// the fields of the structured error envelope.
+type CommandError struct {
+	Code     string
+	ExitCode int
+	Details  map[string]any
+	Err      error
+}
+
+func (e *CommandError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// invalidArgsf returns an error classified as invalid arguments.
+func invalidArgsf(format string, args ...any) error {
+	return asInvalidArgs(fmt.Errorf(format, args...))
+}
+
+// asInvalidArgs classifies err as invalid arguments.
+func asInvalidArgs(err error) error {
+	return &CommandError{Code: codeInvalidArgs, ExitCode: ExitInvalidArgs, Err: err}
+}
+
+// notFoundf returns an error classified as a missing resource.
+func notFoundf(resourceType, resourceID, format string, args ...any) error {
+	return &CommandError{
+		Code:     codeNotFound,
+		ExitCode: ExitNotFound,
+		Details:  map[string]any{"resource_type": resourceType, "resource_id": resourceID},
+		Err:      fmt.Errorf(format, args...),
+	}
+}
+
+// ExitCode returns the process exit code for an error returned by Execute.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	return classifyError(err).ExitCode
+}
+
+// classifyError maps an error from a command to an exit code and envelope code.
+// It understands zep-go API errors, client configuration errors, timeouts and
+// errors that were already classified by the command itself.
+func classifyError(err error) *CommandError {
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr
+	}
+
+	var apiErr *core.APIError
+	if errors.As(err, &apiErr) {
+		return classifyAPIError(err, apiErr)
+	}
+
+	if errors.Is(err, output.ErrInvalidTemplate) || errors.Is(err, output.ErrInvalidColumn) || errors.Is(err, archive.ErrInvalidArchive) {
+		return &CommandError{Code: codeInvalidArgs, ExitCode: ExitInvalidArgs, Err: err}
+	}
+
+	if errors.Is(err, client.ErrNoAPIKey) {
+		return &CommandError{Code: codeAuth, ExitCode: ExitAuth, Err: err}
+	}
+
+	if isTimeout(err) {
+		return &CommandError{Code: codeTimeout, ExitCode: ExitTimeout, Err: err}
+	}
+
+	// Cobra reports unknown subcommands as plain errors.
+	if strings.HasPrefix(err.Error(), "unknown command") {
+		return &CommandError{Code: codeInvalidArgs, ExitCode: ExitInvalidArgs, Err: err}
+	}
+
+	return &CommandError{Code: codeGeneral, ExitCode: ExitGeneral, Err: err}
+}
+
+func classifyAPIError(err error, apiErr *core.APIError) *CommandError {
+	details := map[string]any{"status_code": apiErr.StatusCode}
+	if msg := apiErrorMessage(apiErr); msg != "" {
+		details["api_message"] = msg
+	}
+
+	ce := &CommandError{Details: details, Err: err}
+	switch status := apiErr.StatusCode; {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		ce.Code, ce.ExitCode = codeAuth, ExitAuth
+	case status == http.StatusNotFound:
+		ce.Code, ce.ExitCode = codeNotFound, ExitNotFound
+	case status == http.StatusTooManyRequests:
+		ce.Code, ce.ExitCode = codeRateLimit, ExitRateLimit
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		ce.Code, ce.ExitCode = codeTimeout, ExitTimeout
+	case status >= http.StatusInternalServerError:
+		ce.Code, ce.ExitCode = codeServer, ExitServer
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		ce.Code, ce.ExitCode = codeInvalidArgs, ExitInvalidArgs
+	default:
+		ce.Code, ce.ExitCode = codeGeneral, ExitGeneral
+	}
+	return ce
+}
+
+// apiErrorMessage extracts the server-provided message from an API error.
+// zep-go formats API errors as "<status>: <response body>".
+func apiErrorMessage(apiErr *core.APIError) string {
+	body := strings.TrimPrefix(apiErr.Error(), strconv.Itoa(apiErr.StatusCode)+":")
+	body = strings.TrimSpace(body)
+
+	var parsed struct {
+		Message string `json:"message"`
+		Detail  string `json:"detail"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(body), &parsed); err == nil {
+		switch {
+		case parsed.Message != "":
+			return parsed.Message
+		case parsed.Detail != "":
+			return parsed.Detail
+		case parsed.Error != "":
+			return parsed.Error
+		}
+	}
+	return body
+}
+
+// isNotFound reports whether err is an API error for a missing resource.
+func isNotFound(err error) bool {
+	var apiErr *core.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// addResourceDetails annotates not-found errors with the resource type and ID
+// of the command that failed, e.g. "user get <user-id>".
+func addResourceDetails(cmd *cobra.Command, ce *CommandError) {
+	if cmd == nil || ce.Code != codeNotFound || !cmd.HasParent() || cmd.Parent() == cmd.Root() {
+		return
+	}
+	if _, ok := ce.Details["resource_type"]; ok {
+		return
+	}
+	args := cmd.Flags().Args()
+	if len(args) == 0 {
+		return
+	}
+	if ce.Details == nil {
+		ce.Details = map[string]any{}
+	}
+	ce.Details["resource_type"] = cmd.Parent().Name()
+	ce.Details["resource_id"] = args[0]
+}
+
+// wrapArgValidators classifies positional argument validation failures as
+// invalid arguments for cmd and all of its subcommands.
+func wrapArgValidators(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				return asInvalidArgs(err)
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		wrapArgValidators(sub)
+	}
+}
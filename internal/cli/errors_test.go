@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/getzep/zepctl/internal/client"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		code     string
+		exitCode int
+	}{
+		{
+			name:     "invalid arguments",
+			err:      invalidArgsf("--user flag is required"),
+			code:     codeInvalidArgs,
+			exitCode: ExitInvalidArgs,
+		},
+		{
+			name:     "wrapped invalid arguments",
+			err:      fmt.Errorf("searching graph: %w", asInvalidArgs(errors.New("bad filter"))),
+			code:     codeInvalidArgs,
+			exitCode: ExitInvalidArgs,
+		},
+		{
+			name:     "not found",
+			err:      notFoundf("profile", "dev", "profile %q not found", "dev"),
+			code:     codeNotFound,
+			exitCode: ExitNotFound,
+		},
+		{
+			name:     "missing API key",
+			err:      client.ErrNoAPIKey,
+			code:     codeAuth,
+			exitCode: ExitAuth,
+		},
+		{
+			name:     "deadline exceeded",
+			err:      fmt.Errorf("timeout waiting for task t1: %w", context.DeadlineExceeded),
+			code:     codeTimeout,
+			exitCode: ExitTimeout,
+		},
+		{
+			name:     "unknown command",
+			err:      errors.New(`unknown command "foo" for "zepctl"`),
+			code:     codeInvalidArgs,
+			exitCode: ExitInvalidArgs,
+		},
+		{
+			name:     "general error",
+			err:      errors.New("reading file: permission denied"),
+			code:     codeGeneral,
+			exitCode: ExitGeneral,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ce := classifyError(tt.err)
+			if ce.Code != tt.code {
+				t.Errorf("code = %q, want %q", ce.Code, tt.code)
+			}
+			if ce.ExitCode != tt.exitCode {
+				t.Errorf("exit code = %d, want %d", ce.ExitCode, tt.exitCode)
+			}
+			if ExitCode(tt.err) != tt.exitCode {
+				t.Errorf("ExitCode() = %d, want %d", ExitCode(tt.err), tt.exitCode)
+			}
+		})
+	}
+}
+
+func TestExitCodeNil(t *testing.T) {
+	if got := ExitCode(nil); got != ExitOK {
+		t.Errorf("ExitCode(nil) = %d, want %d", got, ExitOK)
+	}
+}
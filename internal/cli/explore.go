@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/explore"
+	"github.com/getzep/zepctl/internal/pagination"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	// exploreMaxNodes caps the node list loaded by explore. Nodes beyond it
+	// can still be reached through search or by following edges.
+	exploreMaxNodes = 1000
+
+	// exploreSearchLimit is the number of nodes a search returns.
+	exploreSearchLimit = 50
+)
+
+var exploreCmd = &cobra.Command{
+	Use:   "explore",
+	Short: "Interactively explore a graph",
+	Long: `Open a full-screen browser for a user graph or standalone graph.
+
+The left pane lists the graph's nodes. The right side shows the details of the
+selected item, the edges of the selected node and the episodes that mention it.
+
+Keys:
+  up/down, j/k   Move the selection
+  tab            Switch between the node, edge and episode panes
+  enter          Show a node's edges, or follow an edge to its other node
+  s, t           Jump to the source or target node of the selected edge
+  e              Show the episodes that mention the selected node
+  b              Go back to the previous node
+  /              Search nodes (an empty search lists every node)
+  d              Delete the selected node, edge or episode, after confirming
+  r              Reload
+  q, ctrl-c      Quit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userID, _ := cmd.Flags().GetString("user")
+		graphID, _ := cmd.Flags().GetString("graph")
+
+		if userID == "" && graphID == "" {
+			return invalidArgsf("either --user or --graph is required")
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+			return invalidArgsf("explore needs an interactive terminal")
+		}
+
+		c, err := client.New()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		m := explore.New(&graphSource{c: c, userID: userID, graphID: graphID}, graphLabel(userID, graphID))
+		if err := m.Load(ctx); err != nil {
+			return err
+		}
+		return explore.Run(ctx, m, os.Stdin, os.Stdout)
+	},
+}
+
+// graphSource serves the explorer from a user graph or standalone graph with
+// the same calls the node, edge and episode commands make.
+type graphSource struct {
+	c       *client.Client
+	userID  string
+	graphID string
+}
+
+func (s *graphSource) Nodes(ctx context.Context) ([]*zep.EntityNode, error) {
+	it := pagination.NewCursorIterator("", exportPageSize, nodePageFetcher(s.c, s.userID, s.graphID), func(n *zep.EntityNode) string {
+		return n.UUID
+	}).WithMaxItems(exploreMaxNodes)
+	return it.All(ctx)
+}
+
+func (s *graphSource) Search(ctx context.Context, query string) ([]*zep.EntityNode, error) {
+	scope := zep.GraphSearchScope("nodes")
+	req := &zep.GraphSearchQuery{
+		Query: query,
+		Limit: zep.Int(exploreSearchLimit),
+		Scope: &scope,
+	}
+	if s.userID != "" {
+		req.UserID = zep.String(s.userID)
+	} else {
+		req.GraphID = zep.String(s.graphID)
+	}
+
+	resp, err := s.c.Graph.Search(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("searching graph: %w", err)
+	}
+	return resp.Nodes, nil
+}
+
+func (s *graphSource) Node(ctx context.Context, uuid string) (*zep.EntityNode, error) {
+	node, err := s.c.Graph.Node.Get(ctx, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("getting node: %w", err)
+	}
+	return node, nil
+}
+
+func (s *graphSource) NodeEdges(ctx context.Context, uuid string) ([]*zep.EntityEdge, error) {
+	edges, err := s.c.Graph.Node.GetEdges(ctx, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("getting node edges: %w", err)
+	}
+	return edges, nil
+}
+
+func (s *graphSource) NodeEpisodes(ctx context.Context, uuid string) ([]*zep.Episode, error) {
+	episodes, err := s.c.Graph.Node.GetEpisodes(ctx, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("getting node episodes: %w", err)
+	}
+	return episodes.Episodes, nil
+}
+
+func (s *graphSource) DeleteNode(ctx context.Context, uuid string) error {
+	if _, err := s.c.Graph.Node.Delete(ctx, uuid); err != nil {
+		return fmt.Errorf("deleting node: %w", err)
+	}
+	return nil
+}
+
+func (s *graphSource) DeleteEdge(ctx context.Context, uuid string) error {
+	if _, err := s.c.Graph.Edge.Delete(ctx, uuid); err != nil {
+		return fmt.Errorf("deleting edge: %w", err)
+	}
+	return nil
+}
+
+func (s *graphSource) DeleteEpisode(ctx context.Context, uuid string) error {
+	if _, err := s.c.Graph.Episode.Delete(ctx, uuid); err != nil {
+		return fmt.Errorf("deleting episode: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(exploreCmd)
+
+	exploreCmd.Flags().String("user", "", "Explore user graph")
+	exploreCmd.Flags().String("graph", "", "Explore standalone graph")
+}
@@ -0,0 +1,19 @@
+package cli
+
+import "testing"
+
+func TestExploreRequiresTerminal(t *testing.T) {
+	server := newSandbox(t)
+	if _, err := runCLI(t, server, "user", "create", "alice"); err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"explore"},
+		{"explore", "--user", "alice"}, // stdout is a pipe in tests
+	} {
+		if _, err := runCLI(t, server, args...); ExitCode(err) != ExitInvalidArgs {
+			t.Errorf("zepctl %v: exit code %d, want %d", args, ExitCode(err), ExitInvalidArgs)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zep-go/v3/graph"
+	"github.com/getzep/zepctl/internal/archive"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/pagination"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// exportPageSize is the page size used when walking list endpoints.
+	exportPageSize = 100
+
+	// exportEpisodeLimit is the number of most recent episodes exported per
+	// graph. The episode endpoints are not paginated and can only return the
+	// last N episodes.
+	exportEpisodeLimit = 10000
+)
+
+var projectExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the project to an archive",
+	Long: `Export every user with their threads, messages and graph, every standalone
+graph with its episodes, nodes and edges, the ontology and summary instructions
+to a gzipped tar archive.
+
+The archive contains a manifest.json describing the export and one NDJSON file
+per resource type, each with a SHA-256 checksum recorded in the manifest.
+
+The episodes API can only list the most recent 10000 episodes of a graph, so
+the export fails if a graph has that many, rather than silently leaving out
+older ones. Pass --allow-partial-episodes to export the most recent 10000
+episodes of such graphs instead.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := cmd.Flags().GetString("out")
+		partialEpisodes, _ := cmd.Flags().GetBool("allow-partial-episodes")
+		if out == "" {
+			return invalidArgsf("--out is required")
+		}
+
+		c, err := client.New()
+		if err != nil {
+			return err
+		}
+
+		w, err := archive.NewWriter()
+		if err != nil {
+			return err
+		}
+
+		e := &exporter{c: c, w: w, partialEpisodes: partialEpisodes, progress: output.NewProgress("Exporting")}
+		manifest, err := e.run(context.Background())
+		if err != nil {
+			w.Discard()
+			return err
+		}
+		e.progress.Done()
+
+		manifest, err = w.Close(out, manifest)
+		if err != nil {
+			return err
+		}
+		output.Info("Exported project to %s", out)
+
+		if output.IsTabular() {
+			tbl := output.NewTable("FILE", "RECORDS", "SIZE", "SHA256")
+			tbl.WriteHeader()
+			for _, f := range manifest.Files {
+				tbl.WriteRow(f.Name, strconv.Itoa(f.Records), strconv.FormatInt(f.Size, 10), f.SHA256)
+			}
+			return tbl.Flush()
+		}
+
+		return output.Print(manifest)
+	},
+}
+
+// exporter walks a project and writes every resource to an archive.
+type exporter struct {
+	c *client.Client
+	w *archive.Writer
+	// partialEpisodes exports the most recent episodes of graphs with more
+	// than exportEpisodeLimit instead of failing.
+	partialEpisodes bool
+	progress        *output.Progress
+}
+
+func (e *exporter) run(ctx context.Context) (archive.Manifest, error) {
+	manifest := archive.Manifest{
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		ZepctlVersion: version,
+	}
+
+	project, err := e.c.Project.Get(ctx)
+	if err != nil {
+		return manifest, fmt.Errorf("getting project: %w", err)
+	}
+	if manifest.Project, err = json.Marshal(project.Project); err != nil {
+		return manifest, fmt.Errorf("encoding project: %w", err)
+	}
+
+	steps := []func(context.Context) error{
+		e.exportUsers,
+		e.exportGraphs,
+		e.exportOntology,
+		func(ctx context.Context) error { return e.exportInstructions(ctx, "") },
+	}
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			return manifest, err
+		}
+	}
+	return manifest, nil
+}
+
+func (e *exporter) add(resource string, record any) error {
+	if err := e.w.Add(resource, record); err != nil {
+		return err
+	}
+	e.progress.Add(resource, 1)
+	return nil
+}
+
+func (e *exporter) exportUsers(ctx context.Context) error {
+	it := pagination.NewPageIterator(1, exportPageSize, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.User, error) {
+		users, err := e.c.User.ListOrdered(ctx, &zep.UserListOrderedRequest{
+			PageNumber: zep.Int(pageNumber),
+			PageSize:   zep.Int(pageSize),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing users: %w", err)
+		}
+		return users.Users, nil
+	})
+
+	return it.ForEachPage(ctx, func(users []*zep.User) error {
+		for _, u := range users {
+			userID := stringValue(u.UserID)
+			if err := e.add(archive.Users, archive.Record[*zep.User]{Data: u}); err != nil {
+				return err
+			}
+			if err := e.exportThreads(ctx, userID); err != nil {
+				return err
+			}
+			if err := e.exportGraph(ctx, userID, ""); err != nil {
+				return err
+			}
+			if err := e.exportInstructions(ctx, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (e *exporter) exportThreads(ctx context.Context, userID string) error {
+	threads, err := e.c.User.GetThreads(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("getting threads for user %q: %w", userID, err)
+	}
+
+	for _, t := range threads {
+		if err := e.add(archive.Threads, archive.Record[*zep.Thread]{UserID: userID, Data: t}); err != nil {
+			return err
+		}
+		if err := e.exportMessages(ctx, stringValue(t.ThreadID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportMessages pages through a thread's messages. The cursor is the number
+// of messages already read.
+func (e *exporter) exportMessages(ctx context.Context, threadID string) error {
+	cursor := 0
+	for {
+		resp, err := e.c.Thread.Get(ctx, threadID, &zep.ThreadGetRequest{
+			Limit:  zep.Int(exportPageSize),
+			Cursor: zep.Int(cursor),
+		})
+		if err != nil {
+			return fmt.Errorf("getting messages for thread %q: %w", threadID, err)
+		}
+		for _, m := range resp.Messages {
+			if err := e.add(archive.Messages, archive.Record[*zep.Message]{ThreadID: threadID, Data: m}); err != nil {
+				return err
+			}
+		}
+		if len(resp.Messages) < exportPageSize {
+			return nil
+		}
+		cursor += len(resp.Messages)
+	}
+}
+
+func (e *exporter) exportGraphs(ctx context.Context) error {
+	it := pagination.NewPageIterator(1, exportPageSize, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.Graph, error) {
+		graphs, err := e.c.Graph.ListAll(ctx, &zep.GraphListAllRequest{
+			PageNumber: zep.Int(pageNumber),
+			PageSize:   zep.Int(pageSize),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing graphs: %w", err)
+		}
+		return graphs.Graphs, nil
+	})
+
+	return it.ForEachPage(ctx, func(graphs []*zep.Graph) error {
+		for _, g := range graphs {
+			if err := e.add(archive.Graphs, archive.Record[*zep.Graph]{Data: g}); err != nil {
+				return err
+			}
+			if err := e.exportGraph(ctx, "", stringValue(g.GraphID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// exportGraph exports the episodes, nodes and edges of a user graph or
+// standalone graph.
+func (e *exporter) exportGraph(ctx context.Context, userID, graphID string) error {
+	var episodes *zep.EpisodeResponse
+	var err error
+	if userID != "" {
+		episodes, err = e.c.Graph.Episode.GetByUserID(ctx, userID, &graph.EpisodeGetByUserIDRequest{Lastn: zep.Int(exportEpisodeLimit)})
+	} else {
+		episodes, err = e.c.Graph.Episode.GetByGraphID(ctx, graphID, &graph.EpisodeGetByGraphIDRequest{Lastn: zep.Int(exportEpisodeLimit)})
+	}
+	if err != nil {
+		return fmt.Errorf("listing episodes: %w", err)
+	}
+	if len(episodes.Episodes) >= exportEpisodeLimit {
+		if !e.partialEpisodes {
+			return fmt.Errorf("%s has %d or more episodes and only the most recent %d can be exported (use --allow-partial-episodes to export them anyway)",
+				graphLabel(userID, graphID), exportEpisodeLimit, exportEpisodeLimit)
+		}
+		output.Warn("only the last %d episodes of %s were exported", exportEpisodeLimit, graphLabel(userID, graphID))
+	}
+	for _, ep := range episodes.Episodes {
+		if err := e.add(archive.Episodes, archive.Record[*zep.Episode]{UserID: userID, GraphID: graphID, Data: ep}); err != nil {
+			return err
+		}
+	}
+
+	nodes := pagination.NewCursorIterator("", exportPageSize, nodePageFetcher(e.c, userID, graphID), func(n *zep.EntityNode) string {
+		return n.UUID
+	})
+	err = nodes.ForEachPage(ctx, func(nodes []*zep.EntityNode) error {
+		for _, n := range nodes {
+			if err := e.add(archive.Nodes, archive.Record[*zep.EntityNode]{UserID: userID, GraphID: graphID, Data: n}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	edges := pagination.NewCursorIterator("", exportPageSize, edgePageFetcher(e.c, userID, graphID), func(e *zep.EntityEdge) string {
+		return e.UUID
+	})
+	return edges.ForEachPage(ctx, func(edges []*zep.EntityEdge) error {
+		for _, edge := range edges {
+			if err := e.add(archive.Edges, archive.Record[*zep.EntityEdge]{UserID: userID, GraphID: graphID, Data: edge}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (e *exporter) exportOntology(ctx context.Context) error {
+	ontology, err := e.c.Graph.ListEntityTypes(ctx, &zep.GraphListEntityTypesRequest{})
+	if err != nil {
+		return fmt.Errorf("getting ontology: %w", err)
+	}
+	return e.add(archive.Ontology, archive.Record[*zep.EntityTypeResponse]{Data: ontology})
+}
+
+// exportInstructions exports the summary instructions of a user, or the
+// project-wide instructions if userID is empty.
+func (e *exporter) exportInstructions(ctx context.Context, userID string) error {
+	req := &zep.UserListUserSummaryInstructionsRequest{}
+	if userID != "" {
+		req.UserID = zep.String(userID)
+	}
+	result, err := e.c.User.ListUserSummaryInstructions(ctx, req)
+	if err != nil {
+		return fmt.Errorf("listing summary instructions: %w", err)
+	}
+	for _, inst := range result.Instructions {
+		if err := e.add(archive.SummaryInstructions, archive.Record[*zep.UserInstruction]{UserID: userID, Data: inst}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// graphLabel describes a user graph or standalone graph in messages.
+func graphLabel(userID, graphID string) string {
+	if userID != "" {
+		return fmt.Sprintf("user %q", userID)
+	}
+	return fmt.Sprintf("graph %q", graphID)
+}
+
+func init() {
+	projectCmd.AddCommand(projectExportCmd)
+
+	projectExportCmd.Flags().String("out", "", "Path of the archive to write, e.g. backup.tar.gz")
+	projectExportCmd.Flags().Bool("allow-partial-episodes", false, "Export only the most recent 10000 episodes of larger graphs instead of failing")
+
+}
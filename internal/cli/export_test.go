@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/getzep/zepctl/internal/archive"
+	"github.com/getzep/zepctl/internal/sandbox"
+)
+
+func TestProjectExport(t *testing.T) {
+	server := newSandbox(t)
+	setup := [][]string{
+		{"user", "create", "alice"},
+		{"thread", "create", "t1", "--user", "alice"},
+		{"graph", "create", "g1"},
+		{"graph", "add-fact", "--graph", "g1", "--fact", "Alice works at Acme", "--fact-name", "WORKS_AT", "--source-node", "Alice", "--target-node", "Acme"},
+		{"summary-instructions", "add", "--name", "tone", "--instruction", "Be brief"},
+	}
+	for _, args := range setup {
+		if _, err := runCLI(t, server, args...); err != nil {
+			t.Fatalf("zepctl %v: %v", args, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+	if _, err := runCLI(t, server, "project", "export", "--out", path, "-q"); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	r, err := archive.Open(path)
+	if err != nil {
+		t.Fatalf("opening archive: %v", err)
+	}
+	manifest := r.Manifest()
+	want := map[string]int{
+		archive.Users:               1,
+		archive.Threads:             1,
+		archive.Graphs:              1,
+		archive.Nodes:               3, // the user node plus Alice and Acme
+		archive.Edges:               1,
+		archive.Ontology:            1,
+		archive.SummaryInstructions: 1,
+	}
+	for resource, records := range want {
+		f, ok := manifest.File(resource)
+		if !ok {
+			t.Errorf("archive has no %s file", resource)
+			continue
+		}
+		if f.Records != records {
+			t.Errorf("%s: %d records, want %d", resource, f.Records, records)
+		}
+	}
+
+	if _, err := runCLI(t, server, "project", "export"); ExitCode(err) != ExitInvalidArgs {
+		t.Errorf("export without --out: exit code %d, want %d", ExitCode(err), ExitInvalidArgs)
+	}
+}
+
+func TestProjectExportEpisodeLimit(t *testing.T) {
+	// Seed a sandbox with a graph of exportEpisodeLimit episodes by copying
+	// one episode in the saved state, which is faster than adding them.
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	server, err := sandbox.Start(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"graph", "create", "g1"}, {"graph", "add", "g1", "--data", "Alice works at Acme"}} {
+		if _, err := runCLI(t, server, args...); err != nil {
+			_ = server.Close()
+			t.Fatalf("zepctl %v: %v", args, err)
+		}
+	}
+	_ = server.Close()
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state map[string]json.RawMessage
+	var episodes map[string]map[string]any
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(state["episodes"], &episodes); err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range episodes {
+		for i := range exportEpisodeLimit - 1 {
+			uuid := fmt.Sprintf("copy-%d", i)
+			episode := maps.Clone(rec["episode"].(map[string]any))
+			episode["uuid"] = uuid
+			episodes[uuid] = map[string]any{"seq": rec["seq"], "graph": rec["graph"], "episode": episode}
+		}
+		break
+	}
+	if state["episodes"], err = json.Marshal(episodes); err != nil {
+		t.Fatal(err)
+	}
+	if data, err = json.Marshal(state); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(statePath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err = sandbox.Start(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+	_, err = runCLI(t, server, "project", "export", "--out", path, "-q")
+	if err == nil || !strings.Contains(err.Error(), "--allow-partial-episodes") {
+		t.Fatalf("export of a graph at the episode limit: err %v, want a failure", err)
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Errorf("failed export left an archive behind (stat: %v)", statErr)
+	}
+
+	if _, err := runCLI(t, server, "project", "export", "--out", path, "-q", "--allow-partial-episodes"); err != nil {
+		t.Fatalf("export --allow-partial-episodes: %v", err)
+	}
+	r, err := archive.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := r.Manifest()
+	if f, _ := manifest.File(archive.Episodes); f.Records != exportEpisodeLimit {
+		t.Errorf("exported %d episodes, want %d", f.Records, exportEpisodeLimit)
+	}
+}
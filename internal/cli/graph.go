@@ -8,10 +8,12 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/getzep/zep-go/v3"
 	"github.com/getzep/zepctl/internal/client"
 	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/pagination"
 	"github.com/spf13/cobra"
 )
 
@@ -33,6 +35,20 @@ var graphListCmd = &cobra.Command{
 		page, _ := cmd.Flags().GetInt("page")
 		pageSize, _ := cmd.Flags().GetInt("page-size")
 
+		if paginate, maxItems := wantsAllPages(cmd); paginate {
+			it := pagination.NewPageIterator(page, pageSize, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.Graph, error) {
+				graphs, err := c.Graph.ListAll(ctx, &zep.GraphListAllRequest{
+					PageNumber: zep.Int(pageNumber),
+					PageSize:   zep.Int(pageSize),
+				})
+				if err != nil {
+					return nil, fmt.Errorf("listing graphs: %w", err)
+				}
+				return graphs.Graphs, nil
+			}).WithMaxItems(maxItems)
+			return printPages(it, graphColumns)
+		}
+
 		graphs, err := c.Graph.ListAll(context.Background(), &zep.GraphListAllRequest{
 			PageNumber: zep.Int(page),
 			PageSize:   zep.Int(pageSize),
@@ -41,35 +57,19 @@ var graphListCmd = &cobra.Command{
 			return fmt.Errorf("listing graphs: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("UUID", "GRAPH ID", "NAME", "CREATED AT")
-			tbl.WriteHeader()
-			for _, g := range graphs.Graphs {
-				uuid := ""
-				if g.UUID != nil {
-					uuid = *g.UUID
-				}
-				graphID := ""
-				if g.GraphID != nil {
-					graphID = *g.GraphID
-				}
-				name := ""
-				if g.Name != nil {
-					name = *g.Name
-				}
-				createdAt := ""
-				if g.CreatedAt != nil {
-					createdAt = *g.CreatedAt
-				}
-				tbl.WriteRow(uuid, graphID, name, createdAt)
-			}
-			return tbl.Flush()
-		}
-
-		return output.Print(graphs)
+		return printList(graphColumns, graphs.Graphs, graphs)
 	},
 }
 
+// graphColumns is the column registry for standalone graphs.
+var graphColumns = output.Columns[*zep.Graph]{
+	{Name: "UUID", Value: func(g *zep.Graph) string { return stringValue(g.UUID) }},
+	{Name: "GRAPH ID", Value: func(g *zep.Graph) string { return stringValue(g.GraphID) }},
+	{Name: "NAME", Value: func(g *zep.Graph) string { return stringValue(g.Name) }},
+	{Name: "CREATED AT", Value: func(g *zep.Graph) string { return stringValue(g.CreatedAt) }},
+	{Name: "DESCRIPTION", Wide: true, Value: func(g *zep.Graph) string { return output.Truncate(stringValue(g.Description), 40) }},
+}
+
 var graphCreateCmd = &cobra.Command{
 	Use:   "create <graph-id>",
 	Short: "Create a new graph",
@@ -89,16 +89,18 @@ var graphCreateCmd = &cobra.Command{
 			return fmt.Errorf("creating graph: %w", err)
 		}
 
+		invalidateCache(cacheGraphIDs)
 		output.Info("Created graph %q", graphID)
 		return output.Print(graph)
 	},
 }
 
 var graphDeleteCmd = &cobra.Command{
-	Use:   "delete <graph-id>",
-	Short: "Delete a graph",
-	Long:  `Delete a graph. To delete a user graph, use 'zepctl user delete' instead.`,
-	Args:  cobra.ExactArgs(1),
+	Use:               "delete <graph-id>",
+	Short:             "Delete a graph",
+	Long:              `Delete a graph. To delete a user graph, use 'zepctl user delete' instead.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeGraphIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		graphID := args[0]
 		force, _ := cmd.Flags().GetBool("force")
@@ -123,6 +125,7 @@ var graphDeleteCmd = &cobra.Command{
 			return fmt.Errorf("deleting graph: %w", err)
 		}
 
+		invalidateCache(cacheGraphIDs)
 		output.Info("Deleted graph %q", graphID)
 		return nil
 	},
@@ -137,21 +140,26 @@ var graphCloneCmd = &cobra.Command{
 		targetUser, _ := cmd.Flags().GetString("target-user")
 		sourceGraph, _ := cmd.Flags().GetString("source-graph")
 		targetGraph, _ := cmd.Flags().GetString("target-graph")
+		wait, _ := cmd.Flags().GetBool("wait")
+		timeout, pollInterval, err := waitFlags(cmd)
+		if err != nil {
+			return err
+		}
 
 		if sourceUser == "" && sourceGraph == "" {
-			return fmt.Errorf("either --source-user or --source-graph is required")
+			return invalidArgsf("either --source-user or --source-graph is required")
 		}
 
 		if sourceUser != "" && sourceGraph != "" {
-			return fmt.Errorf("--source-user and --source-graph are mutually exclusive")
+			return invalidArgsf("--source-user and --source-graph are mutually exclusive")
 		}
 
 		if sourceUser != "" && targetGraph != "" {
-			return fmt.Errorf("--target-graph cannot be used with --source-user; use --target-user instead")
+			return invalidArgsf("--target-graph cannot be used with --source-user; use --target-user instead")
 		}
 
 		if sourceGraph != "" && targetUser != "" {
-			return fmt.Errorf("--target-user cannot be used with --source-graph; use --target-graph instead")
+			return invalidArgsf("--target-user cannot be used with --source-graph; use --target-graph instead")
 		}
 
 		c, err := client.New()
@@ -177,6 +185,9 @@ var graphCloneCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("cloning graph: %w", err)
 		}
+		// Cloning a user graph creates the target user.
+		invalidateCache(cacheGraphIDs)
+		invalidateCache(cacheUserIDs)
 
 		if resp.GraphID != nil {
 			output.Info("Cloned to graph: %s", *resp.GraphID)
@@ -184,6 +195,13 @@ var graphCloneCmd = &cobra.Command{
 			output.Info("Cloned to user: %s", *resp.UserID)
 		}
 
+		if wait {
+			if err := waitForClone(c, resp, timeout, pollInterval); err != nil {
+				return err
+			}
+			output.Info("Clone completed")
+		}
+
 		return output.Print(resp)
 	},
 }
@@ -195,15 +213,30 @@ type EpisodeInput struct {
 
 // EpisodeData represents a single episode for batch import.
 type EpisodeData struct {
-	Type string `json:"type"`
-	Data string `json:"data"`
+	Type              string `json:"type"`
+	Data              string `json:"data"`
+	CreatedAt         string `json:"created_at,omitempty"`
+	SourceDescription string `json:"source_description,omitempty"`
+}
+
+// parseTimestamp validates an episode timestamp. It accepts RFC 3339
+// timestamps and plain dates, which are sent as midnight UTC.
+func parseTimestamp(name, value string) (string, error) {
+	if _, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return value, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t.Format(time.RFC3339), nil
+	}
+	return "", invalidArgsf("invalid %s %q: must be an RFC 3339 timestamp such as 2024-01-15T10:30:00Z, or a date such as 2024-01-15", name, value)
 }
 
 var graphAddCmd = &cobra.Command{
-	Use:   "add [graph-id]",
-	Short: "Add data to a graph",
-	Long:  `Add text, JSON, or message data to a graph or user graph.`,
-	Args:  cobra.MaximumNArgs(1),
+	Use:               "add [graph-id]",
+	Short:             "Add data to a graph",
+	Long:              `Add text, JSON, or message data to a graph or user graph.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: firstArg(completeGraphIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		userID, _ := cmd.Flags().GetString("user")
 		dataType, _ := cmd.Flags().GetString("type")
@@ -211,7 +244,23 @@ var graphAddCmd = &cobra.Command{
 		file, _ := cmd.Flags().GetString("file")
 		useStdin, _ := cmd.Flags().GetBool("stdin")
 		batch, _ := cmd.Flags().GetBool("batch")
+		inputFormatFlag, _ := cmd.Flags().GetString("input-format")
+		createdAt, _ := cmd.Flags().GetString("created-at")
+		sourceDescription, _ := cmd.Flags().GetString("source-description")
 		wait, _ := cmd.Flags().GetBool("wait")
+		timeout, pollInterval, err := waitFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		if batch && (createdAt != "" || sourceDescription != "") {
+			return invalidArgsf("--created-at and --source-description apply to a single episode; set created_at and source_description on each episode of the batch file instead")
+		}
+		if createdAt != "" {
+			if createdAt, err = parseTimestamp("--created-at", createdAt); err != nil {
+				return err
+			}
+		}
 
 		var graphID string
 		if len(args) > 0 {
@@ -219,7 +268,7 @@ var graphAddCmd = &cobra.Command{
 		}
 
 		if userID == "" && graphID == "" {
-			return fmt.Errorf("either graph-id argument or --user flag is required")
+			return invalidArgsf("either graph-id argument or --user flag is required")
 		}
 
 		c, err := client.New()
@@ -229,55 +278,75 @@ var graphAddCmd = &cobra.Command{
 
 		// Handle batch mode
 		if batch {
-			var data []byte
-			if file != "" {
-				data, err = os.ReadFile(file)
-				if err != nil {
-					return fmt.Errorf("reading file: %w", err)
-				}
-			} else if useStdin {
-				data, err = io.ReadAll(os.Stdin)
-				if err != nil {
-					return fmt.Errorf("reading stdin: %w", err)
-				}
-			} else {
-				return fmt.Errorf("--file or --stdin is required for batch mode")
+			if file == "" && !useStdin {
+				return invalidArgsf("--file or --stdin is required for batch mode")
 			}
-
-			var input EpisodeInput
-			if err := json.Unmarshal(data, &input); err != nil {
-				return fmt.Errorf("parsing episodes: %w", err)
+			format, err := inputFormat(inputFormatFlag, file)
+			if err != nil {
+				return err
 			}
+			in, err := openInput(file)
+			if err != nil {
+				return err
+			}
+			defer in.Close()
 
 			var episodes []*zep.EpisodeData
-			for _, e := range input.Episodes {
-				episodeType := zep.GraphDataType(e.Type)
-				episodes = append(episodes, &zep.EpisodeData{
+			err = readRecords(in, format, "episodes", func(e *EpisodeData, _ int) error {
+				episode := &zep.EpisodeData{
 					Data: e.Data,
-					Type: episodeType,
-				})
+					Type: zep.GraphDataType(e.Type),
+				}
+				if e.CreatedAt != "" {
+					createdAt, err := parseTimestamp("created_at", e.CreatedAt)
+					if err != nil {
+						return err
+					}
+					episode.CreatedAt = zep.String(createdAt)
+				}
+				if e.SourceDescription != "" {
+					episode.SourceDescription = zep.String(e.SourceDescription)
+				}
+				episodes = append(episodes, episode)
+				return nil
+			})
+			if err != nil {
+				return asInvalidArgs(fmt.Errorf("parsing episodes: %w", err))
+			}
+			if len(episodes) == 0 {
+				return invalidArgsf("no episodes in input")
 			}
 
-			req := &zep.AddDataBatchRequest{
-				Episodes: episodes,
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			if concurrency < 1 {
+				return invalidArgsf("--concurrency must be at least 1")
 			}
-			if userID != "" {
-				req.UserID = zep.String(userID)
-			} else {
-				req.GraphID = zep.String(graphID)
+			results, _ := cmd.Flags().GetString("results")
+
+			adder := &batchAdder{
+				c:           c,
+				userID:      userID,
+				graphID:     graphID,
+				concurrency: concurrency,
+				resultsPath: results,
 			}
-
-			resp, err := c.Graph.AddBatch(context.Background(), req)
+			resp, skipped, err := adder.add(context.Background(), episodes)
 			if err != nil {
 				return fmt.Errorf("adding batch data: %w", err)
 			}
 
-			output.Info("Added %d episodes to graph", len(resp))
-			if !wait {
-				return output.Print(resp)
+			if skipped > 0 {
+				output.Info("Added %d episodes to graph (%d already added, see %s)", len(resp), skipped, results)
+			} else {
+				output.Info("Added %d episodes to graph", len(resp))
+			}
+			if wait {
+				resp, err = waitForEpisodes(c, resp, timeout, pollInterval)
+				if err != nil {
+					return err
+				}
+				output.Info("Processed %d episodes", len(resp))
 			}
-
-			// If wait is requested, we just report completion since batch returns episodes directly
 			return output.Print(resp)
 		}
 
@@ -298,7 +367,7 @@ var graphAddCmd = &cobra.Command{
 			}
 			dataContent = string(data)
 		} else {
-			return fmt.Errorf("--data, --file, or --stdin is required")
+			return invalidArgsf("--data, --file, or --stdin is required")
 		}
 
 		episodeType := zep.GraphDataType(dataType)
@@ -306,6 +375,12 @@ var graphAddCmd = &cobra.Command{
 			Data: dataContent,
 			Type: episodeType,
 		}
+		if createdAt != "" {
+			req.CreatedAt = zep.String(createdAt)
+		}
+		if sourceDescription != "" {
+			req.SourceDescription = zep.String(sourceDescription)
+		}
 		if userID != "" {
 			req.UserID = zep.String(userID)
 		} else {
@@ -318,6 +393,14 @@ var graphAddCmd = &cobra.Command{
 		}
 
 		output.Info("Added data to graph")
+		if wait {
+			episodes, err := waitForEpisodes(c, []*zep.Episode{resp}, timeout, pollInterval)
+			if err != nil {
+				return err
+			}
+			resp = episodes[0]
+			output.Info("Episode processed")
+		}
 		return output.Print(resp)
 	},
 }
@@ -343,20 +426,20 @@ Example: --source-attrs '{"type": "Person", "age": 30}'`,
 		targetAttrsStr, _ := cmd.Flags().GetString("target-attrs")
 
 		if userID == "" && graphID == "" {
-			return fmt.Errorf("either --user or --graph is required")
+			return invalidArgsf("either --user or --graph is required")
 		}
 
 		if fact == "" {
-			return fmt.Errorf("--fact is required")
+			return invalidArgsf("--fact is required")
 		}
 		if factName == "" {
-			return fmt.Errorf("--fact-name is required")
+			return invalidArgsf("--fact-name is required")
 		}
 		if sourceNodeName == "" {
-			return fmt.Errorf("--source-node is required")
+			return invalidArgsf("--source-node is required")
 		}
 		if targetNodeName == "" {
-			return fmt.Errorf("--target-node is required")
+			return invalidArgsf("--target-node is required")
 		}
 
 		c, err := client.New()
@@ -389,7 +472,7 @@ Example: --source-attrs '{"type": "Person", "age": 30}'`,
 		if sourceAttrsStr != "" {
 			var sourceAttrs map[string]interface{}
 			if err := json.Unmarshal([]byte(sourceAttrsStr), &sourceAttrs); err != nil {
-				return fmt.Errorf("parsing source-attrs: %w", err)
+				return asInvalidArgs(fmt.Errorf("parsing source-attrs: %w", err))
 			}
 			req.SourceNodeAttributes = sourceAttrs
 		}
@@ -398,7 +481,7 @@ Example: --source-attrs '{"type": "Person", "age": 30}'`,
 		if edgeAttrsStr != "" {
 			var edgeAttrs map[string]interface{}
 			if err := json.Unmarshal([]byte(edgeAttrsStr), &edgeAttrs); err != nil {
-				return fmt.Errorf("parsing edge-attrs: %w", err)
+				return asInvalidArgs(fmt.Errorf("parsing edge-attrs: %w", err))
 			}
 			req.EdgeAttributes = edgeAttrs
 		}
@@ -407,7 +490,7 @@ Example: --source-attrs '{"type": "Person", "age": 30}'`,
 		if targetAttrsStr != "" {
 			var targetAttrs map[string]interface{}
 			if err := json.Unmarshal([]byte(targetAttrsStr), &targetAttrs); err != nil {
-				return fmt.Errorf("parsing target-attrs: %w", err)
+				return asInvalidArgs(fmt.Errorf("parsing target-attrs: %w", err))
 			}
 			req.TargetNodeAttributes = targetAttrs
 		}
@@ -464,7 +547,7 @@ Date filters allow filtering by date fields (created_at, valid_at, invalid_at, e
 		dateFilters, _ := cmd.Flags().GetStringArray("date-filter")
 
 		if userID == "" && graphID == "" {
-			return fmt.Errorf("either --user or --graph is required")
+			return invalidArgsf("either --user or --graph is required")
 		}
 
 		c, err := client.New()
@@ -528,7 +611,7 @@ Date filters allow filtering by date fields (created_at, valid_at, invalid_at, e
 			if len(propertyFilters) > 0 {
 				parsedFilters, err := parsePropertyFilters(propertyFilters)
 				if err != nil {
-					return err
+					return asInvalidArgs(err)
 				}
 				req.SearchFilters.PropertyFilters = parsedFilters
 			}
@@ -536,7 +619,7 @@ Date filters allow filtering by date fields (created_at, valid_at, invalid_at, e
 			// Parse date filters
 			if len(dateFilters) > 0 {
 				if err := parseDateFilters(dateFilters, req.SearchFilters); err != nil {
-					return err
+					return asInvalidArgs(err)
 				}
 			}
 		}
@@ -546,38 +629,11 @@ Date filters allow filtering by date fields (created_at, valid_at, invalid_at, e
 			return fmt.Errorf("searching graph: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable && scope == "edges" {
-			tbl := output.NewTable("UUID", "FACT", "VALID AT", "INVALID AT")
-			tbl.WriteHeader()
-			for _, e := range resp.Edges {
-				fact := e.Fact
-				if len(fact) > 60 {
-					fact = fact[:60] + "..."
-				}
-				validAt := ""
-				if e.ValidAt != nil {
-					validAt = *e.ValidAt
-				}
-				invalidAt := ""
-				if e.InvalidAt != nil {
-					invalidAt = *e.InvalidAt
-				}
-				tbl.WriteRow(e.UUID, fact, validAt, invalidAt)
-			}
-			return tbl.Flush()
-		}
-
-		if output.GetFormat() == output.FormatTable && scope == "nodes" {
-			tbl := output.NewTable("UUID", "NAME", "SUMMARY")
-			tbl.WriteHeader()
-			for _, n := range resp.Nodes {
-				summary := n.Summary
-				if len(summary) > 50 {
-					summary = summary[:50] + "..."
-				}
-				tbl.WriteRow(n.UUID, n.Name, summary)
-			}
-			return tbl.Flush()
+		switch {
+		case output.IsTabular() && scope == "edges":
+			return printList(edgeColumns(newNodeNames(c)).WithDefault("UUID", "FACT", "VALID AT", "INVALID AT"), resp.Edges, resp)
+		case output.IsTabular() && scope == "nodes":
+			return printList(nodeColumns.WithDefault("UUID", "NAME", "SUMMARY"), resp.Nodes, resp)
 		}
 
 		return output.Print(resp)
@@ -770,6 +826,7 @@ func init() {
 	// List flags
 	graphListCmd.Flags().Int("page", 1, "Page number")
 	graphListCmd.Flags().Int("page-size", 50, "Results per page")
+	addPaginationFlags(graphListCmd)
 
 	// Delete flags
 	graphDeleteCmd.Flags().Bool("force", false, "Skip confirmation prompt")
@@ -779,7 +836,8 @@ func init() {
 	graphCloneCmd.Flags().String("target-user", "", "Target user ID (for user graphs)")
 	graphCloneCmd.Flags().String("source-graph", "", "Source graph ID (for standalone graphs)")
 	graphCloneCmd.Flags().String("target-graph", "", "Target graph ID (for standalone graphs)")
-	graphCloneCmd.Flags().Bool("wait", false, "Wait for clone operation to complete")
+	graphCloneCmd.Flags().Bool("wait", false, "Wait until the clone task completes and the target graph exists")
+	addWaitFlags(graphCloneCmd)
 
 	// Add flags
 	graphAddCmd.Flags().String("type", "text", "Data type: text, json, message")
@@ -787,8 +845,14 @@ func init() {
 	graphAddCmd.Flags().String("file", "", "Path to data file")
 	graphAddCmd.Flags().Bool("stdin", false, "Read data from stdin")
 	graphAddCmd.Flags().String("user", "", "Add to user graph instead of standalone graph")
-	graphAddCmd.Flags().Bool("batch", false, "Enable batch processing (up to 20 episodes)")
-	graphAddCmd.Flags().Bool("wait", false, "Wait for ingestion to complete")
+	graphAddCmd.Flags().String("created-at", "", "When the episode happened (RFC 3339 or YYYY-MM-DD), for backfilling")
+	graphAddCmd.Flags().String("source-description", "", "Description of where the data came from")
+	graphAddCmd.Flags().Bool("batch", false, "Add episodes from a JSON, JSONL or YAML file, sent in batches of up to 20")
+	graphAddCmd.Flags().String("input-format", "", "Batch input format: json, jsonl, or yaml (default from the file extension, json for stdin)")
+	graphAddCmd.Flags().Int("concurrency", 1, "Number of batches to send in parallel (with --batch); above 1, episodes without created_at get the start time of the run as created_at, to keep their order")
+	graphAddCmd.Flags().String("results", "", "Results file mapping input index to episode UUID, used to resume (with --batch)")
+	graphAddCmd.Flags().Bool("wait", false, "Wait until every added episode is processed")
+	addWaitFlags(graphAddCmd)
 
 	// Add-fact flags
 	graphAddFactCmd.Flags().String("user", "", "Add to user graph")
@@ -0,0 +1,274 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/output"
+)
+
+// maxBatchEpisodes is the most episodes Graph.AddBatch accepts per call.
+const maxBatchEpisodes = 20
+
+// episodeTimeLayout is RFC 3339 with fixed microseconds, so stamped
+// created_at values also sort as strings.
+const episodeTimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// batchResult records the episode created for one input episode. Results
+// files hold one per line after the header.
+type batchResult struct {
+	Index int    `json:"index"`
+	UUID  string `json:"uuid"`
+}
+
+// batchResultsHeader is the first line of a results file. It ties the file to
+// the graph and input it was written for, and holds the base time of stamped
+// created_at values so a resumed run stamps the same times.
+type batchResultsHeader struct {
+	Target      string `json:"target"`
+	InputSHA256 string `json:"input_sha256"`
+	BaseTime    string `json:"base_time,omitempty"`
+}
+
+// episodeChunk is a run of input episodes sent in one AddBatch call.
+type episodeChunk struct {
+	indexes  []int
+	episodes []*zep.EpisodeData
+}
+
+// batchAdder adds episodes to one graph in chunks of maxBatchEpisodes, with
+// up to concurrency chunks in flight.
+type batchAdder struct {
+	c           *client.Client
+	userID      string
+	graphID     string
+	concurrency int
+	// resultsPath is the results file used to resume, or "" for none.
+	resultsPath string
+}
+
+// add sends the episodes that are not in the results file yet and returns
+// the episodes created by this run in input order, and the number of
+// episodes skipped because an earlier run added them.
+//
+// With a concurrency of 1, chunks are sent one at a time in input order and
+// episodes are sent as given. Concurrent chunks may complete in any order,
+// so episodes without a created_at are then given increasing timestamps
+// first: Zep orders the episodes of a graph by created_at, which keeps them
+// in input order.
+func (b *batchAdder) add(ctx context.Context, episodes []*zep.EpisodeData) ([]*zep.Episode, int, error) {
+	sum, err := episodesSHA256(episodes)
+	if err != nil {
+		return nil, 0, err
+	}
+	header := &batchResultsHeader{Target: graphLabel(b.userID, b.graphID), InputSHA256: sum}
+	prev, done, err := readBatchResults(b.resultsPath)
+	if err != nil {
+		return nil, 0, err
+	}
+	if prev != nil {
+		switch {
+		case prev.Target != header.Target:
+			return nil, 0, invalidArgsf("results file %s is for %s, not %s; remove it or pass another --results", b.resultsPath, prev.Target, header.Target)
+		case prev.InputSHA256 != header.InputSHA256:
+			return nil, 0, invalidArgsf("results file %s was written for different input; remove it or pass another --results", b.resultsPath)
+		case prev.BaseTime == "" && b.concurrency > 1:
+			return nil, 0, invalidArgsf("results file %s was written with --concurrency 1; resume it with --concurrency 1", b.resultsPath)
+		}
+		header = prev
+	}
+
+	var base time.Time
+	if header.BaseTime != "" {
+		if base, err = time.Parse(time.RFC3339Nano, header.BaseTime); err != nil {
+			return nil, 0, asInvalidArgs(fmt.Errorf("results file %s: invalid base_time: %w", b.resultsPath, err))
+		}
+	} else if b.concurrency > 1 {
+		base = time.Now().UTC().Truncate(time.Microsecond)
+		header.BaseTime = base.Format(episodeTimeLayout)
+	}
+
+	var chunks []*episodeChunk
+	skipped, stamped := 0, 0
+	for i, e := range episodes {
+		if _, ok := done[i]; ok {
+			skipped++
+			continue
+		}
+		if e.CreatedAt == nil && !base.IsZero() {
+			e.CreatedAt = zep.String(base.Add(time.Duration(i) * time.Microsecond).Format(episodeTimeLayout))
+			stamped++
+		}
+		if len(chunks) == 0 || len(chunks[len(chunks)-1].indexes) == maxBatchEpisodes {
+			chunks = append(chunks, &episodeChunk{})
+		}
+		chunk := chunks[len(chunks)-1]
+		chunk.indexes = append(chunk.indexes, i)
+		chunk.episodes = append(chunk.episodes, e)
+	}
+	if len(chunks) == 0 {
+		return []*zep.Episode{}, skipped, nil
+	}
+	if stamped > 0 {
+		output.Warn("%d episodes without created_at are given created_at values from %s onward, to keep their order across concurrent batches; use --concurrency 1 to send them without one", stamped, header.BaseTime)
+	}
+
+	var results *json.Encoder
+	if b.resultsPath != "" {
+		f, err := os.OpenFile(b.resultsPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, skipped, fmt.Errorf("opening results file: %w", err)
+		}
+		defer f.Close()
+		results = json.NewEncoder(f)
+		if prev == nil {
+			if err := results.Encode(header); err != nil {
+				return nil, skipped, fmt.Errorf("writing results file: %w", err)
+			}
+		}
+	}
+
+	progress := output.NewProgress("Adding episodes").WithTotal(len(episodes) - skipped)
+	created := make([]*zep.Episode, len(episodes))
+	var mu sync.Mutex
+	record := func(chunk *episodeChunk, resp []*zep.Episode) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for i, ep := range resp {
+			created[chunk.indexes[i]] = ep
+			if results != nil {
+				if err := results.Encode(batchResult{Index: chunk.indexes[i], UUID: ep.UUID}); err != nil {
+					return fmt.Errorf("writing results file: %w", err)
+				}
+			}
+		}
+		progress.Add("episodes", len(resp))
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jobs := make(chan *episodeChunk)
+	errs := make(chan error, b.concurrency)
+	var wg sync.WaitGroup
+	for range b.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				if err := b.send(ctx, chunk, record); err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+dispatch:
+	for _, chunk := range chunks {
+		select {
+		case jobs <- chunk:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	progress.Done()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		if b.resultsPath != "" {
+			return nil, skipped, fmt.Errorf("%w; progress was saved to %s, run the command again to resume", err, b.resultsPath)
+		}
+		return nil, skipped, err
+	}
+
+	added := make([]*zep.Episode, 0, len(chunks)*maxBatchEpisodes)
+	for _, ep := range created {
+		if ep != nil {
+			added = append(added, ep)
+		}
+	}
+	return added, skipped, nil
+}
+
+func (b *batchAdder) send(ctx context.Context, chunk *episodeChunk, record func(*episodeChunk, []*zep.Episode) error) error {
+	req := &zep.AddDataBatchRequest{Episodes: chunk.episodes}
+	if b.userID != "" {
+		req.UserID = zep.String(b.userID)
+	} else {
+		req.GraphID = zep.String(b.graphID)
+	}
+
+	first, last := chunk.indexes[0], chunk.indexes[len(chunk.indexes)-1]
+	resp, err := b.c.Graph.AddBatch(ctx, req)
+	if err != nil {
+		return fmt.Errorf("adding episodes %d-%d: %w", first, last, err)
+	}
+	if len(resp) != len(chunk.episodes) {
+		return fmt.Errorf("adding episodes %d-%d: expected %d episodes in response, got %d", first, last, len(chunk.episodes), len(resp))
+	}
+	return record(chunk, resp)
+}
+
+// readBatchResults reads the header of a results file and the input indexes
+// it records. A missing or empty file means nothing was added yet and has no
+// header.
+func readBatchResults(path string) (*batchResultsHeader, map[int]string, error) {
+	done := make(map[int]string)
+	if path == "" {
+		return nil, done, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, done, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening results file: %w", err)
+	}
+	defer f.Close()
+
+	var header *batchResultsHeader
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if line == 1 {
+			header = &batchResultsHeader{}
+			if err := json.Unmarshal(scanner.Bytes(), header); err != nil || header.Target == "" {
+				return nil, nil, invalidArgsf("%s line 1: not a results file header", path)
+			}
+			continue
+		}
+		var r batchResult
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, nil, asInvalidArgs(fmt.Errorf("%s line %d: %w", path, line, err))
+		}
+		done[r.Index] = r.UUID
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading results file: %w", err)
+	}
+	return header, done, nil
+}
+
+// episodesSHA256 returns the hex SHA-256 of the episodes as JSON, which
+// identifies the input a results file was written for.
+func episodesSHA256(episodes []*zep.EpisodeData) (string, error) {
+	data, err := json.Marshal(episodes)
+	if err != nil {
+		return "", fmt.Errorf("encoding episodes: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/getzep/zepctl/internal/sandbox"
+)
+
+// writeEpisodes writes a batch file with n text episodes whose data is
+// "episode <index>".
+func writeEpisodes(t *testing.T, dir string, n int) string {
+	t.Helper()
+	var input EpisodeInput
+	for i := range n {
+		input.Episodes = append(input.Episodes, EpisodeData{Type: "text", Data: fmt.Sprintf("episode %d", i)})
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "episodes.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+type addedEpisode struct {
+	UUID      string `json:"uuid"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"created_at"`
+}
+
+func addBatch(t *testing.T, server *sandbox.Server, args ...string) []addedEpisode {
+	t.Helper()
+	out, err := runCLI(t, server, append([]string{"graph", "add", "g1", "--batch", "-o", "json"}, args...)...)
+	if err != nil {
+		t.Fatalf("graph add --batch: %v", err)
+	}
+	var episodes []addedEpisode
+	if err := json.Unmarshal([]byte(out), &episodes); err != nil {
+		t.Fatalf("parsing output %q: %v", out, err)
+	}
+	return episodes
+}
+
+func TestGraphAddBatchChunks(t *testing.T) {
+	server := newSandbox(t)
+	for _, args := range [][]string{{"graph", "create", "g1"}, {"graph", "create", "g2"}} {
+		if _, err := runCLI(t, server, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dir := t.TempDir()
+	path := writeEpisodes(t, dir, 45)
+
+	// Sequential chunks send the episodes as given, without a results file
+	// unless one is asked for.
+	episodes := addBatch(t, server, "--file", path)
+	if len(episodes) != 45 {
+		t.Fatalf("added %d episodes, want 45", len(episodes))
+	}
+	for i, ep := range episodes {
+		if want := fmt.Sprintf("episode %d", i); ep.Content != want {
+			t.Errorf("episode %d content = %q, want %q", i, ep.Content, want)
+		}
+	}
+	if _, err := os.Stat(path + ".results.jsonl"); !os.IsNotExist(err) {
+		t.Errorf("results file written without --results: %v", err)
+	}
+
+	// Concurrent chunks stamp increasing created_at values.
+	results := filepath.Join(dir, "results.jsonl")
+	episodes = addBatch(t, server, "--file", path, "--concurrency", "3", "--results", results)
+	if len(episodes) != 45 {
+		t.Fatalf("added %d episodes, want 45", len(episodes))
+	}
+	for i, ep := range episodes {
+		if i > 0 && ep.CreatedAt <= episodes[i-1].CreatedAt {
+			t.Errorf("episode %d created_at %s is not after %s", i, ep.CreatedAt, episodes[i-1].CreatedAt)
+		}
+	}
+	header, done, err := readBatchResults(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header == nil || header.Target != `graph "g1"` || header.BaseTime == "" {
+		t.Errorf("results header = %+v, want graph g1 with a base time", header)
+	}
+	for i, ep := range episodes {
+		if done[i] != ep.UUID {
+			t.Errorf("results[%d] = %q, want %q", i, done[i], ep.UUID)
+		}
+	}
+
+	// Everything is in the results file, so a second run adds nothing.
+	if episodes := addBatch(t, server, "--file", path, "--concurrency", "3", "--results", results); len(episodes) != 0 {
+		t.Errorf("second run added %d episodes, want 0", len(episodes))
+	}
+
+	// A partial results file resumes with the missing episodes, stamped from
+	// the first run's base time.
+	data, err := os.ReadFile(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Chunks complete in any order, so keep the header and indexes 0-9.
+	lines := strings.SplitAfter(string(data), "\n")
+	partial := lines[0]
+	for _, line := range lines[1:] {
+		var r batchResult
+		if json.Unmarshal([]byte(line), &r) == nil && r.Index < 10 {
+			partial += line
+		}
+	}
+	if err := os.WriteFile(results, []byte(partial), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resumed := addBatch(t, server, "--file", path, "--concurrency", "3", "--results", results)
+	if len(resumed) != 35 || resumed[0].Content != "episode 10" || resumed[34].Content != "episode 44" {
+		t.Fatalf("resumed run added %d episodes, want episodes 10-44", len(resumed))
+	}
+	for i, ep := range resumed {
+		if ep.CreatedAt != episodes[i+10].CreatedAt {
+			t.Errorf("resumed episode %d created_at %s, want %s", i+10, ep.CreatedAt, episodes[i+10].CreatedAt)
+		}
+	}
+	if _, done, err = readBatchResults(results); err != nil || len(done) != 45 {
+		t.Errorf("results file has %d entries (err: %v), want 45", len(done), err)
+	}
+
+	// The results file only resumes the graph and input it was written for.
+	tests := [][]string{
+		{"graph", "add", "g2", "--batch", "--file", path, "--concurrency", "3", "--results", results},
+		{"graph", "add", "g1", "--batch", "--file", writeEpisodes(t, t.TempDir(), 46), "--concurrency", "3", "--results", results},
+		{"graph", "add", "g1", "--batch", "--file", path, "--concurrency", "0"},
+	}
+	for _, args := range tests {
+		if _, err := runCLI(t, server, args...); ExitCode(err) != ExitInvalidArgs {
+			t.Errorf("zepctl %s: exit code %d, want %d (err: %v)", strings.Join(args, " "), ExitCode(err), ExitInvalidArgs, err)
+		}
+	}
+}
+
+func TestGraphAddEpisodeMetadata(t *testing.T) {
+	server := newSandbox(t)
+	if _, err := runCLI(t, server, "graph", "create", "g1"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, server, "graph", "add", "g1", "--data", "Q3 report", "--created-at", "2023-10-01", "--source-description", "reports/q3.md", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var episode struct {
+		CreatedAt         string `json:"created_at"`
+		SourceDescription string `json:"source_description"`
+	}
+	if err := json.Unmarshal([]byte(out), &episode); err != nil {
+		t.Fatal(err)
+	}
+	if episode.CreatedAt != "2023-10-01T00:00:00Z" || episode.SourceDescription != "reports/q3.md" {
+		t.Errorf("episode = %+v", episode)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "episodes.json")
+	batch := `{"episodes": [
+		{"type": "text", "data": "old", "created_at": "2020-05-01T09:00:00Z", "source_description": "archive"},
+		{"type": "text", "data": "new"}
+	]}`
+	if err := os.WriteFile(path, []byte(batch), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	episodes := addBatch(t, server, "--file", path)
+	if len(episodes) != 2 || episodes[0].CreatedAt != "2020-05-01T09:00:00Z" {
+		t.Errorf("batch episodes = %+v, want the first created at 2020-05-01T09:00:00Z", episodes)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"episodes": [{"type": "text", "data": "x", "created_at": "last week"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := [][]string{
+		{"graph", "add", "g1", "--data", "x", "--created-at", "last week"},
+		{"graph", "add", "g1", "--batch", "--file", bad},
+		{"graph", "add", "g1", "--batch", "--file", path, "--source-description", "archive"},
+	}
+	for _, args := range tests {
+		if _, err := runCLI(t, server, args...); ExitCode(err) != ExitInvalidArgs {
+			t.Errorf("zepctl %s: exit code %d, want %d (err: %v)", strings.Join(args, " "), ExitCode(err), ExitInvalidArgs, err)
+		}
+	}
+}
@@ -0,0 +1,474 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/pagination"
+	"github.com/spf13/cobra"
+)
+
+// snapshotPageSize is the page size used when fetching every node and edge of
+// a graph.
+const snapshotPageSize = 100
+
+// Kinds and changes reported by graph diff.
+const (
+	diffNode    = "node"
+	diffFact    = "fact"
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffChanged = "changed"
+)
+
+var graphSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save the nodes and edges of a graph to a file",
+	Long: `Save every node and edge of a user graph or standalone graph to a JSON file,
+for comparing against later with 'zepctl graph diff'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userID, _ := cmd.Flags().GetString("user")
+		graphID, _ := cmd.Flags().GetString("graph")
+		out, _ := cmd.Flags().GetString("out")
+
+		if userID == "" && graphID == "" {
+			return invalidArgsf("either --user or --graph is required")
+		}
+		if out == "" {
+			return invalidArgsf("--out is required")
+		}
+
+		c, err := client.New()
+		if err != nil {
+			return err
+		}
+
+		snapshot, err := fetchGraphSnapshot(context.Background(), c, userID, graphID)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding snapshot: %w", err)
+		}
+		if err := os.WriteFile(out, append(data, '\n'), 0o600); err != nil {
+			return fmt.Errorf("writing snapshot: %w", err)
+		}
+
+		output.Info("Saved %d nodes and %d edges to %s", len(snapshot.Nodes), len(snapshot.Edges), out)
+		return nil
+	},
+}
+
+var graphDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two graphs or a graph against a snapshot",
+	Long: `Compare the nodes and facts of two graphs. Each side is a user graph, a
+standalone graph or a snapshot file written by 'zepctl graph snapshot'.
+
+Nodes are matched by name and facts by their text, so graphs with different
+UUIDs, such as a graph and its clone, can be compared. Matched nodes are
+compared by summary, labels and attributes; matched facts by edge name,
+source and target node, attributes and validity window (valid_at, invalid_at,
+expired_at).
+
+Use --unified for a unified text diff instead of the configured output format.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		unified, _ := cmd.Flags().GetBool("unified")
+
+		sides := make([]diffSide, 2)
+		for i, prefix := range []string{"source", "target"} {
+			side, err := diffSideFromFlags(cmd, prefix)
+			if err != nil {
+				return err
+			}
+			sides[i] = side
+		}
+
+		var c *client.Client
+		if sides[0].snapshot == "" || sides[1].snapshot == "" {
+			var err error
+			if c, err = client.New(); err != nil {
+				return err
+			}
+		}
+
+		snapshots := make([]*graphSnapshot, 2)
+		for i, side := range sides {
+			snapshot, err := side.load(context.Background(), c)
+			if err != nil {
+				return err
+			}
+			snapshots[i] = snapshot
+		}
+
+		result := diffGraphs(snapshots[0], snapshots[1])
+		result.Source, result.Target = sides[0].String(), sides[1].String()
+
+		if unified {
+			return writeUnifiedDiff(os.Stdout, result)
+		}
+
+		s := result.Summary
+		output.Info("Nodes: %d added, %d removed, %d changed. Facts: %d added, %d removed, %d changed.",
+			s.NodesAdded, s.NodesRemoved, s.NodesChanged, s.FactsAdded, s.FactsRemoved, s.FactsChanged)
+		return printList(diffColumns, result.Changes, result)
+	},
+}
+
+// diffColumns is the column registry for graph diff changes.
+var diffColumns = output.Columns[*graphChange]{
+	{Name: "KIND", Value: func(c *graphChange) string { return c.Kind }},
+	{Name: "CHANGE", Value: func(c *graphChange) string { return c.Change }},
+	{Name: "NAME", Value: func(c *graphChange) string { return output.Truncate(c.Name, 50) }},
+	{Name: "CHANGED FIELDS", Value: func(c *graphChange) string {
+		if c.Change != diffChanged {
+			return ""
+		}
+		fields := make([]string, len(c.Fields))
+		for i, f := range c.Fields {
+			fields[i] = f.Field
+		}
+		return strings.Join(fields, ",")
+	}},
+}
+
+// graphSnapshot holds every node and edge of a graph at a point in time.
+type graphSnapshot struct {
+	CreatedAt string            `json:"created_at"`
+	UserID    string            `json:"user_id,omitempty"`
+	GraphID   string            `json:"graph_id,omitempty"`
+	Nodes     []*zep.EntityNode `json:"nodes"`
+	Edges     []*zep.EntityEdge `json:"edges"`
+}
+
+func fetchGraphSnapshot(ctx context.Context, c *client.Client, userID, graphID string) (*graphSnapshot, error) {
+	snapshot := &graphSnapshot{
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		UserID:    userID,
+		GraphID:   graphID,
+		Nodes:     []*zep.EntityNode{},
+		Edges:     []*zep.EntityEdge{},
+	}
+
+	nodes := pagination.NewCursorIterator("", snapshotPageSize, nodePageFetcher(c, userID, graphID), func(n *zep.EntityNode) string {
+		return n.UUID
+	})
+	err := nodes.ForEachPage(ctx, func(page []*zep.EntityNode) error {
+		snapshot.Nodes = append(snapshot.Nodes, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	edges := pagination.NewCursorIterator("", snapshotPageSize, edgePageFetcher(c, userID, graphID), func(e *zep.EntityEdge) string {
+		return e.UUID
+	})
+	err = edges.ForEachPage(ctx, func(page []*zep.EntityEdge) error {
+		snapshot.Edges = append(snapshot.Edges, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// diffSide is one side of a graph diff: a user graph, a standalone graph or a
+// snapshot file.
+type diffSide struct {
+	userID   string
+	graphID  string
+	snapshot string
+}
+
+// diffSideFromFlags reads the --<prefix>-user, --<prefix>-graph and
+// --<prefix>-snapshot flags, exactly one of which must be set.
+func diffSideFromFlags(cmd *cobra.Command, prefix string) (diffSide, error) {
+	var side diffSide
+	side.userID, _ = cmd.Flags().GetString(prefix + "-user")
+	side.graphID, _ = cmd.Flags().GetString(prefix + "-graph")
+	side.snapshot, _ = cmd.Flags().GetString(prefix + "-snapshot")
+
+	set := 0
+	for _, v := range []string{side.userID, side.graphID, side.snapshot} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return side, invalidArgsf("exactly one of --%[1]s-user, --%[1]s-graph or --%[1]s-snapshot is required", prefix)
+	}
+	return side, nil
+}
+
+func (s diffSide) String() string {
+	switch {
+	case s.userID != "":
+		return "user:" + s.userID
+	case s.graphID != "":
+		return "graph:" + s.graphID
+	default:
+		return s.snapshot
+	}
+}
+
+func (s diffSide) load(ctx context.Context, c *client.Client) (*graphSnapshot, error) {
+	if s.snapshot == "" {
+		return fetchGraphSnapshot(ctx, c, s.userID, s.graphID)
+	}
+
+	data, err := os.ReadFile(s.snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+	var snapshot graphSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, invalidArgsf("parsing snapshot %s: %v", s.snapshot, err)
+	}
+	return &snapshot, nil
+}
+
+// graphDiff is the result of comparing two graphs.
+type graphDiff struct {
+	Source  string         `json:"source"`
+	Target  string         `json:"target"`
+	Summary diffSummary    `json:"summary"`
+	Changes []*graphChange `json:"changes"`
+}
+
+type diffSummary struct {
+	NodesAdded   int `json:"nodes_added"`
+	NodesRemoved int `json:"nodes_removed"`
+	NodesChanged int `json:"nodes_changed"`
+	FactsAdded   int `json:"facts_added"`
+	FactsRemoved int `json:"facts_removed"`
+	FactsChanged int `json:"facts_changed"`
+}
+
+// graphChange is a node or fact that was added, removed or changed. Name is
+// the node name or fact text. Fields lists the compared fields that differ;
+// for added and removed items it holds the item's fields on the side where it
+// exists.
+type graphChange struct {
+	Kind   string        `json:"kind"`
+	Change string        `json:"change"`
+	Name   string        `json:"name"`
+	Fields []fieldChange `json:"fields,omitempty"`
+}
+
+type fieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// diffItem is a node or edge reduced to its match key and compared fields.
+type diffItem struct {
+	key    string
+	name   string
+	fields []fieldChange // only Field and To are used
+}
+
+// diffGraphs compares two snapshots. Nodes are matched by name and facts by
+// their text, case-insensitively. When several items share a key they are
+// paired in order of creation.
+func diffGraphs(from, to *graphSnapshot) *graphDiff {
+	result := &graphDiff{Changes: []*graphChange{}}
+
+	nodeChanges := diffItems(diffNode, nodeItems(from), nodeItems(to))
+	factChanges := diffItems(diffFact, edgeItems(from), edgeItems(to))
+	result.Changes = append(nodeChanges, factChanges...)
+
+	counts := map[string]*int{
+		diffNode + diffAdded:   &result.Summary.NodesAdded,
+		diffNode + diffRemoved: &result.Summary.NodesRemoved,
+		diffNode + diffChanged: &result.Summary.NodesChanged,
+		diffFact + diffAdded:   &result.Summary.FactsAdded,
+		diffFact + diffRemoved: &result.Summary.FactsRemoved,
+		diffFact + diffChanged: &result.Summary.FactsChanged,
+	}
+	for _, c := range result.Changes {
+		*counts[c.Kind+c.Change]++
+	}
+	return result
+}
+
+func diffKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func nodeItems(s *graphSnapshot) []diffItem {
+	nodes := slices.Clone(s.Nodes)
+	slices.SortStableFunc(nodes, func(a, b *zep.EntityNode) int {
+		return cmp.Or(cmp.Compare(diffKey(a.Name), diffKey(b.Name)), cmp.Compare(a.CreatedAt, b.CreatedAt))
+	})
+
+	items := make([]diffItem, len(nodes))
+	for i, n := range nodes {
+		labels := slices.Clone(n.Labels)
+		slices.Sort(labels)
+		items[i] = diffItem{
+			key:  diffKey(n.Name),
+			name: n.Name,
+			fields: []fieldChange{
+				{Field: "summary", To: n.Summary},
+				{Field: "labels", To: strings.Join(labels, ",")},
+				{Field: "attributes", To: attributesString(n.Attributes)},
+			},
+		}
+	}
+	return items
+}
+
+func edgeItems(s *graphSnapshot) []diffItem {
+	names := make(map[string]string, len(s.Nodes))
+	for _, n := range s.Nodes {
+		names[n.UUID] = n.Name
+	}
+	nodeName := func(uuid string) string {
+		if name, ok := names[uuid]; ok {
+			return name
+		}
+		return uuid
+	}
+
+	edges := slices.Clone(s.Edges)
+	slices.SortStableFunc(edges, func(a, b *zep.EntityEdge) int {
+		return cmp.Or(cmp.Compare(diffKey(a.Fact), diffKey(b.Fact)), cmp.Compare(a.CreatedAt, b.CreatedAt))
+	})
+
+	items := make([]diffItem, len(edges))
+	for i, e := range edges {
+		items[i] = diffItem{
+			key:  diffKey(e.Fact),
+			name: e.Fact,
+			fields: []fieldChange{
+				{Field: "name", To: e.Name},
+				{Field: "source", To: nodeName(e.SourceNodeUUID)},
+				{Field: "target", To: nodeName(e.TargetNodeUUID)},
+				{Field: "attributes", To: attributesString(e.Attributes)},
+				{Field: "valid_at", To: stringValue(e.ValidAt)},
+				{Field: "invalid_at", To: stringValue(e.InvalidAt)},
+				{Field: "expired_at", To: stringValue(e.ExpiredAt)},
+			},
+		}
+	}
+	return items
+}
+
+// attributesString formats attributes for comparison. JSON encoding sorts map
+// keys, so equal attributes always format the same.
+func attributesString(attrs map[string]any) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(attrs)
+	if err != nil {
+		return fmt.Sprint(attrs)
+	}
+	return string(data)
+}
+
+// diffItems merges two key-sorted item lists into changes.
+func diffItems(kind string, from, to []diffItem) []*graphChange {
+	var changes []*graphChange
+	i, j := 0, 0
+	for i < len(from) || j < len(to) {
+		switch {
+		case j == len(to) || (i < len(from) && from[i].key < to[j].key):
+			changes = append(changes, &graphChange{Kind: kind, Change: diffRemoved, Name: from[i].name, Fields: presentFields(from[i].fields, false)})
+			i++
+		case i == len(from) || to[j].key < from[i].key:
+			changes = append(changes, &graphChange{Kind: kind, Change: diffAdded, Name: to[j].name, Fields: presentFields(to[j].fields, true)})
+			j++
+		default:
+			var fields []fieldChange
+			for k, f := range from[i].fields {
+				if g := to[j].fields[k]; f.To != g.To {
+					fields = append(fields, fieldChange{Field: f.Field, From: f.To, To: g.To})
+				}
+			}
+			if len(fields) > 0 {
+				changes = append(changes, &graphChange{Kind: kind, Change: diffChanged, Name: to[j].name, Fields: fields})
+			}
+			i++
+			j++
+		}
+	}
+	return changes
+}
+
+// presentFields returns the non-empty fields of an added item as To values,
+// or of a removed item as From values.
+func presentFields(fields []fieldChange, added bool) []fieldChange {
+	var present []fieldChange
+	for _, f := range fields {
+		if f.To == "" {
+			continue
+		}
+		if added {
+			present = append(present, fieldChange{Field: f.Field, To: f.To})
+		} else {
+			present = append(present, fieldChange{Field: f.Field, From: f.To})
+		}
+	}
+	return present
+}
+
+// writeUnifiedDiff writes changes in the style of diff -u: lines only in the
+// source are prefixed with "-", lines only in the target with "+" and the
+// names of changed items with " ".
+func writeUnifiedDiff(w io.Writer, d *graphDiff) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", d.Source, d.Target)
+	for _, c := range d.Changes {
+		switch c.Change {
+		case diffRemoved:
+			fmt.Fprintf(&b, "-%s %s\n", c.Kind, c.Name)
+			for _, f := range c.Fields {
+				fmt.Fprintf(&b, "-  %s: %s\n", f.Field, f.From)
+			}
+		case diffAdded:
+			fmt.Fprintf(&b, "+%s %s\n", c.Kind, c.Name)
+			for _, f := range c.Fields {
+				fmt.Fprintf(&b, "+  %s: %s\n", f.Field, f.To)
+			}
+		default:
+			fmt.Fprintf(&b, " %s %s\n", c.Kind, c.Name)
+			for _, f := range c.Fields {
+				fmt.Fprintf(&b, "-  %s: %s\n+  %s: %s\n", f.Field, f.From, f.Field, f.To)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func init() {
+	graphCmd.AddCommand(graphSnapshotCmd)
+	graphCmd.AddCommand(graphDiffCmd)
+
+	graphSnapshotCmd.Flags().String("user", "", "Snapshot user graph")
+	graphSnapshotCmd.Flags().String("graph", "", "Snapshot standalone graph")
+	graphSnapshotCmd.Flags().String("out", "", "Path of the snapshot file to write")
+
+	for _, prefix := range []string{"source", "target"} {
+		graphDiffCmd.Flags().String(prefix+"-user", "", fmt.Sprintf("User ID of the %s user graph", prefix))
+		graphDiffCmd.Flags().String(prefix+"-graph", "", fmt.Sprintf("Graph ID of the %s standalone graph", prefix))
+		graphDiffCmd.Flags().String(prefix+"-snapshot", "", fmt.Sprintf("Snapshot file to use as the %s", prefix))
+	}
+	graphDiffCmd.Flags().Bool("unified", false, "Print a unified text diff")
+}
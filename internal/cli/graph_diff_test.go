@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/getzep/zep-go/v3"
+)
+
+func TestDiffGraphs(t *testing.T) {
+	node := func(uuid, name, summary string) *zep.EntityNode {
+		return &zep.EntityNode{UUID: uuid, Name: name, Summary: summary, Labels: []string{"Entity"}}
+	}
+	edge := func(uuid, fact, source, target string, validAt *string) *zep.EntityEdge {
+		return &zep.EntityEdge{UUID: uuid, Name: "RELATES_TO", Fact: fact, SourceNodeUUID: source, TargetNodeUUID: target, ValidAt: validAt}
+	}
+
+	from := &graphSnapshot{
+		Nodes: []*zep.EntityNode{node("a1", "Alice", "Engineer"), node("b1", "Bob", ""), node("c1", "Acme", "")},
+		Edges: []*zep.EntityEdge{
+			edge("e1", "Alice works at Acme", "a1", "c1", nil),
+			edge("e2", "Bob knows Alice", "b1", "a1", nil),
+		},
+	}
+	// The target is a clone with new UUIDs: Bob is gone, Alice was promoted,
+	// Carol is new and the employment fact gained a validity window.
+	to := &graphSnapshot{
+		Nodes: []*zep.EntityNode{node("a2", "alice", "Manager"), node("c2", "Acme", ""), node("d2", "Carol", "")},
+		Edges: []*zep.EntityEdge{
+			edge("e3", "Alice works at Acme", "a2", "c2", zep.String("2024-01-01T00:00:00Z")),
+		},
+	}
+
+	got := diffGraphs(from, to)
+
+	// Node changes come first, each kind ordered by name or fact.
+	want := []*graphChange{
+		{Kind: diffNode, Change: diffChanged, Name: "alice", Fields: []fieldChange{
+			{Field: "summary", From: "Engineer", To: "Manager"},
+		}},
+		{Kind: diffNode, Change: diffRemoved, Name: "Bob", Fields: []fieldChange{{Field: "labels", From: "Entity"}}},
+		{Kind: diffNode, Change: diffAdded, Name: "Carol", Fields: []fieldChange{{Field: "labels", To: "Entity"}}},
+		{Kind: diffFact, Change: diffChanged, Name: "Alice works at Acme", Fields: []fieldChange{
+			{Field: "source", From: "Alice", To: "alice"},
+			{Field: "valid_at", To: "2024-01-01T00:00:00Z"},
+		}},
+		{Kind: diffFact, Change: diffRemoved, Name: "Bob knows Alice", Fields: []fieldChange{
+			{Field: "name", From: "RELATES_TO"},
+			{Field: "source", From: "Bob"},
+			{Field: "target", From: "Alice"},
+		}},
+	}
+
+	if !reflect.DeepEqual(got.Changes, want) {
+		for _, c := range got.Changes {
+			t.Logf("got %+v", *c)
+		}
+		t.Fatalf("unexpected changes")
+	}
+
+	wantSummary := diffSummary{NodesAdded: 1, NodesRemoved: 1, NodesChanged: 1, FactsRemoved: 1, FactsChanged: 1}
+	if got.Summary != wantSummary {
+		t.Errorf("summary = %+v, want %+v", got.Summary, wantSummary)
+	}
+
+	if same := diffGraphs(from, from); len(same.Changes) != 0 {
+		t.Errorf("diff of a graph with itself has %d changes", len(same.Changes))
+	}
+}
+
+func TestWriteUnifiedDiff(t *testing.T) {
+	d := &graphDiff{
+		Source: "graph:g1",
+		Target: "snapshot.json",
+		Changes: []*graphChange{
+			{Kind: diffNode, Change: diffChanged, Name: "Alice", Fields: []fieldChange{{Field: "summary", From: "Engineer", To: "Manager"}}},
+			{Kind: diffFact, Change: diffAdded, Name: "Alice works at Acme", Fields: []fieldChange{{Field: "name", To: "WORKS_AT"}}},
+			{Kind: diffFact, Change: diffRemoved, Name: "Bob knows Alice"},
+		},
+	}
+
+	var b strings.Builder
+	if err := writeUnifiedDiff(&b, d); err != nil {
+		t.Fatal(err)
+	}
+	want := `--- graph:g1
++++ snapshot.json
+ node Alice
+-  summary: Engineer
++  summary: Manager
++fact Alice works at Acme
++  name: WORKS_AT
+-fact Bob knows Alice
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
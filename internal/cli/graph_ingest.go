@@ -0,0 +1,429 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// maxEpisodeLength is the most characters of data the API accepts in one
+// episode.
+const maxEpisodeLength = 10000
+
+// ingestTypes maps file extensions to episode types. Files with other
+// extensions are only ingested when an --include pattern matches them.
+var ingestTypes = map[string]string{
+	".md":       "text",
+	".markdown": "text",
+	".mdx":      "text",
+	".txt":      "text",
+	".text":     "text",
+	".rst":      "text",
+	".json":     "json",
+	".chat":     "message",
+}
+
+var graphIngestCmd = &cobra.Command{
+	Use:   "ingest <dir>",
+	Short: "Add every document in a directory to a graph",
+	Long: `Walk a directory and add each document to a graph as one or more episodes.
+
+The episode type is chosen by file extension: .md, .markdown, .mdx, .txt,
+.text and .rst files are text, .json files are json and .chat files are
+message. Use --type-map to add or change extensions, e.g. --type-map .log=text.
+
+Without --include, every file with a known extension is ingested. Patterns
+without a slash match file names at any depth, patterns with a slash match
+the path relative to <dir>, and an --exclude pattern that matches a directory
+skips it. Hidden files and directories are always skipped.
+
+Files longer than --max-chars are split into several episodes: Markdown on
+headings, then on paragraphs, and text on paragraphs, then on lines. Message
+files are split on lines. JSON files cannot be split and are skipped with a
+warning when too long. Each episode's source description is the file's path
+relative to <dir>, and episodes are added in file order.`,
+	Example: `  zepctl graph ingest ./docs --graph kb
+  zepctl graph ingest ./notes --user user_123 --include '*.md' --exclude drafts
+  zepctl graph ingest ./docs --graph kb --type-map .log=text --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userID, _ := cmd.Flags().GetString("user")
+		graphID, _ := cmd.Flags().GetString("graph")
+		include, _ := cmd.Flags().GetStringArray("include")
+		exclude, _ := cmd.Flags().GetStringArray("exclude")
+		typeMap, _ := cmd.Flags().GetStringToString("type-map")
+		maxChars, _ := cmd.Flags().GetInt("max-chars")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		results, _ := cmd.Flags().GetString("results")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if (userID == "") == (graphID == "") {
+			return invalidArgsf("exactly one of --user or --graph is required")
+		}
+		if maxChars < 1 || maxChars > maxEpisodeLength {
+			return invalidArgsf("--max-chars must be between 1 and %d", maxEpisodeLength)
+		}
+		if concurrency < 1 {
+			return invalidArgsf("--concurrency must be at least 1")
+		}
+		for _, p := range slices.Concat(include, exclude) {
+			if _, err := path.Match(p, ""); err != nil {
+				return invalidArgsf("invalid pattern %q: %v", p, err)
+			}
+		}
+		types := ingestTypeMap(typeMap)
+		for ext, t := range types {
+			if t != "text" && t != "json" && t != "message" {
+				return invalidArgsf("invalid --type-map %s=%s: type must be text, json, or message", ext, t)
+			}
+		}
+
+		in := &ingester{
+			dir:      args[0],
+			include:  include,
+			exclude:  exclude,
+			types:    types,
+			maxChars: maxChars,
+		}
+		files, episodes, err := in.collect()
+		if err != nil {
+			return err
+		}
+		if len(episodes) == 0 {
+			return invalidArgsf("no files to ingest in %s", args[0])
+		}
+
+		if dryRun {
+			output.Info("Dry run: would add %d episodes from %d files", len(episodes), len(files))
+			return printList(ingestColumns, files, files)
+		}
+
+		c, err := client.New()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+
+		if len(episodes) == 1 {
+			e := episodes[0]
+			req := &zep.AddDataRequest{
+				Data:              e.Data,
+				Type:              e.Type,
+				SourceDescription: e.SourceDescription,
+			}
+			if userID != "" {
+				req.UserID = zep.String(userID)
+			} else {
+				req.GraphID = zep.String(graphID)
+			}
+			if _, err := c.Graph.Add(ctx, req); err != nil {
+				return fmt.Errorf("adding %s: %w", *e.SourceDescription, err)
+			}
+		} else {
+			adder := &batchAdder{
+				c:           c,
+				userID:      userID,
+				graphID:     graphID,
+				concurrency: concurrency,
+				resultsPath: results,
+			}
+			if _, _, err := adder.add(ctx, episodes); err != nil {
+				return fmt.Errorf("ingesting %s: %w", args[0], err)
+			}
+		}
+
+		output.Info("Added %d episodes from %d files", len(episodes), len(files))
+		return printList(ingestColumns, files, files)
+	},
+}
+
+// ingestFile describes a file found by graph ingest.
+type ingestFile struct {
+	Path       string `json:"path"`
+	Type       string `json:"type"`
+	Characters int    `json:"characters"`
+	Episodes   int    `json:"episodes"`
+}
+
+// ingestColumns is the column registry for graph ingest.
+var ingestColumns = output.Columns[*ingestFile]{
+	{Name: "PATH", Value: func(f *ingestFile) string { return f.Path }},
+	{Name: "TYPE", Value: func(f *ingestFile) string { return f.Type }},
+	{Name: "CHARACTERS", Value: func(f *ingestFile) string { return strconv.Itoa(f.Characters) }},
+	{Name: "EPISODES", Value: func(f *ingestFile) string { return strconv.Itoa(f.Episodes) }},
+}
+
+// ingestTypeMap returns the built-in extension types updated with --type-map.
+// Extensions are matched case-insensitively, with or without a leading dot.
+func ingestTypeMap(overrides map[string]string) map[string]string {
+	types := make(map[string]string, len(ingestTypes)+len(overrides))
+	for ext, t := range ingestTypes {
+		types[ext] = t
+	}
+	for ext, t := range overrides {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		types[ext] = t
+	}
+	return types
+}
+
+// ingester finds the documents under a directory and splits them into
+// episodes.
+type ingester struct {
+	dir      string
+	include  []string
+	exclude  []string
+	types    map[string]string
+	maxChars int
+}
+
+// collect walks the directory in lexical order and returns the files to
+// ingest and their episodes.
+func (in *ingester) collect() ([]*ingestFile, []*zep.EpisodeData, error) {
+	info, err := os.Stat(in.dir)
+	if err != nil {
+		return nil, nil, asInvalidArgs(fmt.Errorf("reading directory: %w", err))
+	}
+	if !info.IsDir() {
+		return nil, nil, invalidArgsf("%s is not a directory", in.dir)
+	}
+
+	var files []*ingestFile
+	var episodes []*zep.EpisodeData
+	err = filepath.WalkDir(in.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == in.dir {
+			return nil
+		}
+		rel, err := filepath.Rel(in.dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(d.Name(), ".") || matchesAny(in.exclude, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		episodeType, ok := in.types[strings.ToLower(path.Ext(rel))]
+		if len(in.include) > 0 {
+			if !matchesAny(in.include, rel) {
+				return nil
+			}
+			if !ok {
+				episodeType = "text"
+			}
+		} else if !ok {
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", p, err)
+		}
+		text := string(data)
+		if !utf8.ValidString(text) {
+			output.Warn("Skipping %s: not UTF-8 text", rel)
+			return nil
+		}
+		chunks := in.split(text, episodeType, path.Ext(rel))
+		if chunks == nil {
+			output.Warn("Skipping %s: JSON longer than %d characters cannot be split", rel, in.maxChars)
+			return nil
+		}
+		if len(chunks) == 0 {
+			return nil
+		}
+
+		files = append(files, &ingestFile{
+			Path:       rel,
+			Type:       episodeType,
+			Characters: utf8.RuneCountInString(text),
+			Episodes:   len(chunks),
+		})
+		for _, chunk := range chunks {
+			episodes = append(episodes, &zep.EpisodeData{
+				Data:              chunk,
+				Type:              zep.GraphDataType(episodeType),
+				SourceDescription: zep.String(rel),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("walking %s: %w", in.dir, err)
+	}
+	return files, episodes, nil
+}
+
+// split divides a document into chunks of at most maxChars characters. It
+// returns an empty slice for a blank document and nil for JSON that is too
+// long.
+func (in *ingester) split(text, episodeType, ext string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return []string{}
+	}
+	if utf8.RuneCountInString(text) <= in.maxChars {
+		return []string{text}
+	}
+	switch {
+	case episodeType == "json":
+		return nil
+	case episodeType == "message":
+		return splitChunks(text, in.maxChars, []string{"\n"})
+	case isMarkdown(ext):
+		var units []string
+		for _, section := range markdownSections(text) {
+			units = append(units, splitChunks(section, in.maxChars, []string{"\n\n", "\n", " "})...)
+		}
+		return packChunks(units, "\n\n", in.maxChars)
+	default:
+		return splitChunks(text, in.maxChars, []string{"\n\n", "\n", " "})
+	}
+}
+
+func isMarkdown(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".md", ".markdown", ".mdx":
+		return true
+	}
+	return false
+}
+
+// markdownSections splits a Markdown document before each ATX heading that
+// is not inside a fenced code block.
+func markdownSections(text string) []string {
+	var sections []string
+	var current []string
+	fence := ""
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case isHeading(trimmed) && len(current) > 0:
+			sections = append(sections, strings.TrimSpace(strings.Join(current, "\n")))
+			current = nil
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		sections = append(sections, strings.TrimSpace(strings.Join(current, "\n")))
+	}
+	return sections
+}
+
+// isHeading reports whether a line is an ATX heading such as "## Setup".
+func isHeading(line string) bool {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	return level >= 1 && level <= 6 && (len(line) == level || line[level] == ' ' || line[level] == '\t')
+}
+
+// splitChunks splits text on the first separator and packs the parts back
+// into chunks of at most limit characters. Parts that are still too long are
+// split on the next separator, and finally at limit characters.
+func splitChunks(text string, limit int, seps []string) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+	if len(seps) == 0 {
+		var chunks []string
+		runes := []rune(text)
+		for len(runes) > limit {
+			chunks = append(chunks, string(runes[:limit]))
+			runes = runes[limit:]
+		}
+		return append(chunks, string(runes))
+	}
+
+	var units []string
+	for _, part := range strings.Split(text, seps[0]) {
+		if part = strings.Trim(part, "\n"); strings.TrimSpace(part) != "" {
+			units = append(units, splitChunks(part, limit, seps[1:])...)
+		}
+	}
+	return packChunks(units, seps[0], limit)
+}
+
+// packChunks joins consecutive units with sep into chunks of at most limit
+// characters. Each unit must fit within limit.
+func packChunks(units []string, sep string, limit int) []string {
+	var chunks []string
+	var current strings.Builder
+	size := 0
+	for _, unit := range units {
+		n := utf8.RuneCountInString(unit)
+		if size > 0 && size+len(sep)+n > limit {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			size = 0
+		}
+		if size > 0 {
+			current.WriteString(sep)
+			size += len(sep)
+		}
+		current.WriteString(unit)
+		size += n
+	}
+	if size > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// matchesAny reports whether a slash-separated path relative to the ingested
+// directory matches one of the patterns. Patterns without a slash match the
+// base name.
+func matchesAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		name := rel
+		if !strings.Contains(p, "/") {
+			name = path.Base(rel)
+		}
+		if ok, _ := path.Match(strings.TrimPrefix(p, "./"), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	graphCmd.AddCommand(graphIngestCmd)
+
+	graphIngestCmd.Flags().String("graph", "", "Standalone graph to add the documents to")
+	graphIngestCmd.Flags().String("user", "", "User whose graph to add the documents to")
+	graphIngestCmd.Flags().StringArray("include", nil, "Only ingest files matching this glob (repeatable)")
+	graphIngestCmd.Flags().StringArray("exclude", nil, "Skip files and directories matching this glob (repeatable)")
+	graphIngestCmd.Flags().StringToString("type-map", nil, "Episode type by file extension, e.g. .log=text,.vtt=message")
+	graphIngestCmd.Flags().Int("max-chars", maxEpisodeLength, "Maximum characters per episode; longer files are split")
+	graphIngestCmd.Flags().Int("concurrency", 1, "Number of batches to send in parallel; above 1, episodes get the start time of the run as created_at, to keep file order")
+	graphIngestCmd.Flags().String("results", "", "Results file mapping episode index to UUID, used to resume")
+	graphIngestCmd.Flags().Bool("dry-run", false, "List the files and episode counts without adding anything")
+}
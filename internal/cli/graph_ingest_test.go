@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestIngesterSplit(t *testing.T) {
+	para := func(word string, n int) string {
+		return strings.TrimSpace(strings.Repeat(word+" ", n))
+	}
+	tests := []struct {
+		name  string
+		text  string
+		typ   string
+		ext   string
+		limit int
+		want  []string
+	}{
+		{"fits", "hello\n", "text", ".txt", 20, []string{"hello"}},
+		{"blank", "  \n\n", "text", ".txt", 20, []string{}},
+		{"paragraphs", "aaaa aaaa\n\nbbbb\n\ncccc", "text", ".txt", 12, []string{"aaaa aaaa", "bbbb\n\ncccc"}},
+		{"long line", "aaaa bbbb cccc", "text", ".txt", 9, []string{"aaaa bbbb", "cccc"}},
+		{"long word", "abcdefghij", "text", ".txt", 4, []string{"abcd", "efgh", "ij"}},
+		{"markdown headings", "# A\n" + para("a", 5) + "\n# B\n" + para("b", 5), "text", ".md", 20, []string{"# A\na a a a a", "# B\nb b b b b"}},
+		{"markdown fence", "# A\n```\n# not a heading\n```\n# B\nbbb", "text", ".md", 30, []string{"# A\n```\n# not a heading\n```", "# B\nbbb"}},
+		{"messages", "Alice: hi\nBob: hello\nAlice: bye", "message", ".chat", 20, []string{"Alice: hi\nBob: hello", "Alice: bye"}},
+		{"json too long", `{"a": "bbbbbbbbbbbbbbbbbbbb"}`, "json", ".json", 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := &ingester{maxChars: tt.limit}
+			got := in.split(tt.text, tt.typ, tt.ext)
+			if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("split = %q, want %q", got, tt.want)
+			}
+			for _, chunk := range got {
+				if n := utf8.RuneCountInString(chunk); n > tt.limit {
+					t.Errorf("chunk %q has %d characters, limit %d", chunk, n, tt.limit)
+				}
+			}
+		})
+	}
+}
+
+func TestGraphIngest(t *testing.T) {
+	server := newSandbox(t)
+	if _, err := runCLI(t, server, "graph", "create", "kb"); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	section := strings.Repeat("Some words about the product. ", 200)
+	files := map[string]string{
+		"guide.md":          "# Install\n" + section + "\n\n# Configure\n" + section,
+		"notes/todo.txt":    "Ship it",
+		"notes/facts.json":  `{"plan": "pro"}`,
+		"notes/server.log":  "started",
+		"drafts/wip.md":     "Not ready",
+		".git/config.md":    "hidden",
+		"images/logo.png":   "\x89PNG",
+		"transcripts/a.txt": "",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ingest := func(args ...string) []ingestFile {
+		t.Helper()
+		out, err := runCLI(t, server, append([]string{"graph", "ingest", dir, "--graph", "kb", "-o", "json"}, args...)...)
+		if err != nil {
+			t.Fatalf("graph ingest %v: %v", args, err)
+		}
+		var got []ingestFile
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("parsing output %q: %v", out, err)
+		}
+		return got
+	}
+
+	got := ingest("--exclude", "drafts", "--dry-run")
+	want := []ingestFile{
+		{Path: "guide.md", Type: "text", Characters: utf8.RuneCountInString(files["guide.md"]), Episodes: 2},
+		{Path: "notes/facts.json", Type: "json", Characters: 15, Episodes: 1},
+		{Path: "notes/todo.txt", Type: "text", Characters: 7, Episodes: 1},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("dry run = %+v, want %+v", got, want)
+	}
+	out, err := runCLI(t, server, "episode", "list", "--graph", "kb", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out) != "[]" {
+		t.Fatalf("dry run added episodes:\n%s", out)
+	}
+
+	ingest("--exclude", "drafts", "--include", "*.md", "--include", "*.log", "--type-map", "log=message")
+	out, err = runCLI(t, server, "episode", "list", "--graph", "kb", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var episodes []struct {
+		Content           string `json:"content"`
+		Source            string `json:"source"`
+		SourceDescription string `json:"source_description"`
+	}
+	if err := json.Unmarshal([]byte(out), &episodes); err != nil {
+		t.Fatal(err)
+	}
+	var sources []string
+	for _, ep := range episodes {
+		sources = append(sources, ep.SourceDescription+":"+ep.Source)
+	}
+	if want := []string{"guide.md:text", "guide.md:text", "notes/server.log:message"}; !slices.Equal(sources, want) {
+		t.Errorf("episodes = %v, want %v", sources, want)
+	}
+	if len(episodes) == 3 && !strings.HasPrefix(episodes[1].Content, "# Configure") {
+		t.Errorf("second guide.md episode starts %q, want the Configure section", episodes[1].Content[:20])
+	}
+}
+
+func TestGraphIngestInvalidArgs(t *testing.T) {
+	server := newSandbox(t)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.md"), []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := [][]string{
+		{"graph", "ingest", dir},
+		{"graph", "ingest", dir, "--graph", "kb", "--user", "alice"},
+		{"graph", "ingest", filepath.Join(dir, "missing"), "--graph", "kb"},
+		{"graph", "ingest", filepath.Join(dir, "a.md"), "--graph", "kb"},
+		{"graph", "ingest", dir, "--graph", "kb", "--max-chars", "10001"},
+		{"graph", "ingest", dir, "--graph", "kb", "--include", "[a-"},
+		{"graph", "ingest", dir, "--graph", "kb", "--type-map", ".md=html"},
+		{"graph", "ingest", dir, "--graph", "kb", "--include", "*.txt"},
+	}
+	for _, args := range tests {
+		if _, err := runCLI(t, server, args...); ExitCode(err) != ExitInvalidArgs {
+			t.Errorf("zepctl %s: exit code %d, want %d (err: %v)", strings.Join(args, " "), ExitCode(err), ExitInvalidArgs, err)
+		}
+	}
+}
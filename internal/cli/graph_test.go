@@ -338,6 +338,40 @@ func TestParseComparisonOperator(t *testing.T) {
 	}
 }
 
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"2024-01-15T10:30:00Z", "2024-01-15T10:30:00Z", false},
+		{"2024-01-15T10:30:00.123456+02:00", "2024-01-15T10:30:00.123456+02:00", false},
+		{"2024-01-15", "2024-01-15T00:00:00Z", false},
+		{"2024-01-15 10:30", "", true},
+		{"2024-13-01", "", true},
+		{"yesterday", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseTimestamp("--created-at", tt.input)
+			if tt.wantErr {
+				if ExitCode(err) != ExitInvalidArgs {
+					t.Errorf("expected invalid arguments error for %q, got %v", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error for %q: %v", tt.input, err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("got %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
 func strPtr(s string) *string {
 	return &s
 }
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zep-go/v3/graph"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/output"
+)
+
+// waitForEpisodes polls each episode until Zep has processed it, and returns
+// the episodes as last fetched, in the same order.
+func waitForEpisodes(c *client.Client, episodes []*zep.Episode, timeout, pollInterval time.Duration) ([]*zep.Episode, error) {
+	latest := make([]*zep.Episode, len(episodes))
+	pending := make(map[int]bool)
+	for i, ep := range episodes {
+		latest[i] = ep
+		if !episodeProcessed(ep) {
+			pending[i] = true
+		}
+	}
+	if len(pending) == 0 {
+		return latest, nil
+	}
+
+	progress := output.NewProgress("Waiting for episodes").WithTotal(len(pending))
+	defer progress.Done()
+	err := poll(timeout, pollInterval, fmt.Sprintf("%d episodes to be processed", len(pending)), func(ctx context.Context) (bool, error) {
+		for i := range pending {
+			ep, err := c.Graph.Episode.Get(ctx, episodes[i].UUID)
+			if err != nil {
+				return false, fmt.Errorf("getting episode %s: %w", episodes[i].UUID, err)
+			}
+			latest[i] = ep
+			if episodeProcessed(ep) {
+				delete(pending, i)
+				progress.Add("processed", 1)
+			}
+		}
+		return len(pending) == 0, nil
+	})
+	return latest, err
+}
+
+func episodeProcessed(ep *zep.Episode) bool {
+	return ep.Processed != nil && *ep.Processed
+}
+
+// waitForClone polls until a clone's task has completed and its target graph
+// exists. Completion is decided by the task; the target is only checked so
+// the command does not return before the graph can be used.
+func waitForClone(c *client.Client, resp *zep.CloneGraphResponse, timeout, pollInterval time.Duration) error {
+	target := graphRef{userID: stringValue(resp.UserID), graphID: stringValue(resp.GraphID)}
+	taskID := stringValue(resp.TaskID)
+
+	return poll(timeout, pollInterval, "clone to "+target.String(), func(ctx context.Context) (bool, error) {
+		if taskID != "" {
+			done, err := taskDone(ctx, c, taskID)
+			if !done || err != nil {
+				return false, err
+			}
+			taskID = ""
+		}
+		// Listing the episodes of a graph that does not exist yet fails with
+		// not found.
+		err := target.check(ctx, c)
+		if isNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("getting %s: %w", target, err)
+		}
+		return true, nil
+	})
+}
+
+// graphRef identifies a user graph or a standalone graph.
+type graphRef struct {
+	userID  string
+	graphID string
+}
+
+func (g graphRef) String() string {
+	if g.userID != "" {
+		return "user " + g.userID
+	}
+	return "graph " + g.graphID
+}
+
+// check lists at most one episode of the graph, which fails with not found
+// if the graph does not exist.
+func (g graphRef) check(ctx context.Context, c *client.Client) error {
+	var err error
+	if g.userID != "" {
+		_, err = c.Graph.Episode.GetByUserID(ctx, g.userID, &graph.EpisodeGetByUserIDRequest{Lastn: zep.Int(1)})
+	} else {
+		_, err = c.Graph.Episode.GetByGraphID(ctx, g.graphID, &graph.EpisodeGetByGraphIDRequest{Lastn: zep.Int(1)})
+	}
+	return err
+}
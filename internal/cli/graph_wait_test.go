@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPoll(t *testing.T) {
+	calls := 0
+	err := poll(time.Second, time.Millisecond, "test", func(ctx context.Context) (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("poll = %v after %d calls, want nil after 3", err, calls)
+	}
+
+	failed := errors.New("failed")
+	err = poll(time.Second, time.Millisecond, "test", func(ctx context.Context) (bool, error) {
+		return false, failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("poll = %v, want %v", err, failed)
+	}
+
+	err = poll(20*time.Millisecond, time.Millisecond, "test", func(ctx context.Context) (bool, error) {
+		return false, nil
+	})
+	if ExitCode(err) != ExitTimeout || !strings.Contains(err.Error(), "timeout waiting for test") {
+		t.Errorf("poll = %v (exit code %d), want timeout", err, ExitCode(err))
+	}
+}
+
+func TestGraphWait(t *testing.T) {
+	server := newSandbox(t)
+	if _, err := runCLI(t, server, "graph", "create", "g1"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, server, "graph", "add", "g1", "--data", "Alice works at Acme", "--wait", "--poll-interval", "10ms", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var episode struct {
+		Processed bool `json:"processed"`
+	}
+	if err := json.Unmarshal([]byte(out), &episode); err != nil || !episode.Processed {
+		t.Errorf("graph add --wait = %s (err: %v), want a processed episode", out, err)
+	}
+
+	path := writeEpisodes(t, t.TempDir(), 3)
+	out, err = runCLI(t, server, "graph", "add", "g1", "--batch", "--file", path, "--wait", "--poll-interval", "10ms", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var episodes []struct {
+		Processed bool `json:"processed"`
+	}
+	if err := json.Unmarshal([]byte(out), &episodes); err != nil || len(episodes) != 3 {
+		t.Fatalf("graph add --batch --wait = %s (err: %v), want 3 episodes", out, err)
+	}
+	for i, ep := range episodes {
+		if !ep.Processed {
+			t.Errorf("batch episode %d is not processed", i)
+		}
+	}
+
+	if _, err := runCLI(t, server, "graph", "clone", "--source-graph", "g1", "--target-graph", "g2", "--wait", "--poll-interval", "10ms"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runCLI(t, server, "episode", "list", "--graph", "g2", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(out), &episodes); err != nil || len(episodes) != 4 {
+		t.Errorf("clone has %d episodes (err: %v), want 4", len(episodes), err)
+	}
+
+	tests := [][]string{
+		{"graph", "add", "g1", "--data", "x", "--wait", "--poll-interval", "0s"},
+		{"graph", "clone", "--source-graph", "g1", "--wait", "--timeout", "-1s"},
+		{"task", "wait", "task-1", "--poll-interval", "0s"},
+	}
+	for _, args := range tests {
+		if _, err := runCLI(t, server, args...); ExitCode(err) != ExitInvalidArgs {
+			t.Errorf("zepctl %s: exit code %d, want %d (err: %v)", strings.Join(args, " "), ExitCode(err), ExitInvalidArgs, err)
+		}
+	}
+}
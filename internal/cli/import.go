@@ -0,0 +1,692 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zepctl/internal/archive"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Conflict policies for records that already exist in the target project.
+const (
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+	conflictFail      = "fail"
+)
+
+// Outcomes counted per resource in the import summary.
+const (
+	outcomeImported    = "imported"
+	outcomeOverwritten = "overwritten"
+	outcomeSkipped     = "skipped"
+)
+
+// importMessageBatch is the number of messages sent per AddMessages call.
+const importMessageBatch = 30
+
+// importOrder lists the resources in the order they are imported. The
+// ontology comes first so ingested data uses the archived types, and users
+// come before the threads, graphs and instructions that refer to them.
+var importOrder = []string{
+	archive.Ontology,
+	archive.Users,
+	archive.SummaryInstructions,
+	archive.Threads,
+	archive.Messages,
+	archive.Graphs,
+	archive.Episodes,
+}
+
+var projectImportCmd = &cobra.Command{
+	Use:   "import <archive>",
+	Short: "Import a project archive",
+	Long: `Import an archive written by 'zepctl project export' into the current project.
+
+Users, threads with their messages in their original order, standalone graphs
+with their episodes, the ontology and summary instructions are recreated. Nodes
+and edges are not imported; Zep rebuilds them from the imported messages and
+episodes.
+
+Records that already exist are handled according to --conflict:
+  skip       keep the existing record and do not import its contents (default)
+  overwrite  update existing users and instructions, and replace existing
+             threads, graphs and the ontology with the archived ones
+  fail       stop the import
+
+Overwriting a user only updates its fields. Its graph is kept as it is, so
+the archived episodes of an existing user graph are not re-added with any
+policy; delete the user first to restore its graph from the archive.
+
+Progress is saved to a checkpoint file after every record. If an import fails,
+running the same command again resumes where it stopped. The checkpoint is
+removed once the import completes.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		conflict, _ := cmd.Flags().GetString("conflict")
+		checkpointPath, _ := cmd.Flags().GetString("checkpoint")
+
+		switch conflict {
+		case conflictSkip, conflictOverwrite, conflictFail:
+		default:
+			return invalidArgsf("invalid --conflict %q: must be skip, overwrite or fail", conflict)
+		}
+		if checkpointPath == "" {
+			checkpointPath = path + ".checkpoint"
+		}
+
+		r, err := archive.Open(path)
+		if err != nil {
+			return err
+		}
+		manifest := r.Manifest()
+
+		cp, err := loadCheckpoint(checkpointPath, &manifest)
+		if err != nil {
+			return err
+		}
+		if cp.resumed {
+			output.Info("Resuming import from checkpoint %s", checkpointPath)
+		}
+
+		c, err := client.New()
+		if err != nil {
+			return err
+		}
+
+		im := &importer{
+			c:            c,
+			r:            r,
+			cp:           cp,
+			conflict:     conflict,
+			progress:     output.NewProgress("Importing"),
+			instructions: make(map[string]map[string]bool),
+		}
+		if err := im.run(context.Background()); err != nil {
+			return fmt.Errorf("%w; progress was saved to %s, run the command again to resume", err, checkpointPath)
+		}
+		im.progress.Done()
+
+		if err := os.Remove(checkpointPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			output.Warn("could not remove checkpoint: %v", err)
+		}
+		output.Info("Imported %s", path)
+		for _, resource := range []string{archive.Nodes, archive.Edges} {
+			if f, ok := manifest.File(resource); ok && f.Records > 0 {
+				output.Info("Nodes and edges are not imported; they are rebuilt from the imported messages and episodes")
+				break
+			}
+		}
+
+		results := cp.results()
+		if output.IsTabular() {
+			tbl := output.NewTable("RESOURCE", "IMPORTED", "OVERWRITTEN", "SKIPPED")
+			tbl.WriteHeader()
+			for _, res := range results {
+				tbl.WriteRow(res.Resource, strconv.Itoa(res.Imported), strconv.Itoa(res.Overwritten), strconv.Itoa(res.Skipped))
+			}
+			return tbl.Flush()
+		}
+
+		return output.Print(results)
+	},
+}
+
+// importResult summarizes what happened to the records of one resource.
+type importResult struct {
+	Resource    string `json:"resource"`
+	Imported    int    `json:"imported"`
+	Overwritten int    `json:"overwritten"`
+	Skipped     int    `json:"skipped"`
+}
+
+// importCheckpoint records how far an import got. It is saved after every
+// record, together with the counts and the records kept as they were, so a
+// resumed import picks up exactly where the previous one stopped.
+type importCheckpoint struct {
+	Archive string                    `json:"archive"`
+	Done    map[string]int            `json:"done"`
+	Kept    map[string]bool           `json:"kept,omitempty"`
+	Counts  map[string]map[string]int `json:"counts"`
+	// Pending is the user, thread or graph being created when the
+	// checkpoint was saved. If the import stopped before the record was
+	// checkpointed, the resumed import finds it existing and must not treat
+	// it as a conflict.
+	Pending *pendingRecord `json:"pending,omitempty"`
+
+	path    string
+	resumed bool
+}
+
+// loadCheckpoint reads the checkpoint at path, or starts a new one if there is
+// none. A checkpoint written for a different archive is rejected.
+func loadCheckpoint(path string, m *archive.Manifest) (*importCheckpoint, error) {
+	cp := &importCheckpoint{path: path}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		cp.Archive = m.ID()
+	case err != nil:
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	default:
+		if err := json.Unmarshal(data, cp); err != nil {
+			return nil, invalidArgsf("reading checkpoint %s: %v", path, err)
+		}
+		if cp.Archive != m.ID() {
+			return nil, invalidArgsf("checkpoint %s belongs to a different archive; remove it to start over", path)
+		}
+		cp.resumed = true
+	}
+
+	if cp.Done == nil {
+		cp.Done = make(map[string]int)
+	}
+	if cp.Kept == nil {
+		cp.Kept = make(map[string]bool)
+	}
+	if cp.Counts == nil {
+		cp.Counts = make(map[string]map[string]int)
+	}
+	return cp, nil
+}
+
+// keep records that an existing user, thread or graph was kept, so the
+// records it contains are not imported into it.
+func (cp *importCheckpoint) keep(kind, id string) {
+	cp.Kept[kind+"/"+id] = true
+}
+
+func (cp *importCheckpoint) kept(kind, id string) bool {
+	return cp.Kept[kind+"/"+id]
+}
+
+// pendingRecord identifies a record an import is creating, with the
+// outcome to count for it.
+type pendingRecord struct {
+	Kind    string `json:"kind"`
+	ID      string `json:"id"`
+	Outcome string `json:"outcome"`
+}
+
+// begin saves the checkpoint with the record about to be created as pending.
+func (cp *importCheckpoint) begin(kind, id, outcome string) error {
+	cp.Pending = &pendingRecord{Kind: kind, ID: id, Outcome: outcome}
+	return cp.save()
+}
+
+// pending returns the outcome of a record that a previous run was creating
+// when it stopped, if that record is kind/id.
+func (cp *importCheckpoint) pending(kind, id string) (string, bool) {
+	if cp.Pending == nil || cp.Pending.Kind != kind || cp.Pending.ID != id {
+		return "", false
+	}
+	return cp.Pending.Outcome, true
+}
+
+// advance marks every record of resource up to line as done and saves the
+// checkpoint.
+func (cp *importCheckpoint) advance(resource string, line int) error {
+	cp.Done[resource] = line
+	cp.Pending = nil
+	return cp.save()
+}
+
+func (cp *importCheckpoint) save() error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding checkpoint: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cp.path), ".zepctl-checkpoint-*")
+	if err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), cp.path); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (cp *importCheckpoint) results() []importResult {
+	results := make([]importResult, 0, len(importOrder))
+	for _, resource := range importOrder {
+		counts := cp.Counts[resource]
+		results = append(results, importResult{
+			Resource:    resource,
+			Imported:    counts[outcomeImported],
+			Overwritten: counts[outcomeOverwritten],
+			Skipped:     counts[outcomeSkipped],
+		})
+	}
+	return results
+}
+
+// importer replays the records of an archive against a project.
+type importer struct {
+	c        *client.Client
+	r        *archive.Reader
+	cp       *importCheckpoint
+	conflict string
+	progress *output.Progress
+
+	// instructions caches the existing summary instruction names per user,
+	// with "" for project-wide instructions.
+	instructions map[string]map[string]bool
+}
+
+func (im *importer) run(ctx context.Context) error {
+	steps := []func(context.Context) error{
+		func(ctx context.Context) error { return importEach(ctx, im, archive.Ontology, im.importOntology) },
+		func(ctx context.Context) error { return importEach(ctx, im, archive.Users, im.importUser) },
+		func(ctx context.Context) error {
+			return importEach(ctx, im, archive.SummaryInstructions, im.importInstruction)
+		},
+		func(ctx context.Context) error { return importEach(ctx, im, archive.Threads, im.importThread) },
+		im.importMessages,
+		func(ctx context.Context) error { return importEach(ctx, im, archive.Graphs, im.importGraph) },
+		func(ctx context.Context) error { return importEach(ctx, im, archive.Episodes, im.importEpisode) },
+	}
+	// Whatever was imported before a failure may have changed cached
+	// resources.
+	defer func() {
+		invalidateCache(cacheOntology)
+		invalidateCache(cacheUsers)
+		invalidateCache(cacheUserIDs)
+		invalidateCache(cacheThreadIDs)
+		invalidateCache(cacheGraphIDs)
+	}()
+
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importEach calls fn for every record of resource that has not been imported
+// yet and checkpoints after each one. fn returns the outcome to count, or ""
+// for records that are intentionally left out.
+func importEach[T any](ctx context.Context, im *importer, resource string, fn func(context.Context, archive.Record[T]) (string, error)) error {
+	return im.r.Each(resource, func(line int, raw json.RawMessage) error {
+		if line <= im.cp.Done[resource] {
+			return nil
+		}
+		rec, err := decodeRecord[T](resource, line, raw)
+		if err != nil {
+			return err
+		}
+		outcome, err := fn(ctx, rec)
+		if err != nil {
+			return err
+		}
+		im.count(resource, outcome, 1)
+		return im.cp.advance(resource, line)
+	})
+}
+
+func decodeRecord[T any](resource string, line int, raw json.RawMessage) (archive.Record[T], error) {
+	var rec archive.Record[T]
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return rec, fmt.Errorf("%w: %s line %d: %v", archive.ErrInvalidArchive, resource, line, err)
+	}
+	return rec, nil
+}
+
+func (im *importer) count(resource, outcome string, n int) {
+	if outcome == "" || n == 0 {
+		return
+	}
+	if im.cp.Counts[resource] == nil {
+		im.cp.Counts[resource] = make(map[string]int)
+	}
+	im.cp.Counts[resource][outcome] += n
+	im.progress.Add(resource, n)
+}
+
+// resolve applies the conflict policy to a record that already exists and
+// reports whether it should be overwritten.
+func (im *importer) resolve(what string) (bool, error) {
+	switch im.conflict {
+	case conflictOverwrite:
+		return true, nil
+	case conflictFail:
+		return false, fmt.Errorf("%s already exists (use --conflict skip or --conflict overwrite)", what)
+	default:
+		return false, nil
+	}
+}
+
+func (im *importer) importOntology(ctx context.Context, rec archive.Record[*zep.EntityTypeResponse]) (string, error) {
+	ontology := rec.Data
+	if ontology == nil || len(ontology.EntityTypes)+len(ontology.EdgeTypes) == 0 {
+		return "", nil
+	}
+
+	current, err := im.c.Graph.ListEntityTypes(ctx, &zep.GraphListEntityTypesRequest{})
+	if err != nil {
+		return "", fmt.Errorf("getting ontology: %w", err)
+	}
+	outcome := outcomeImported
+	if len(current.EntityTypes)+len(current.EdgeTypes) > 0 {
+		overwrite, err := im.resolve("an ontology")
+		if err != nil || !overwrite {
+			return outcomeSkipped, err
+		}
+		outcome = outcomeOverwritten
+	}
+
+	_, err = im.c.Graph.SetEntityTypesInternal(ctx, &zep.EntityTypeRequest{
+		EntityTypes: ontology.EntityTypes,
+		EdgeTypes:   ontology.EdgeTypes,
+	})
+	if err != nil {
+		return "", fmt.Errorf("setting ontology: %w", err)
+	}
+	return outcome, nil
+}
+
+func (im *importer) importUser(ctx context.Context, rec archive.Record[*zep.User]) (string, error) {
+	u := rec.Data
+	userID := stringValue(u.UserID)
+
+	_, err := im.c.User.Get(ctx, userID)
+	if outcome, ok := im.cp.pending(archive.Users, userID); ok && err == nil {
+		// The previous run created the user but stopped before checkpointing
+		// it.
+		return outcome, nil
+	}
+	switch {
+	case err == nil:
+		overwrite, err := im.resolve(fmt.Sprintf("user %q", userID))
+		if err != nil {
+			return "", err
+		}
+		// Even when overwritten, the existing user keeps its graph, so its
+		// episodes are not re-added.
+		im.cp.keep(archive.Users, userID)
+		if !overwrite {
+			return outcomeSkipped, nil
+		}
+		_, err = im.c.User.Update(ctx, userID, &zep.UpdateUserRequest{
+			Email:     u.Email,
+			FirstName: u.FirstName,
+			LastName:  u.LastName,
+			Metadata:  u.Metadata,
+		})
+		if err != nil {
+			return "", fmt.Errorf("updating user %q: %w", userID, err)
+		}
+		return outcomeOverwritten, nil
+	case !isNotFound(err):
+		return "", fmt.Errorf("getting user %q: %w", userID, err)
+	}
+
+	if err := im.cp.begin(archive.Users, userID, outcomeImported); err != nil {
+		return "", err
+	}
+	_, err = im.c.User.Add(ctx, &zep.CreateUserRequest{
+		UserID:    userID,
+		Email:     u.Email,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Metadata:  u.Metadata,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating user %q: %w", userID, err)
+	}
+	return outcomeImported, nil
+}
+
+func (im *importer) importInstruction(ctx context.Context, rec archive.Record[*zep.UserInstruction]) (string, error) {
+	inst := rec.Data
+	names, ok := im.instructions[rec.UserID]
+	if !ok {
+		req := &zep.UserListUserSummaryInstructionsRequest{}
+		if rec.UserID != "" {
+			req.UserID = zep.String(rec.UserID)
+		}
+		existing, err := im.c.User.ListUserSummaryInstructions(ctx, req)
+		if err != nil {
+			return "", fmt.Errorf("listing summary instructions: %w", err)
+		}
+		names = make(map[string]bool)
+		for _, e := range existing.Instructions {
+			names[e.Name] = true
+		}
+		im.instructions[rec.UserID] = names
+	}
+
+	outcome := outcomeImported
+	if names[inst.Name] {
+		overwrite, err := im.resolve(fmt.Sprintf("summary instruction %q", inst.Name))
+		if err != nil || !overwrite {
+			return outcomeSkipped, err
+		}
+		outcome = outcomeOverwritten
+	}
+
+	req := &zep.AddUserInstructionsRequest{
+		Instructions: []*zep.UserInstruction{{Name: inst.Name, Text: inst.Text}},
+	}
+	if rec.UserID != "" {
+		req.UserIDs = []string{rec.UserID}
+	}
+	if _, err := im.c.User.AddUserSummaryInstructions(ctx, req); err != nil {
+		return "", fmt.Errorf("adding summary instruction %q: %w", inst.Name, err)
+	}
+	names[inst.Name] = true
+	return outcome, nil
+}
+
+func (im *importer) importThread(ctx context.Context, rec archive.Record[*zep.Thread]) (string, error) {
+	threadID := stringValue(rec.Data.ThreadID)
+	userID := rec.UserID
+	if userID == "" {
+		userID = stringValue(rec.Data.UserID)
+	}
+
+	outcome := outcomeImported
+	_, err := im.c.Thread.Get(ctx, threadID, &zep.ThreadGetRequest{Limit: zep.Int(1)})
+	if pending, ok := im.cp.pending(archive.Threads, threadID); ok && err == nil {
+		// The previous run created the thread but stopped before
+		// checkpointing it, so its messages are still to be imported.
+		return pending, nil
+	}
+	switch {
+	case err == nil:
+		overwrite, err := im.resolve(fmt.Sprintf("thread %q", threadID))
+		if err != nil {
+			return "", err
+		}
+		if !overwrite {
+			im.cp.keep(archive.Threads, threadID)
+			return outcomeSkipped, nil
+		}
+		if err := im.cp.begin(archive.Threads, threadID, outcomeOverwritten); err != nil {
+			return "", err
+		}
+		if _, err := im.c.Thread.Delete(ctx, threadID); err != nil {
+			return "", fmt.Errorf("deleting thread %q: %w", threadID, err)
+		}
+		outcome = outcomeOverwritten
+	case !isNotFound(err):
+		return "", fmt.Errorf("getting thread %q: %w", threadID, err)
+	}
+
+	if err := im.cp.begin(archive.Threads, threadID, outcome); err != nil {
+		return "", err
+	}
+	_, err = im.c.Thread.Create(ctx, &zep.CreateThreadRequest{ThreadID: threadID, UserID: userID})
+	if err != nil {
+		return "", fmt.Errorf("creating thread %q: %w", threadID, err)
+	}
+	return outcome, nil
+}
+
+// importMessages adds messages to their threads in archive order, batching
+// consecutive messages of the same thread. Messages of threads that were kept
+// are skipped.
+func (im *importer) importMessages(ctx context.Context) error {
+	done := im.cp.Done[archive.Messages]
+	var threadID string
+	var batch []*zep.Message
+	last := done
+
+	flush := func() error {
+		if len(batch) > 0 {
+			_, err := im.c.Thread.AddMessages(ctx, threadID, &zep.AddThreadMessagesRequest{Messages: batch})
+			if err != nil {
+				return fmt.Errorf("adding messages to thread %q: %w", threadID, err)
+			}
+			im.count(archive.Messages, outcomeImported, len(batch))
+			batch = nil
+		}
+		if last > im.cp.Done[archive.Messages] {
+			return im.cp.advance(archive.Messages, last)
+		}
+		return nil
+	}
+
+	err := im.r.Each(archive.Messages, func(line int, raw json.RawMessage) error {
+		if line <= done {
+			return nil
+		}
+		rec, err := decodeRecord[*zep.Message](archive.Messages, line, raw)
+		if err != nil {
+			return err
+		}
+		if rec.ThreadID != threadID || len(batch) == importMessageBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+			threadID = rec.ThreadID
+		}
+		last = line
+
+		if im.cp.kept(archive.Threads, threadID) {
+			im.count(archive.Messages, outcomeSkipped, 1)
+			return nil
+		}
+		m := rec.Data
+		batch = append(batch, &zep.Message{
+			Content:   m.Content,
+			CreatedAt: m.CreatedAt,
+			Metadata:  m.Metadata,
+			Name:      m.Name,
+			Role:      m.Role,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+func (im *importer) importGraph(ctx context.Context, rec archive.Record[*zep.Graph]) (string, error) {
+	g := rec.Data
+	graphID := stringValue(g.GraphID)
+
+	outcome := outcomeImported
+	_, err := im.c.Graph.Get(ctx, graphID)
+	if pending, ok := im.cp.pending(archive.Graphs, graphID); ok && err == nil {
+		// The previous run created the graph but stopped before
+		// checkpointing it, so its episodes are still to be imported.
+		return pending, nil
+	}
+	switch {
+	case err == nil:
+		overwrite, err := im.resolve(fmt.Sprintf("graph %q", graphID))
+		if err != nil {
+			return "", err
+		}
+		if !overwrite {
+			im.cp.keep(archive.Graphs, graphID)
+			return outcomeSkipped, nil
+		}
+		if err := im.cp.begin(archive.Graphs, graphID, outcomeOverwritten); err != nil {
+			return "", err
+		}
+		if _, err := im.c.Graph.Delete(ctx, graphID); err != nil {
+			return "", fmt.Errorf("deleting graph %q: %w", graphID, err)
+		}
+		outcome = outcomeOverwritten
+	case !isNotFound(err):
+		return "", fmt.Errorf("getting graph %q: %w", graphID, err)
+	}
+
+	if err := im.cp.begin(archive.Graphs, graphID, outcome); err != nil {
+		return "", err
+	}
+	_, err = im.c.Graph.Create(ctx, &zep.CreateGraphRequest{
+		GraphID:     graphID,
+		Name:        g.Name,
+		Description: g.Description,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating graph %q: %w", graphID, err)
+	}
+	return outcome, nil
+}
+
+// importEpisode re-adds an episode with Graph.Add, unless its graph or user
+// was kept. Message episodes of user graphs are left out, since importing the
+// thread messages recreates them.
+func (im *importer) importEpisode(ctx context.Context, rec archive.Record[*zep.Episode]) (string, error) {
+	ep := rec.Data
+	req := &zep.AddDataRequest{
+		Data:              ep.Content,
+		Type:              zep.GraphDataTypeText,
+		SourceDescription: ep.SourceDescription,
+	}
+	if ep.Source != nil {
+		req.Type = *ep.Source
+	}
+	if ep.CreatedAt != "" {
+		req.CreatedAt = zep.String(ep.CreatedAt)
+	}
+
+	switch {
+	case rec.GraphID != "":
+		if im.cp.kept(archive.Graphs, rec.GraphID) {
+			return outcomeSkipped, nil
+		}
+		req.GraphID = zep.String(rec.GraphID)
+	case req.Type == zep.GraphDataTypeMessage:
+		return "", nil
+	case im.cp.kept(archive.Users, rec.UserID):
+		return outcomeSkipped, nil
+	default:
+		req.UserID = zep.String(rec.UserID)
+	}
+
+	if _, err := im.c.Graph.Add(ctx, req); err != nil {
+		return "", fmt.Errorf("adding episode to %s: %w", graphLabel(rec.UserID, rec.GraphID), err)
+	}
+	return outcomeImported, nil
+}
+
+func init() {
+	projectCmd.AddCommand(projectImportCmd)
+
+	projectImportCmd.Flags().String("conflict", conflictSkip, "How to handle records that already exist: skip, overwrite or fail (overwrite updates user fields but keeps their graphs)")
+	projectImportCmd.Flags().String("checkpoint", "", "Path of the checkpoint file (default <archive>.checkpoint)")
+}
@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// exportSandbox fills a sandbox with a small project and exports it.
+func exportSandbox(t *testing.T) string {
+	t.Helper()
+	server := newSandbox(t)
+	dir := t.TempDir()
+
+	messages := filepath.Join(dir, "messages.json")
+	var b strings.Builder
+	b.WriteString(`{"messages": [`)
+	for i := range 40 {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"role": "user", "content": "m%02d"}`, i)
+	}
+	b.WriteString("]}")
+	if err := os.WriteFile(messages, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	setup := [][]string{
+		{"user", "create", "alice", "--email", "alice@example.com"},
+		{"thread", "create", "t1", "--user", "alice"},
+		{"thread", "add-messages", "t1", "--file", messages},
+		{"graph", "add", "--user", "alice", "--data", "Alice likes tea"},
+		{"graph", "create", "g1"},
+		{"graph", "add", "g1", "--data", "Acme makes anvils"},
+		{"summary-instructions", "add", "--name", "tone", "--instruction", "Be brief"},
+	}
+	for _, args := range setup {
+		if _, err := runCLI(t, server, args...); err != nil {
+			t.Fatalf("zepctl %v: %v", args, err)
+		}
+	}
+
+	path := filepath.Join(dir, "backup.tar.gz")
+	if _, err := runCLI(t, server, "project", "export", "--out", path, "-q"); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	return path
+}
+
+// importCounts runs project import and returns the outcome counts per
+// resource, e.g. counts["users"]["skipped"].
+func importCounts(t *testing.T, out string) map[string]map[string]int {
+	t.Helper()
+	var results []importResult
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("parsing import output %q: %v", out, err)
+	}
+	counts := make(map[string]map[string]int)
+	for _, r := range results {
+		counts[r.Resource] = map[string]int{
+			outcomeImported:    r.Imported,
+			outcomeOverwritten: r.Overwritten,
+			outcomeSkipped:     r.Skipped,
+		}
+	}
+	return counts
+}
+
+func TestProjectImport(t *testing.T) {
+	path := exportSandbox(t)
+	server := newSandbox(t)
+
+	out, err := runCLI(t, server, "project", "import", path, "-o", "json", "-q")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	counts := importCounts(t, out)
+	want := map[string]int{"users": 1, "threads": 1, "messages": 40, "graphs": 1, "episodes": 2, "summary_instructions": 1}
+	for resource, n := range want {
+		if got := counts[resource][outcomeImported]; got != n {
+			t.Errorf("%s: imported %d, want %d", resource, got, n)
+		}
+	}
+
+	out, err = runCLI(t, server, "thread", "get", "t1", "-o", "jsonpath={.messages[*].content}")
+	if err != nil {
+		t.Fatalf("thread get: %v", err)
+	}
+	if contents := strings.Fields(out); len(contents) != 40 || contents[0] != "m00" || contents[39] != "m39" {
+		t.Errorf("messages not imported in order: %q", out)
+	}
+
+	if _, err := os.Stat(path + ".checkpoint"); !os.IsNotExist(err) {
+		t.Errorf("checkpoint not removed after a completed import: %v", err)
+	}
+
+	// Replaying the archive skips everything that already exists.
+	out, err = runCLI(t, server, "project", "import", path, "-o", "json", "-q")
+	if err != nil {
+		t.Fatalf("second import: %v", err)
+	}
+	counts = importCounts(t, out)
+	for resource := range want {
+		if counts[resource][outcomeImported] != 0 {
+			t.Errorf("replay imported %d %s", counts[resource][outcomeImported], resource)
+		}
+	}
+	if counts["users"][outcomeSkipped] != 1 || counts["messages"][outcomeSkipped] != 40 {
+		t.Errorf("replay did not skip existing records: %v", counts)
+	}
+
+	out, err = runCLI(t, server, "project", "import", path, "--conflict", "overwrite", "-o", "json", "-q")
+	if err != nil {
+		t.Fatalf("overwrite import: %v", err)
+	}
+	counts = importCounts(t, out)
+	if counts["threads"][outcomeOverwritten] != 1 || counts["messages"][outcomeImported] != 40 {
+		t.Errorf("overwrite did not replace the thread: %v", counts)
+	}
+	// An overwritten user keeps its graph, while the standalone graph is
+	// replaced with its episode.
+	if counts["users"][outcomeOverwritten] != 1 || counts["episodes"][outcomeSkipped] != 1 || counts["episodes"][outcomeImported] != 1 {
+		t.Errorf("overwrite: users %v, episodes %v; want the user's episode skipped and the graph's imported", counts["users"], counts["episodes"])
+	}
+
+	if _, err := runCLI(t, server, "project", "import", path, "--conflict", "merge"); ExitCode(err) != ExitInvalidArgs {
+		t.Errorf("invalid --conflict: exit code %d, want %d", ExitCode(err), ExitInvalidArgs)
+	}
+}
+
+func TestProjectImportResume(t *testing.T) {
+	path := exportSandbox(t)
+	server := newSandbox(t)
+
+	// A thread that already exists stops an import with --conflict fail after
+	// the users were imported.
+	for _, args := range [][]string{
+		{"user", "create", "bob"},
+		{"thread", "create", "t1", "--user", "bob"},
+	} {
+		if _, err := runCLI(t, server, args...); err != nil {
+			t.Fatalf("zepctl %v: %v", args, err)
+		}
+	}
+	_, err := runCLI(t, server, "project", "import", path, "--conflict", "fail", "-q")
+	if err == nil || !strings.Contains(err.Error(), `thread "t1" already exists`) {
+		t.Fatalf("import error = %v, want thread conflict", err)
+	}
+	if _, err := os.Stat(path + ".checkpoint"); err != nil {
+		t.Fatalf("no checkpoint after a failed import: %v", err)
+	}
+
+	// Resuming does not revisit the users imported by the first run.
+	out, err := runCLI(t, server, "project", "import", path, "--conflict", "skip", "-o", "json", "-q")
+	if err != nil {
+		t.Fatalf("resumed import: %v", err)
+	}
+	counts := importCounts(t, out)
+	if counts["users"][outcomeImported] != 1 || counts["users"][outcomeSkipped] != 0 {
+		t.Errorf("users: %v, want the first run's single import", counts["users"])
+	}
+	if counts["threads"][outcomeSkipped] != 1 || counts["messages"][outcomeSkipped] != 40 {
+		t.Errorf("existing thread was not skipped: threads %v, messages %v", counts["threads"], counts["messages"])
+	}
+	if _, err := os.Stat(path + ".checkpoint"); !os.IsNotExist(err) {
+		t.Errorf("checkpoint not removed after resuming: %v", err)
+	}
+}
+
+func TestProjectImportResumePending(t *testing.T) {
+	path := exportSandbox(t)
+	server := newSandbox(t)
+
+	// Stop the import at the threads, then rewind the checkpoint to just
+	// after alice was created but before she was checkpointed, as if the
+	// import had crashed in between.
+	for _, args := range [][]string{
+		{"user", "create", "bob"},
+		{"thread", "create", "t1", "--user", "bob"},
+	} {
+		if _, err := runCLI(t, server, args...); err != nil {
+			t.Fatalf("zepctl %v: %v", args, err)
+		}
+	}
+	if _, err := runCLI(t, server, "project", "import", path, "--conflict", "fail", "-q"); err == nil {
+		t.Fatal("import did not stop at the existing thread")
+	}
+	if _, err := runCLI(t, server, "thread", "delete", "t1", "--force"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path + ".checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cp importCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		t.Fatal(err)
+	}
+	delete(cp.Done, "users")
+	delete(cp.Counts, "users")
+	cp.Pending = &pendingRecord{Kind: "users", ID: "alice", Outcome: outcomeImported}
+	if data, err = json.Marshal(cp); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".checkpoint", data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The resumed import recognizes alice as its own and imports her graph.
+	out, err := runCLI(t, server, "project", "import", path, "--conflict", "fail", "-o", "json", "-q")
+	if err != nil {
+		t.Fatalf("resumed import: %v", err)
+	}
+	counts := importCounts(t, out)
+	if counts["users"][outcomeImported] != 1 || counts["episodes"][outcomeImported] != 2 {
+		t.Errorf("users %v, episodes %v; want alice and both episodes imported", counts["users"], counts["episodes"])
+	}
+}
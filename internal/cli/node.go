@@ -10,6 +10,7 @@ import (
 	"github.com/getzep/zep-go/v3"
 	"github.com/getzep/zepctl/internal/client"
 	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/pagination"
 	"github.com/spf13/cobra"
 )
 
@@ -27,7 +28,11 @@ var nodeListCmd = &cobra.Command{
 		graphID, _ := cmd.Flags().GetString("graph")
 
 		if userID == "" && graphID == "" {
-			return fmt.Errorf("either --user or --graph is required")
+			return invalidArgsf("either --user or --graph is required")
+		}
+		watch, interval, err := wantsWatch(cmd)
+		if err != nil {
+			return err
 		}
 
 		c, err := client.New()
@@ -35,11 +40,55 @@ var nodeListCmd = &cobra.Command{
 			return err
 		}
 
-		var nodes []*zep.EntityNode
-
 		limit, _ := cmd.Flags().GetInt("limit")
 		cursor, _ := cmd.Flags().GetString("cursor")
+		fetch := nodePageFetcher(c, userID, graphID)
+		pages := func(maxItems int) *pagination.Iterator[*zep.EntityNode] {
+			return pagination.NewCursorIterator(cursor, limit, fetch, func(n *zep.EntityNode) string {
+				return n.UUID
+			}).WithMaxItems(maxItems)
+		}
+
+		if watch {
+			_, maxItems := wantsAllPages(cmd)
+			return watchList(context.Background(), interval, nodeColumns, func(ctx context.Context) ([]*zep.EntityNode, error) {
+				return pages(maxItems).All(ctx)
+			}, func(n *zep.EntityNode) string { return n.UUID })
+		}
+
+		if paginate, maxItems := wantsAllPages(cmd); paginate {
+			return printPages(pages(maxItems), nodeColumns)
+		}
 
+		nodes, err := fetch(context.Background(), cursor, limit)
+		if err != nil {
+			return err
+		}
+
+		return printList(nodeColumns, nodes, nodes)
+	},
+}
+
+// nodeColumns is the column registry for graph nodes.
+var nodeColumns = output.Columns[*zep.EntityNode]{
+	{Name: "UUID", Value: func(n *zep.EntityNode) string { return n.UUID }},
+	{Name: "NAME", Value: func(n *zep.EntityNode) string { return n.Name }},
+	{Name: "LABEL", Value: func(n *zep.EntityNode) string {
+		if len(n.Labels) == 0 {
+			return ""
+		}
+		return n.Labels[0]
+	}},
+	{Name: "SUMMARY", Value: func(n *zep.EntityNode) string { return output.Truncate(n.Summary, 40) }},
+	{Name: "LABELS", Wide: true, Value: func(n *zep.EntityNode) string { return strings.Join(n.Labels, ",") }},
+	{Name: "ATTRIBUTE KEYS", Wide: true, Value: func(n *zep.EntityNode) string { return mapKeys(n.Attributes) }},
+	{Name: "CREATED AT", Wide: true, Value: func(n *zep.EntityNode) string { return n.CreatedAt }},
+}
+
+// nodePageFetcher returns a function that fetches one page of nodes from a
+// user graph or standalone graph, starting after the given UUID cursor.
+func nodePageFetcher(c *client.Client, userID, graphID string) func(ctx context.Context, cursor string, limit int) ([]*zep.EntityNode, error) {
+	return func(ctx context.Context, cursor string, limit int) ([]*zep.EntityNode, error) {
 		req := &zep.GraphNodesRequest{}
 		if limit > 0 {
 			req.Limit = zep.Int(limit)
@@ -48,39 +97,18 @@ var nodeListCmd = &cobra.Command{
 			req.UUIDCursor = zep.String(cursor)
 		}
 
+		var nodes []*zep.EntityNode
+		var err error
 		if userID != "" {
-			result, err := c.Graph.Node.GetByUserID(context.Background(), userID, req)
-			if err != nil {
-				return fmt.Errorf("listing nodes: %w", err)
-			}
-			nodes = result
+			nodes, err = c.Graph.Node.GetByUserID(ctx, userID, req)
 		} else {
-			result, err := c.Graph.Node.GetByGraphID(context.Background(), graphID, req)
-			if err != nil {
-				return fmt.Errorf("listing nodes: %w", err)
-			}
-			nodes = result
+			nodes, err = c.Graph.Node.GetByGraphID(ctx, graphID, req)
 		}
-
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("UUID", "NAME", "LABEL", "SUMMARY")
-			tbl.WriteHeader()
-			for _, n := range nodes {
-				label := ""
-				if len(n.Labels) > 0 {
-					label = n.Labels[0]
-				}
-				summary := n.Summary
-				if len(summary) > 40 {
-					summary = summary[:40] + "..."
-				}
-				tbl.WriteRow(n.UUID, n.Name, label, summary)
-			}
-			return tbl.Flush()
+		if err != nil {
+			return nil, fmt.Errorf("listing nodes: %w", err)
 		}
-
-		return output.Print(nodes)
-	},
+		return nodes, nil
+	}
 }
 
 var nodeGetCmd = &cobra.Command{
@@ -100,7 +128,7 @@ var nodeGetCmd = &cobra.Command{
 			return fmt.Errorf("getting node: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
+		if output.IsTabular() {
 			tbl := output.NewTable("FIELD", "VALUE")
 			tbl.WriteHeader()
 			tbl.WriteRow("UUID", node.UUID)
@@ -135,20 +163,7 @@ var nodeEdgesCmd = &cobra.Command{
 			return fmt.Errorf("getting node edges: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("UUID", "NAME", "FACT", "SOURCE", "TARGET")
-			tbl.WriteHeader()
-			for _, e := range edges {
-				fact := e.Fact
-				if len(fact) > 40 {
-					fact = fact[:40] + "..."
-				}
-				tbl.WriteRow(e.UUID, e.Name, fact, e.SourceNodeUUID, e.TargetNodeUUID)
-			}
-			return tbl.Flush()
-		}
-
-		return output.Print(edges)
+		return printList(edgeColumns(newNodeNames(c)).WithDefault("UUID", "NAME", "FACT", "SOURCE UUID", "TARGET UUID"), edges, edges)
 	},
 }
 
@@ -170,24 +185,7 @@ var nodeEpisodesCmd = &cobra.Command{
 			return fmt.Errorf("getting node episodes: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("UUID", "SOURCE", "CONTENT", "CREATED AT")
-			tbl.WriteHeader()
-			for _, ep := range episodes.Episodes {
-				source := ""
-				if ep.Source != nil {
-					source = string(*ep.Source)
-				}
-				content := ep.Content
-				if len(content) > 40 {
-					content = content[:40] + "..."
-				}
-				tbl.WriteRow(ep.UUID, source, content, ep.CreatedAt)
-			}
-			return tbl.Flush()
-		}
-
-		return output.Print(episodes)
+		return printList(episodeColumns.WithDefault("UUID", "SOURCE", "CONTENT", "CREATED AT"), episodes.Episodes, episodes)
 	},
 }
 
@@ -219,6 +217,7 @@ var nodeDeleteCmd = &cobra.Command{
 			return fmt.Errorf("deleting node: %w", err)
 		}
 
+		invalidateCache(cacheNodeNames, uuid)
 		output.Info("Deleted node %q", uuid)
 		return nil
 	},
@@ -237,6 +236,8 @@ func init() {
 	nodeListCmd.Flags().String("graph", "", "List nodes for standalone graph")
 	nodeListCmd.Flags().Int("limit", 50, "Maximum number of results to return")
 	nodeListCmd.Flags().String("cursor", "", "UUID cursor for pagination (last UUID from previous page)")
+	addPaginationFlags(nodeListCmd)
+	addWatchFlags(nodeListCmd)
 
 	// Delete flags
 	nodeDeleteCmd.Flags().Bool("force", false, "Skip confirmation prompt")
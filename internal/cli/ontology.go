@@ -29,7 +29,9 @@ var ontologyGetCmd = &cobra.Command{
 			return err
 		}
 
-		result, err := c.Graph.ListEntityTypes(context.Background(), &zep.GraphListEntityTypesRequest{})
+		result, err := cached(cacheOntology, "", ontologyCacheTTL, func() (*zep.EntityTypeResponse, error) {
+			return c.Graph.ListEntityTypes(context.Background(), &zep.GraphListEntityTypesRequest{})
+		})
 		if err != nil {
 			return fmt.Errorf("getting ontology: %w", err)
 		}
@@ -46,7 +48,7 @@ var ontologySetCmd = &cobra.Command{
 		file, _ := cmd.Flags().GetString("file")
 
 		if file == "" {
-			return fmt.Errorf("--file is required")
+			return invalidArgsf("--file is required")
 		}
 
 		data, err := os.ReadFile(file)
@@ -58,11 +60,11 @@ var ontologySetCmd = &cobra.Command{
 		var ontologyDef OntologyDefinition
 		if strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml") {
 			if err := yaml.Unmarshal(data, &ontologyDef); err != nil {
-				return fmt.Errorf("parsing YAML: %w", err)
+				return asInvalidArgs(fmt.Errorf("parsing YAML: %w", err))
 			}
 		} else {
 			if err := json.Unmarshal(data, &ontologyDef); err != nil {
-				return fmt.Errorf("parsing JSON: %w", err)
+				return asInvalidArgs(fmt.Errorf("parsing JSON: %w", err))
 			}
 		}
 
@@ -71,61 +73,15 @@ var ontologySetCmd = &cobra.Command{
 			return err
 		}
 
-		// Build entity types
-		var entityTypes []*zep.EntityType
-		for name, entity := range ontologyDef.Entities {
-			entityDef := &zep.EntityType{
-				Name:        name,
-				Description: entity.Description,
-			}
-			if len(entity.Fields) > 0 {
-				var properties []*zep.EntityProperty
-				for fieldName, fieldDef := range entity.Fields {
-					properties = append(properties, &zep.EntityProperty{
-						Name:        fieldName,
-						Description: fieldDef.Description,
-						Type:        zep.EntityPropertyTypeText, // Default to text type
-					})
-				}
-				entityDef.Properties = properties
-			}
-			entityTypes = append(entityTypes, entityDef)
-		}
-
-		// Build edge types
-		var edgeTypes []*zep.EdgeType
-		for name, edge := range ontologyDef.Edges {
-			edgeDef := &zep.EdgeType{
-				Name:        name,
-				Description: edge.Description,
-			}
-			// Build source/target constraints
-			if len(edge.SourceTypes) > 0 && len(edge.TargetTypes) > 0 {
-				var sourceTargets []*zep.EntityEdgeSourceTarget
-				for _, source := range edge.SourceTypes {
-					for _, target := range edge.TargetTypes {
-						sourceTargets = append(sourceTargets, &zep.EntityEdgeSourceTarget{
-							Source: zep.String(source),
-							Target: zep.String(target),
-						})
-					}
-				}
-				edgeDef.SourceTargets = sourceTargets
-			}
-			edgeTypes = append(edgeTypes, edgeDef)
-		}
-
-		req := &zep.EntityTypeRequest{
-			EntityTypes: entityTypes,
-			EdgeTypes:   edgeTypes,
-		}
+		req := ontologyDef.request()
 
 		result, err := c.Graph.SetEntityTypesInternal(context.Background(), req)
 		if err != nil {
 			return fmt.Errorf("setting ontology: %w", err)
 		}
+		invalidateCache(cacheOntology)
 
-		if output.GetFormat() == output.FormatTable {
+		if output.IsTabular() {
 			output.Info("Ontology set successfully")
 			return nil
 		}
@@ -140,6 +96,59 @@ type OntologyDefinition struct {
 	Edges    map[string]EdgeDefinition   `json:"edges" yaml:"edges"`
 }
 
+// request builds the entity and edge types of the definition. Fields are
+// created as text properties.
+func (d *OntologyDefinition) request() *zep.EntityTypeRequest {
+	// Build entity types
+	var entityTypes []*zep.EntityType
+	for name, entity := range d.Entities {
+		entityDef := &zep.EntityType{
+			Name:        name,
+			Description: entity.Description,
+		}
+		if len(entity.Fields) > 0 {
+			var properties []*zep.EntityProperty
+			for fieldName, fieldDef := range entity.Fields {
+				properties = append(properties, &zep.EntityProperty{
+					Name:        fieldName,
+					Description: fieldDef.Description,
+					Type:        zep.EntityPropertyTypeText, // Default to text type
+				})
+			}
+			entityDef.Properties = properties
+		}
+		entityTypes = append(entityTypes, entityDef)
+	}
+
+	// Build edge types
+	var edgeTypes []*zep.EdgeType
+	for name, edge := range d.Edges {
+		edgeDef := &zep.EdgeType{
+			Name:        name,
+			Description: edge.Description,
+		}
+		// Build source/target constraints
+		if len(edge.SourceTypes) > 0 && len(edge.TargetTypes) > 0 {
+			var sourceTargets []*zep.EntityEdgeSourceTarget
+			for _, source := range edge.SourceTypes {
+				for _, target := range edge.TargetTypes {
+					sourceTargets = append(sourceTargets, &zep.EntityEdgeSourceTarget{
+						Source: zep.String(source),
+						Target: zep.String(target),
+					})
+				}
+			}
+			edgeDef.SourceTargets = sourceTargets
+		}
+		edgeTypes = append(edgeTypes, edgeDef)
+	}
+
+	return &zep.EntityTypeRequest{
+		EntityTypes: entityTypes,
+		EdgeTypes:   edgeTypes,
+	}
+}
+
 // EntityDefinition represents an entity type in the ontology file.
 type EntityDefinition struct {
 	Description string                     `json:"description" yaml:"description"`
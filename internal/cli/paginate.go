@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/pagination"
+	"github.com/spf13/cobra"
+)
+
+// addPaginationFlags registers the --all and --max-items flags on a list command.
+func addPaginationFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all", false, "Fetch all pages of results")
+	cmd.Flags().Int("max-items", 0, "Maximum number of items to fetch across pages (implies pagination)")
+}
+
+// wantsAllPages reports whether a list command should paginate automatically,
+// and the maximum number of items to fetch (0 for no limit).
+func wantsAllPages(cmd *cobra.Command) (bool, int) {
+	all, _ := cmd.Flags().GetBool("all")
+	maxItems, _ := cmd.Flags().GetInt("max-items")
+	return all || maxItems > 0, maxItems
+}
+
+// printPages streams every page from it in the configured output format.
+// Table output writes the header once and flushes after each page; JSON and
+// YAML output is a single list written incrementally. Sorting with --sort-by
+// needs every item, so all pages are fetched before anything is written.
+func printPages[T any](it *pagination.Iterator[T], cols output.Columns[T]) error {
+	ctx := context.Background()
+
+	if output.IsSorted() {
+		items, err := it.All(ctx)
+		if err != nil {
+			return err
+		}
+		return printList(cols, items, items)
+	}
+
+	if output.IsTabular() {
+		tbl, err := cols.NewColumnTable()
+		if err != nil {
+			return err
+		}
+		return it.ForEachPage(ctx, func(items []T) error {
+			tbl.Write(items...)
+			return tbl.Flush()
+		})
+	}
+
+	lw := output.NewListWriter(os.Stdout)
+	err := it.ForEachPage(ctx, func(items []T) error {
+		for _, item := range items {
+			if err := lw.Write(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return lw.Close()
+}
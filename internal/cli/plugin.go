@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/getzep/zepctl/internal/config"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/plugin"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage zepctl plugins",
+	Long: `Plugins are executables named zepctl-<name> in ~/.zepctl/plugins or on PATH.
+"zepctl <name> [args...]" runs the plugin with the remaining arguments, for any
+name that is not a built-in command. Dashes in the file name are nested
+subcommands: zepctl-foo-bar runs as "zepctl foo bar".
+
+Plugins receive the resolved connection settings in the environment:
+
+  ZEP_API_KEY, ZEP_API_URL   API key and URL (URL empty for the SDK default)
+  ZEP_PROFILE                Profile in use, if any
+  ZEP_OUTPUT                 Output format, e.g. table or json
+  ZEP_SANDBOX                "true" when running against the sandbox
+  ZEP_SANDBOX_STATE          Sandbox state file, if any
+
+These are the variables zepctl itself reads, so a plugin that runs zepctl
+uses the same settings.`,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed plugins",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plugins := plugin.List()
+		for _, p := range plugins {
+			for _, path := range p.Shadowed {
+				output.Warn("%s is shadowed by %s", path, p.Path)
+			}
+			if builtin, _, err := rootCmd.Find(strings.Split(p.Name, "-")); err == nil && builtin != rootCmd {
+				output.Warn("%s is ignored because it has the same name as a built-in command", p.Path)
+			}
+		}
+		if len(plugins) == 0 {
+			output.Info("No plugins found in %s", strings.Join(plugin.Dirs(), string(os.PathListSeparator)))
+		}
+
+		return printList(pluginColumns, plugins, plugins)
+	},
+}
+
+// pluginColumns is the column registry for plugins.
+var pluginColumns = output.Columns[plugin.Plugin]{
+	{Name: "NAME", Value: func(p plugin.Plugin) string { return p.Name }},
+	{Name: "PATH", Value: func(p plugin.Plugin) string { return p.Path }},
+	{Name: "SHADOWED", Wide: true, Value: func(p plugin.Plugin) string { return strings.Join(p.Shadowed, ",") }},
+}
+
+// pluginExitError reports a plugin that exited with a non-zero status. The
+// plugin reports its own failure, so zepctl only passes on the exit code.
+type pluginExitError struct {
+	name string
+	code int
+}
+
+func (e *pluginExitError) Error() string {
+	return fmt.Sprintf("plugin %q exited with status %d", e.name, e.code)
+}
+
+// runPlugin is the root command's RunE. Cobra has already matched the
+// built-in commands, so any arguments left name a plugin. Flag parsing is
+// disabled on the root command so that flags after the plugin name are
+// passed to the plugin untouched; global flags before it are parsed here.
+func runPlugin(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	flags.AddFlagSet(cmd.PersistentFlags())
+	flags.SetInterspersed(false)
+	defer flags.SetInterspersed(true)
+	if err := flags.Parse(args); err != nil {
+		return asInvalidArgs(err)
+	}
+	args = flags.Args()
+
+	if help, _ := flags.GetBool("help"); help || len(args) == 0 {
+		return cmd.Help()
+	}
+
+	p, pluginArgs, ok := plugin.Lookup(args)
+	if !ok {
+		// Match the error cobra gives for unknown commands.
+		msg := fmt.Sprintf("unknown command %q for %q", args[0], cmd.CommandPath())
+		if cmd.SuggestionsMinimumDistance <= 0 {
+			cmd.SuggestionsMinimumDistance = 2
+		}
+		if suggestions := cmd.SuggestionsFor(args[0]); len(suggestions) > 0 {
+			msg += "\n\nDid you mean this?\n\t" + strings.Join(suggestions, "\n\t")
+		}
+		return errors.New(msg)
+	}
+
+	// The config file and output format were read before the global flags
+	// were parsed.
+	initConfig()
+	if err := output.ValidateFormat(); err != nil {
+		return err
+	}
+
+	c := exec.Command(p.Path, pluginArgs...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = append(os.Environ(), pluginEnv()...)
+
+	// Ctrl-C goes to the plugin too; let it decide when to exit.
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code < 0 {
+			// Killed by a signal.
+			code = ExitGeneral
+		}
+		return &pluginExitError{name: p.Name, code: code}
+	}
+	if err != nil {
+		return fmt.Errorf("running plugin %q: %w", p.Name, err)
+	}
+	return nil
+}
+
+// pluginEnv returns the resolved settings passed to plugins.
+func pluginEnv() []string {
+	env := []string{
+		"ZEP_API_KEY=" + config.GetAPIKey(),
+		"ZEP_API_URL=" + config.GetAPIURL(),
+		"ZEP_PROFILE=" + config.GetProfileName(),
+		"ZEP_OUTPUT=" + viper.GetString("output"),
+	}
+	if config.IsSandbox() {
+		env = append(env, "ZEP_SANDBOX=true", "ZEP_SANDBOX_STATE="+config.GetSandboxStatePath())
+	}
+	return env
+}
+
+func init() {
+	rootCmd.AddCommand(pluginCmd)
+	pluginCmd.AddCommand(pluginListCmd)
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPluginDispatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+
+	bin := t.TempDir()
+	script := `#!/bin/sh
+echo "args=$*"
+echo "url=$ZEP_API_URL key=$ZEP_API_KEY output=$ZEP_OUTPUT"
+exit ${PLUGIN_EXIT:-0}
+`
+	if err := os.WriteFile(filepath.Join(bin, "zepctl-hello"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	server := newSandbox(t)
+	out, err := runCLI(t, server, "-o", "json", "hello", "--name", "x", "-o", "yaml")
+	if err != nil {
+		t.Fatalf("running plugin: %v", err)
+	}
+	for _, want := range []string{
+		"args=--name x -o yaml",
+		"url=" + server.URL() + " key=test output=json",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plugin output missing %q:\n%s", want, out)
+		}
+	}
+
+	t.Setenv("PLUGIN_EXIT", "5")
+	if _, err := runCLI(t, server, "hello"); ExitCode(err) != 5 {
+		t.Errorf("failing plugin: exit code %d, want 5", ExitCode(err))
+	}
+
+	out, err = runCLI(t, server, "plugin", "list")
+	if err != nil || !strings.Contains(out, "hello") {
+		t.Errorf("plugin list = %q, %v", out, err)
+	}
+
+	if _, err := runCLI(t, server, "nosuchcommand"); ExitCode(err) != ExitInvalidArgs {
+		t.Errorf("unknown command: exit code %d, want %d", ExitCode(err), ExitInvalidArgs)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/getzep/zep-go/v3"
 	"github.com/getzep/zepctl/internal/client"
 	"github.com/getzep/zepctl/internal/output"
 	"github.com/spf13/cobra"
@@ -12,7 +13,7 @@ import (
 var projectCmd = &cobra.Command{
 	Use:   "project",
 	Short: "Manage project",
-	Long:  `Get project information.`,
+	Long:  `Get project information, and export or import a project archive.`,
 }
 
 var projectGetCmd = &cobra.Command{
@@ -24,7 +25,9 @@ var projectGetCmd = &cobra.Command{
 			return err
 		}
 
-		project, err := c.Project.Get(context.Background())
+		project, err := cached(cacheProject, "", projectCacheTTL, func() (*zep.ProjectInfoResponse, error) {
+			return c.Project.Get(context.Background())
+		})
 		if err != nil {
 			return fmt.Errorf("getting project: %w", err)
 		}
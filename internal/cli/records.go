@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Input formats of files of episodes or messages.
+const (
+	inputJSON  = "json"
+	inputJSONL = "jsonl"
+	inputYAML  = "yaml"
+)
+
+// inputFormat returns the --input-format flag, or guesses the format from
+// the file extension. Input without a file, i.e. stdin, defaults to JSON.
+func inputFormat(flag, file string) (string, error) {
+	switch flag {
+	case inputJSON, inputJSONL, inputYAML:
+		return flag, nil
+	case "":
+	default:
+		return "", invalidArgsf("invalid --input-format %q: must be json, jsonl, or yaml", flag)
+	}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".jsonl", ".ndjson":
+		return inputJSONL, nil
+	case ".yaml", ".yml":
+		return inputYAML, nil
+	default:
+		return inputJSON, nil
+	}
+}
+
+// openInput opens file, or stdin when file is "".
+func openInput(file string) (io.ReadCloser, error) {
+	if file == "" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	return f, nil
+}
+
+// recordError is a malformed record and the line it starts on.
+type recordError struct {
+	line int
+	err  error
+}
+
+// recordErrors lists every malformed record of an input.
+type recordErrors []recordError
+
+func (e recordErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d malformed records:", len(e))
+	for _, re := range e {
+		fmt.Fprintf(&b, "\n  line %d: %v", re.line, re.err)
+	}
+	return b.String()
+}
+
+// readRecords decodes the records of an input one at a time and calls fn
+// with each record and the line it starts on. An error from fn marks the
+// record as malformed.
+//
+// In JSON the records are an array under key, e.g. {"episodes": [...]}, or
+// a top-level array. JSONL has one record per line. YAML is a document like
+// the JSON one, a list of records, or a stream of records separated by "---".
+//
+// Malformed records do not stop reading; they are all returned together as
+// recordErrors. Input that cannot be parsed any further returns at once.
+func readRecords[T any](r io.Reader, format, key string, fn func(rec *T, line int) error) error {
+	var errs recordErrors
+	record := func(line int, decode func(*T) error) {
+		var rec T
+		err := decode(&rec)
+		if err == nil {
+			err = fn(&rec, line)
+		}
+		if err != nil {
+			errs = append(errs, recordError{line: line, err: err})
+		}
+	}
+
+	var err error
+	switch format {
+	case inputJSONL:
+		err = readJSONLRecords(r, record)
+	case inputYAML:
+		err = readYAMLRecords(r, key, record)
+	default:
+		err = readJSONRecords(r, key, record)
+	}
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func readJSONLRecords[T any](r io.Reader, record func(int, func(*T) error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if strings.TrimSpace(string(data)) == "" {
+			continue
+		}
+		record(line, func(rec *T) error { return unmarshalRecord(data, rec) })
+	}
+	return scanner.Err()
+}
+
+func readJSONRecords[T any](r io.Reader, key string, record func(int, func(*T) error)) error {
+	lr := &lineReader{r: r}
+	dec := json.NewDecoder(lr)
+	fail := func(err error) error {
+		offset := dec.InputOffset()
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			offset = syntaxErr.Offset
+		}
+		return fmt.Errorf("line %d: %w", lr.line(offset), err)
+	}
+	// elements decodes the elements of an array whose opening bracket was
+	// just read, up to and including the closing bracket.
+	elements := func() error {
+		for dec.More() {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return fail(err)
+			}
+			start := dec.InputOffset() - int64(len(raw))
+			record(lr.line(start), func(rec *T) error { return unmarshalRecord(raw, rec) })
+		}
+		if _, err := dec.Token(); err != nil {
+			return fail(err)
+		}
+		return nil
+	}
+
+	tok, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fail(err)
+	}
+	switch tok {
+	case json.Delim('['):
+		return elements()
+	case json.Delim('{'):
+	default:
+		return fail(fmt.Errorf("expected an object with a %q array", key))
+	}
+	for dec.More() {
+		name, err := dec.Token()
+		if err != nil {
+			return fail(err)
+		}
+		if name != key {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fail(err)
+			}
+			continue
+		}
+		tok, err := dec.Token()
+		if err != nil {
+			return fail(err)
+		}
+		if tok != json.Delim('[') {
+			return fail(fmt.Errorf("%s must be an array", key))
+		}
+		if err := elements(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readYAMLRecords[T any](r io.Reader, key string, record func(int, func(*T) error)) error {
+	dec := yaml.NewDecoder(r)
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		root := doc.Content[0]
+
+		var items []*yaml.Node
+		switch root.Kind {
+		case yaml.SequenceNode:
+			items = root.Content
+		case yaml.MappingNode:
+			items = []*yaml.Node{root}
+			for i := 0; i+1 < len(root.Content); i += 2 {
+				if root.Content[i].Value == key {
+					value := root.Content[i+1]
+					if value.Kind != yaml.SequenceNode {
+						return fmt.Errorf("line %d: %s must be a list", value.Line, key)
+					}
+					items = value.Content
+					break
+				}
+			}
+		default:
+			return fmt.Errorf("line %d: expected a record, a list of records or a %q list", root.Line, key)
+		}
+		for _, item := range items {
+			record(item.Line, func(rec *T) error { return decodeYAMLRecord(item, rec) })
+		}
+	}
+}
+
+// decodeYAMLRecord decodes a YAML node through JSON, so the JSON field names
+// of the record type apply.
+func decodeYAMLRecord(node *yaml.Node, v any) error {
+	var doc any
+	if err := node.Decode(&doc); err != nil {
+		return err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return unmarshalRecord(data, v)
+}
+
+func unmarshalRecord(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.New(strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return nil
+}
+
+// lineReader records where the lines of the data read through it start, to
+// turn decoder offsets into line numbers.
+type lineReader struct {
+	r        io.Reader
+	read     int64
+	newlines []int64
+}
+
+func (l *lineReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	for i, b := range p[:n] {
+		if b == '\n' {
+			l.newlines = append(l.newlines, l.read+int64(i))
+		}
+	}
+	l.read += int64(n)
+	return n, err
+}
+
+// line returns the 1-based line of the byte at offset.
+func (l *lineReader) line(offset int64) int {
+	return sort.Search(len(l.newlines), func(i int) bool { return l.newlines[i] >= offset }) + 1
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestInputFormat(t *testing.T) {
+	tests := []struct {
+		flag, file string
+		expected   string
+		wantErr    bool
+	}{
+		{"", "episodes.json", inputJSON, false},
+		{"", "episodes.JSONL", inputJSONL, false},
+		{"", "episodes.ndjson", inputJSONL, false},
+		{"", "episodes.yml", inputYAML, false},
+		{"", "", inputJSON, false},
+		{"yaml", "episodes.json", inputYAML, false},
+		{"xml", "episodes.xml", "", true},
+	}
+	for _, tt := range tests {
+		got, err := inputFormat(tt.flag, tt.file)
+		if (err != nil) != tt.wantErr || got != tt.expected {
+			t.Errorf("inputFormat(%q, %q) = %q, %v; want %q", tt.flag, tt.file, got, err, tt.expected)
+		}
+	}
+}
+
+func TestReadRecords(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		input     string
+		want      []string
+		wantLines []int
+		wantErr   string
+	}{
+		{
+			name:   "json document",
+			format: inputJSON,
+			input:  "{\n  \"episodes\": [\n    {\"data\": \"a\"},\n    {\"data\": \"b\"}\n  ],\n  \"note\": \"ignored\"\n}",
+			want:   []string{"a@3", "b@4"},
+		},
+		{
+			name:   "json array",
+			format: inputJSON,
+			input:  `[{"data": "a"}, {"data": "b"}]`,
+			want:   []string{"a@1", "b@1"},
+		},
+		{
+			name:      "json malformed records",
+			format:    inputJSON,
+			input:     "{\"episodes\": [\n{\"data\": 1},\n{\"data\": \"b\"},\n{\"data\": \"bad\"}\n]}",
+			want:      []string{"b@3"},
+			wantLines: []int{2, 4},
+		},
+		{
+			name:    "json syntax error",
+			format:  inputJSON,
+			input:   "{\"episodes\": [\n{\"data\": \"a\"},\n{\"data\" \"b\"}\n]}",
+			want:    []string{"a@2"},
+			wantErr: "line 3",
+		},
+		{
+			name:    "json wrong shape",
+			format:  inputJSON,
+			input:   `{"episodes": {"data": "a"}}`,
+			wantErr: "episodes must be an array",
+		},
+		{
+			name:      "jsonl",
+			format:    inputJSONL,
+			input:     "{\"data\": \"a\"}\n\n{\"data\": \"b\"}\nnot json\n{\"data\": \"bad\"}\n",
+			want:      []string{"a@1", "b@3"},
+			wantLines: []int{4, 5},
+		},
+		{
+			name:   "yaml document",
+			format: inputYAML,
+			input:  "episodes:\n  - data: a\n  - data: b\n",
+			want:   []string{"a@2", "b@3"},
+		},
+		{
+			name:      "yaml stream",
+			format:    inputYAML,
+			input:     "data: a\n---\ndata: [1]\n---\ndata: b\n",
+			want:      []string{"a@1", "b@5"},
+			wantLines: []int{3},
+		},
+		{
+			name:    "yaml syntax error",
+			format:  inputYAML,
+			input:   "episodes:\n  - data: a\n  - data: \"b\n",
+			wantErr: "line 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := readRecords(strings.NewReader(tt.input), tt.format, "episodes", func(e *EpisodeData, line int) error {
+				if e.Data == "bad" {
+					return errors.New("bad data")
+				}
+				got = append(got, e.Data+"@"+strconv.Itoa(line))
+				return nil
+			})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("records = %v, want %v", got, tt.want)
+			}
+
+			var recErrs recordErrors
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+			case tt.wantLines != nil:
+				if !errors.As(err, &recErrs) {
+					t.Fatalf("error = %v, want malformed records", err)
+				}
+				var lines []int
+				for _, re := range recErrs {
+					lines = append(lines, re.line)
+				}
+				if !slices.Equal(lines, tt.wantLines) {
+					t.Errorf("malformed lines = %v, want %v (%v)", lines, tt.wantLines, err)
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestAddRecordsInputFormats(t *testing.T) {
+	server := newSandbox(t)
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	steps := [][]string{
+		{"user", "create", "alice"},
+		{"thread", "create", "t1", "--user", "alice"},
+		{"graph", "create", "g1"},
+		{"thread", "add-messages", "t1", "--file", write("messages.jsonl", `{"role": "user", "content": "hi"}`+"\n"+`{"role": "assistant", "content": "hello"}`+"\n")},
+		{"graph", "add", "g1", "--batch", "--file", write("episodes.yaml", "episodes:\n  - type: text\n    data: Alice likes tea\n    created_at: 2024-01-15\n")},
+	}
+	for _, args := range steps {
+		if _, err := runCLI(t, server, args...); err != nil {
+			t.Fatalf("zepctl %s: %v", strings.Join(args, " "), err)
+		}
+	}
+
+	// Stdin has no extension, so its format is given explicitly.
+	stdin, err := os.Open(write("stdin", "- role: user\n  content: bye\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	orig := os.Stdin
+	os.Stdin = stdin
+	_, err = runCLI(t, server, "thread", "add-messages", "t1", "--stdin", "--input-format", "yaml")
+	os.Stdin = orig
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, server, "thread", "messages", "t1", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, content := range []string{"hi", "hello", "bye"} {
+		if !strings.Contains(out, `"content": "`+content+`"`) {
+			t.Errorf("thread is missing message %q:\n%s", content, out)
+		}
+	}
+	out, err = runCLI(t, server, "episode", "list", "--graph", "g1", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `"created_at": "2024-01-15T00:00:00Z"`) {
+		t.Errorf("episode was not added with its created_at:\n%s", out)
+	}
+
+	_, err = runCLI(t, server, "thread", "add-messages", "t1", "--file", write("bad.jsonl", `{"role": "user", "content": "ok"}`+"\n"+`{"role": "user", "content": 1}`+"\n"+`{oops`+"\n"))
+	if ExitCode(err) != ExitInvalidArgs || !strings.Contains(err.Error(), "line 2:") || !strings.Contains(err.Error(), "line 3:") {
+		t.Errorf("malformed JSONL: exit code %d, err %v; want exit %d naming lines 2 and 3", ExitCode(err), err, ExitInvalidArgs)
+	}
+}
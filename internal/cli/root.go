@@ -1,10 +1,14 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/config"
+	"github.com/getzep/zepctl/internal/output"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -24,12 +28,41 @@ var rootCmd = &cobra.Command{
 	Long: `zepctl is a command-line interface for administering Zep projects
 and improving the developer experience. It provides comprehensive access
 to Zep's context engineering platform.`,
-	SilenceUsage: true,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	// Unknown subcommands run plugins; see runPlugin.
+	Args:               cobra.ArbitraryArgs,
+	DisableFlagParsing: true,
+	RunE:               runPlugin,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return output.ValidateFormat()
+	},
 }
 
-// Execute runs the root command.
+// Execute runs the root command. Failures are classified and reported on
+// stderr; use ExitCode to map the returned error to a process exit code.
 func Execute() error {
-	return rootCmd.Execute()
+	wrapArgValidators(rootCmd)
+	registerFlagCompletions(rootCmd)
+
+	cmd, err := rootCmd.ExecuteC()
+	if traceErr := client.FlushTrace(version); traceErr != nil {
+		output.Warn("%v", traceErr)
+	}
+	if err == nil {
+		return nil
+	}
+
+	// A failed plugin has reported its own error.
+	var exitErr *pluginExitError
+	if errors.As(err, &exitErr) {
+		return &CommandError{Code: codeGeneral, ExitCode: exitErr.code, Err: err}
+	}
+
+	cmdErr := classifyError(err)
+	addResourceDetails(cmd, cmdErr)
+	output.PrintError(cmdErr.Code, cmdErr.Error(), cmdErr.Details)
+	return cmdErr
 }
 
 func init() {
@@ -39,14 +72,38 @@ func init() {
 	rootCmd.PersistentFlags().StringP("api-key", "k", "", "API key for authentication")
 	rootCmd.PersistentFlags().String("api-url", "", "API endpoint URL (uses SDK default if not set)")
 	rootCmd.PersistentFlags().StringP("profile", "p", "", "Use specific profile")
-	rootCmd.PersistentFlags().StringP("output", "o", "table", "Output format: table, json, yaml, wide")
+	rootCmd.PersistentFlags().StringP("output", "o", "table", "Output format: table, json, ndjson, yaml, wide, csv, tsv, jsonpath=EXPR, go-template=TMPL, go-template-file=PATH")
+	rootCmd.PersistentFlags().String("columns", "", "Comma-separated columns to show in table output (e.g. user-id,email,uuid)")
+	rootCmd.PersistentFlags().String("sort-by", "", "Sort list output by a column; prefix with - for descending order")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress non-essential output")
-	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().CountP("verbose", "v", "Enable verbose output; log HTTP requests (-v) and their headers and bodies (-vv)")
+	rootCmd.PersistentFlags().String("trace-har", "", "Record HTTP requests and responses to a HAR file, with credentials redacted")
+	rootCmd.PersistentFlags().Int("retry", config.DefaultRetryMaxAttempts, "Maximum attempts for retryable requests (1 disables retries)")
+	rootCmd.PersistentFlags().Duration("retry-base-delay", config.DefaultRetryBaseDelay, "Initial backoff delay between retries")
+	rootCmd.PersistentFlags().Float64("retry-jitter", config.DefaultRetryJitter, "Random jitter applied to backoff delays (0-1)")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Bypass the local response cache")
+	rootCmd.PersistentFlags().Bool("sandbox", false, "Run against a local in-memory Zep emulator instead of a project")
+	rootCmd.PersistentFlags().String("sandbox-state", "", "File to persist sandbox state to (in-memory only if not set)")
 
 	_ = viper.BindPFlag("api-key", rootCmd.PersistentFlags().Lookup("api-key"))
 	_ = viper.BindPFlag("api-url", rootCmd.PersistentFlags().Lookup("api-url"))
 	_ = viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
 	_ = viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	_ = viper.BindPFlag("columns", rootCmd.PersistentFlags().Lookup("columns"))
+	_ = viper.BindPFlag("sort-by", rootCmd.PersistentFlags().Lookup("sort-by"))
+	_ = viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
+	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	_ = viper.BindPFlag("trace-har", rootCmd.PersistentFlags().Lookup("trace-har"))
+	_ = viper.BindPFlag("retry", rootCmd.PersistentFlags().Lookup("retry"))
+	_ = viper.BindPFlag("retry-base-delay", rootCmd.PersistentFlags().Lookup("retry-base-delay"))
+	_ = viper.BindPFlag("retry-jitter", rootCmd.PersistentFlags().Lookup("retry-jitter"))
+	_ = viper.BindPFlag("no-cache", rootCmd.PersistentFlags().Lookup("no-cache"))
+	_ = viper.BindPFlag("sandbox", rootCmd.PersistentFlags().Lookup("sandbox"))
+	_ = viper.BindPFlag("sandbox-state", rootCmd.PersistentFlags().Lookup("sandbox-state"))
+
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return asInvalidArgs(err)
+	})
 }
 
 func initConfig() {
@@ -66,7 +123,7 @@ func initConfig() {
 	viper.AutomaticEnv()
 
 	if err := viper.ReadInConfig(); err == nil {
-		if viper.GetBool("verbose") {
+		if output.IsVerbose() {
 			fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 		}
 	}
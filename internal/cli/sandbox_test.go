@@ -0,0 +1,335 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/getzep/zepctl/internal/sandbox"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestMain(m *testing.M) {
+	// Keep tests away from the developer's config file and keychain profiles.
+	home, err := os.MkdirTemp("", "zepctl-test")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Setenv("HOME", home); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// newSandbox starts an emulator with empty state for the duration of a test.
+func newSandbox(t *testing.T) *sandbox.Server {
+	t.Helper()
+	server, err := sandbox.Start("")
+	if err != nil {
+		t.Fatalf("starting sandbox: %v", err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+	return server
+}
+
+// runCLI executes zepctl against server and returns what it wrote to stdout.
+func runCLI(t *testing.T, server *sandbox.Server, args ...string) (string, error) {
+	t.Helper()
+	resetFlags(rootCmd)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	rootCmd.SetArgs(append([]string{"--api-url", server.URL(), "--api-key", "test", "--retry", "1"}, args...))
+	err = Execute()
+
+	os.Stdout = stdout
+	_ = w.Close()
+	return <-done, err
+}
+
+// resetFlags restores every flag to its default so commands can be run
+// repeatedly within one process.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// cliStep is one command in an end-to-end scenario.
+type cliStep struct {
+	args     []string
+	want     []string // substrings expected on stdout
+	wantExit int
+}
+
+func runSteps(t *testing.T, steps []cliStep) {
+	t.Helper()
+	server := newSandbox(t)
+	for _, step := range steps {
+		out, err := runCLI(t, server, step.args...)
+		if got := ExitCode(err); got != step.wantExit {
+			t.Fatalf("zepctl %s: exit code %d, want %d (err: %v)", strings.Join(step.args, " "), got, step.wantExit, err)
+		}
+		for _, want := range step.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("zepctl %s: output missing %q:\n%s", strings.Join(step.args, " "), want, out)
+			}
+		}
+	}
+}
+
+func TestSandboxCommands(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []cliStep
+	}{
+		{
+			name: "user lifecycle",
+			steps: []cliStep{
+				{args: []string{"user", "create", "alice", "--email", "alice@example.com"}, want: []string{`"user_id": "alice"`}},
+				{args: []string{"user", "update", "alice", "--first-name", "Alice"}, want: []string{`"first_name": "Alice"`}},
+				{args: []string{"user", "list"}, want: []string{"alice@example.com"}},
+				{args: []string{"user", "get", "alice", "-o", "json"}, want: []string{`"email": "alice@example.com"`}},
+				{args: []string{"user", "node", "alice"}, want: []string{`"User"`}},
+				{args: []string{"user", "delete", "alice", "--force"}},
+				{args: []string{"user", "get", "alice"}, wantExit: ExitNotFound},
+			},
+		},
+		{
+			name: "thread messages",
+			steps: []cliStep{
+				{args: []string{"user", "create", "bob"}},
+				{args: []string{"thread", "create", "t1", "--user", "bob"}, want: []string{`"thread_id": "t1"`}},
+				{args: []string{"thread", "list"}, want: []string{"t1"}},
+				{args: []string{"user", "threads", "bob"}, want: []string{"t1"}},
+				{args: []string{"thread", "messages", "t1"}},
+				{args: []string{"episode", "list", "--user", "bob"}},
+				{args: []string{"thread", "delete", "t1", "--force"}},
+				{args: []string{"thread", "delete", "missing", "--force"}, wantExit: ExitNotFound},
+			},
+		},
+		{
+			name: "standalone graph",
+			steps: []cliStep{
+				{args: []string{"graph", "create", "g1"}, want: []string{`"graph_id": "g1"`}},
+				{args: []string{"graph", "list"}, want: []string{"g1"}},
+				{args: []string{"graph", "add", "g1", "--data", "Alice works at Acme"}, want: []string{"Alice works at Acme"}},
+				{args: []string{"graph", "add-fact", "--graph", "g1", "--fact", "Alice works at Acme", "--fact-name", "WORKS_AT", "--source-node", "Alice", "--target-node", "Acme"}},
+				{args: []string{"node", "list", "--graph", "g1"}, want: []string{"Alice", "Acme"}},
+				{args: []string{"edge", "list", "--graph", "g1"}, want: []string{"WORKS_AT"}},
+				{args: []string{"graph", "search", "works", "--graph", "g1"}, want: []string{"Alice works at Acme"}},
+				{args: []string{"episode", "list", "--graph", "g1"}, want: []string{"Alice works at Acme"}},
+				{args: []string{"graph", "clone", "--source-graph", "g1", "--target-graph", "g2"}},
+				{args: []string{"node", "list", "--graph", "g2", "-o", "json"}, want: []string{`"name": "Acme"`}},
+				{args: []string{"graph", "delete", "g1", "--force"}},
+				{args: []string{"node", "list", "--graph", "g1"}, wantExit: ExitNotFound},
+			},
+		},
+		{
+			name: "csv and tsv output",
+			steps: []cliStep{
+				{args: []string{"user", "create", "carol", "--first-name", "Carol, Jr.", "--last-name", `O"Neil`}},
+				{args: []string{"user", "list", "-o", "csv"}, want: []string{"USER ID,EMAIL,FIRST NAME,LAST NAME,CREATED AT\n", `carol,,"Carol, Jr.","O""Neil",`}},
+				{args: []string{"user", "list", "-o", "tsv"}, want: []string{"USER ID\tEMAIL\tFIRST NAME", "carol\t\tCarol, Jr.\t\"O\"\"Neil\"\t"}},
+				{args: []string{"graph", "create", "g"}},
+				{args: []string{"graph", "add-fact", "--graph", "g", "--fact", "Carol has been a member of the hiking club since 2019", "--fact-name", "MEMBER_OF", "--source-node", "Carol", "--target-node", "Hiking Club"}},
+				{args: []string{"edge", "list", "--graph", "g", "-o", "csv"}, want: []string{",MEMBER_OF,Carol has been a member of the hiking club since 2019,"}},
+				{args: []string{"edge", "list", "--graph", "g"}, want: []string{"Carol has been a member of the hiking cl..."}},
+				{args: []string{"graph", "search", "hiking", "--graph", "g", "-o", "tsv"}, want: []string{"\tCarol has been a member of the hiking club since 2019\t"}},
+			},
+		},
+		{
+			name: "template output",
+			steps: []cliStep{
+				{args: []string{"user", "create", "dave", "--email", "dave@example.com"}},
+				{args: []string{"user", "create", "erin"}},
+				{args: []string{"user", "list", "-o", "jsonpath={.users[*].user_id}"}, want: []string{"dave erin"}},
+				{args: []string{"user", "list", "--all", "-o", `jsonpath={range [*]}{.user_id}{"\n"}{end}`}, want: []string{"dave\nerin\n"}},
+				{args: []string{"user", "get", "dave", "-o", "go-template={{.user_id}}: {{deref .email}}"}, want: []string{"dave: dave@example.com"}},
+				{args: []string{"user", "list", "-o", `go-template={{range .users}}{{.user_id}}={{default "none" .email}} {{end}}`}, want: []string{"dave=dave@example.com erin=none"}},
+				{args: []string{"user", "create", "frank", "-o", "go-template={{.UserID"}, wantExit: ExitInvalidArgs},
+				{args: []string{"user", "get", "frank"}, wantExit: ExitNotFound},
+			},
+		},
+		{
+			name: "columns and sorting",
+			steps: []cliStep{
+				{args: []string{"user", "create", "alice"}},
+				{args: []string{"user", "create", "bob", "--metadata", `{"plan":"pro","seats":2}`}},
+				{args: []string{"user", "list", "-o", "wide"}, want: []string{"UUID", "METADATA KEYS", "plan,seats"}},
+				{args: []string{"user", "list", "--columns", "user-id", "--sort-by", "-user-id", "-o", "csv"}, want: []string{"USER ID\nbob\nalice\n"}},
+				{args: []string{"user", "list", "--all", "--columns", "USER_ID", "--sort-by", "user-id", "-o", "csv"}, want: []string{"USER ID\nalice\nbob\n"}},
+				{args: []string{"user", "list", "--columns", "user-id,nope"}, wantExit: ExitInvalidArgs},
+				{args: []string{"user", "list", "--sort-by", "nope"}, wantExit: ExitInvalidArgs},
+				{args: []string{"graph", "create", "g"}},
+				{args: []string{"graph", "add-fact", "--graph", "g", "--fact", "Alice works at Acme", "--fact-name", "WORKS_AT", "--source-node", "Alice", "--target-node", "Acme"}},
+				{args: []string{"edge", "list", "--graph", "g", "-o", "wide"}, want: []string{"SOURCE UUID", "Alice", "Acme"}},
+				{args: []string{"edge", "list", "--graph", "g", "--columns", "source,name,target", "-o", "csv"}, want: []string{"SOURCE,NAME,TARGET\nAlice,WORKS_AT,Acme\n"}},
+				{args: []string{"node", "list", "--graph", "g", "--columns", "name", "--sort-by", "name", "-o", "csv"}, want: []string{"NAME\nAcme\nAlice\n"}},
+			},
+		},
+		{
+			name: "ndjson output",
+			steps: []cliStep{
+				{args: []string{"graph", "create", "g"}},
+				{args: []string{"graph", "add-fact", "--graph", "g", "--fact", "Alice works at Acme", "--fact-name", "WORKS_AT", "--source-node", "Alice", "--target-node", "Acme"}},
+				{args: []string{"graph", "add-fact", "--graph", "g", "--fact", "Bob works at Acme", "--fact-name", "EMPLOYED_BY", "--source-node", "Bob", "--target-node", "Acme"}},
+				{args: []string{"edge", "list", "--graph", "g", "--all", "--limit", "1", "-o", "ndjson"}, want: []string{`"name":"WORKS_AT"`, "}\n{", `"name":"EMPLOYED_BY"`}},
+				{args: []string{"node", "list", "--graph", "g", "-o", "ndjson", "--sort-by", "name"}, want: []string{`"name":"Acme"`}},
+				{args: []string{"user", "create", "alice", "-o", "ndjson"}, want: []string{`{"created_at":`}},
+				{args: []string{"user", "get", "nobody", "-o", "ndjson"}, wantExit: ExitNotFound},
+			},
+		},
+		{
+			name: "invalid arguments",
+			steps: []cliStep{
+				{args: []string{"graph", "add", "g1", "--type", "xml", "--data", "x"}, wantExit: ExitInvalidArgs},
+				{args: []string{"user", "get"}, wantExit: ExitInvalidArgs},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runSteps(t, tt.steps)
+		})
+	}
+}
+
+func TestThreadMessagesPages(t *testing.T) {
+	server := newSandbox(t)
+	path := filepath.Join(t.TempDir(), "messages.jsonl")
+	var lines []string
+	for _, content := range []string{"one", "two", "three", "four", "five"} {
+		lines = append(lines, `{"role": "user", "content": "`+content+`"}`)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	steps := [][]string{
+		{"user", "create", "alice"},
+		{"thread", "create", "t1", "--user", "alice"},
+		{"thread", "add-messages", "t1", "--file", path},
+	}
+	for _, args := range steps {
+		if _, err := runCLI(t, server, args...); err != nil {
+			t.Fatalf("zepctl %s: %v", strings.Join(args, " "), err)
+		}
+	}
+
+	out, err := runCLI(t, server, "thread", "messages", "t1", "--all", "--limit", "2", "-o", "ndjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSpace(out), "\n")
+	if len(got) != 5 {
+		t.Fatalf("thread messages --all = %d lines, want 5:\n%s", len(got), out)
+	}
+	for i, content := range []string{"one", "two", "three", "four", "five"} {
+		if !strings.Contains(got[i], `"content":"`+content+`"`) {
+			t.Errorf("line %d = %s, want message %q", i+1, got[i], content)
+		}
+	}
+
+	out, err = runCLI(t, server, "thread", "messages", "t1", "--max-items", "3", "--limit", "2", "-o", "ndjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out, "\n"); n != 3 {
+		t.Errorf("thread messages --max-items 3 = %d lines, want 3:\n%s", n, out)
+	}
+
+	if _, err := runCLI(t, server, "thread", "messages", "t1", "--all", "--last", "2"); ExitCode(err) != ExitInvalidArgs {
+		t.Errorf("--all with --last: exit code %d, want %d (err: %v)", ExitCode(err), ExitInvalidArgs, err)
+	}
+}
+
+func TestEdgeListNodeNames(t *testing.T) {
+	server := newSandbox(t)
+	steps := [][]string{
+		{"graph", "create", "g"},
+		{"graph", "add-fact", "--graph", "g", "--fact", "Alice works at Acme", "--fact-name", "WORKS_AT", "--source-node", "Alice", "--target-node", "Acme"},
+		{"graph", "add-fact", "--graph", "g", "--fact", "Bob lives in Oslo", "--fact-name", "LIVES_IN", "--source-node", "Bob", "--target-node", "Oslo"},
+	}
+	for _, args := range steps {
+		if _, err := runCLI(t, server, args...); err != nil {
+			t.Fatalf("zepctl %s: %v", strings.Join(args, " "), err)
+		}
+	}
+
+	// nodeRequests runs edge list and returns the node requests it made.
+	nodeRequests := func(args ...string) (string, []string) {
+		t.Helper()
+		har := filepath.Join(t.TempDir(), "trace.har")
+		out, err := runCLI(t, server, append([]string{"edge", "list", "--graph", "g", "--no-cache", "--trace-har", har}, args...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(har)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var trace struct {
+			Log struct {
+				Entries []struct {
+					Request struct {
+						Method string `json:"method"`
+						URL    string `json:"url"`
+					} `json:"request"`
+				} `json:"entries"`
+			} `json:"log"`
+		}
+		if err := json.Unmarshal(data, &trace); err != nil {
+			t.Fatal(err)
+		}
+		var requests []string
+		for _, e := range trace.Log.Entries {
+			if strings.Contains(e.Request.URL, "/graph/node/") {
+				requests = append(requests, e.Request.Method+" "+e.Request.URL)
+			}
+		}
+		return out, requests
+	}
+
+	if _, requests := nodeRequests(); len(requests) != 0 {
+		t.Errorf("default columns made node requests: %q", requests)
+	}
+	out, requests := nodeRequests("-o", "wide")
+	for _, name := range []string{"Alice", "Acme", "Bob", "Oslo"} {
+		if !strings.Contains(out, name) {
+			t.Errorf("wide output has no node %s:\n%s", name, out)
+		}
+	}
+	if len(requests) != 1 || !strings.HasPrefix(requests[0], "POST ") {
+		t.Errorf("wide output made node requests %q, want one listing", requests)
+	}
+}
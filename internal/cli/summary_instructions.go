@@ -44,23 +44,17 @@ var summaryInstructionsListCmd = &cobra.Command{
 			return fmt.Errorf("listing summary instructions: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("NAME", "TEXT")
-			tbl.WriteHeader()
-			for _, inst := range result.Instructions {
-				text := inst.Text
-				if len(text) > 60 {
-					text = text[:60] + "..."
-				}
-				tbl.WriteRow(inst.Name, text)
-			}
-			return tbl.Flush()
-		}
-
-		return output.Print(result)
+		return printList(instructionColumns, result.Instructions, result)
 	},
 }
 
+// instructionColumns is the column registry for summary instructions. They
+// have no extra fields to show, so -o wide only lifts the TEXT truncation.
+var instructionColumns = output.Columns[*zep.UserInstruction]{
+	{Name: "NAME", Value: func(inst *zep.UserInstruction) string { return inst.Name }},
+	{Name: "TEXT", Value: func(inst *zep.UserInstruction) string { return output.Truncate(inst.Text, 60) }},
+}
+
 var summaryInstructionsAddCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add summary instructions",
@@ -72,11 +66,11 @@ var summaryInstructionsAddCmd = &cobra.Command{
 		userIDs, _ := cmd.Flags().GetString("user")
 
 		if name == "" {
-			return fmt.Errorf("--name is required")
+			return invalidArgsf("--name is required")
 		}
 
 		if instruction == "" && file == "" {
-			return fmt.Errorf("either --instruction or --file is required")
+			return invalidArgsf("either --instruction or --file is required")
 		}
 
 		instructionText := instruction
@@ -89,7 +83,7 @@ var summaryInstructionsAddCmd = &cobra.Command{
 		}
 
 		if len(instructionText) > maxInstructionLength {
-			return fmt.Errorf("instruction text exceeds maximum length of %d characters (got %d)", maxInstructionLength, len(instructionText))
+			return invalidArgsf("instruction text exceeds maximum length of %d characters (got %d)", maxInstructionLength, len(instructionText))
 		}
 
 		c, err := client.New()
@@ -115,7 +109,7 @@ var summaryInstructionsAddCmd = &cobra.Command{
 			return fmt.Errorf("adding summary instruction: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
+		if output.IsTabular() {
 			scope := "project-wide"
 			if userIDs != "" {
 				scope = fmt.Sprintf("user(s): %s", userIDs)
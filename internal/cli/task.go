@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/getzep/zep-go/v3"
 	"github.com/getzep/zepctl/internal/client"
 	"github.com/getzep/zepctl/internal/output"
 	"github.com/spf13/cobra"
@@ -33,37 +34,50 @@ var taskGetCmd = &cobra.Command{
 			return fmt.Errorf("getting task: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("FIELD", "VALUE")
-			tbl.WriteHeader()
-			if task.TaskID != nil {
-				tbl.WriteRow("Task ID", *task.TaskID)
-			}
-			if task.Status != nil {
-				tbl.WriteRow("Status", *task.Status)
-			}
-			if task.Type != nil {
-				tbl.WriteRow("Type", *task.Type)
-			}
-			if task.CreatedAt != nil {
-				tbl.WriteRow("Created At", *task.CreatedAt)
-			}
-			if task.StartedAt != nil {
-				tbl.WriteRow("Started At", *task.StartedAt)
-			}
-			if task.CompletedAt != nil {
-				tbl.WriteRow("Completed At", *task.CompletedAt)
-			}
-			if task.Error != nil && task.Error.Message != nil {
-				tbl.WriteRow("Error", *task.Error.Message)
-			}
-			return tbl.Flush()
+		if output.IsTabular() {
+			return taskColumns.RenderFields(task)
 		}
 
 		return output.Print(task)
 	},
 }
 
+// taskColumns is the column registry for async tasks. Tasks are shown one at
+// a time, so the columns are rendered as FIELD/VALUE rows.
+var taskColumns = output.Columns[*zep.GetTaskResponse]{
+	{Name: "TASK ID", Value: func(t *zep.GetTaskResponse) string { return stringValue(t.TaskID) }},
+	{Name: "STATUS", Value: func(t *zep.GetTaskResponse) string { return stringValue(t.Status) }},
+	{Name: "TYPE", Value: func(t *zep.GetTaskResponse) string { return stringValue(t.Type) }},
+	{Name: "CREATED AT", Value: func(t *zep.GetTaskResponse) string { return stringValue(t.CreatedAt) }},
+	{Name: "STARTED AT", Value: func(t *zep.GetTaskResponse) string { return stringValue(t.StartedAt) }},
+	{Name: "COMPLETED AT", Value: func(t *zep.GetTaskResponse) string { return stringValue(t.CompletedAt) }},
+	{Name: "ERROR", Value: func(t *zep.GetTaskResponse) string {
+		if t.Error == nil {
+			return ""
+		}
+		return stringValue(t.Error.Message)
+	}},
+	{Name: "DURATION", Wide: true, Value: taskDuration},
+}
+
+// taskDuration returns how long a task ran, or has been running so far.
+func taskDuration(t *zep.GetTaskResponse) string {
+	if t.StartedAt == nil {
+		return ""
+	}
+	started, err := time.Parse(time.RFC3339, *t.StartedAt)
+	if err != nil {
+		return ""
+	}
+	end := time.Now()
+	if t.CompletedAt != nil {
+		if completed, err := time.Parse(time.RFC3339, *t.CompletedAt); err == nil {
+			end = completed
+		}
+	}
+	return end.Sub(started).Round(time.Millisecond).String()
+}
+
 var taskWaitCmd = &cobra.Command{
 	Use:   "wait <task-id>",
 	Short: "Wait for task completion",
@@ -71,8 +85,10 @@ var taskWaitCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		taskID := args[0]
-		timeout, _ := cmd.Flags().GetDuration("timeout")
-		pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
+		timeout, pollInterval, err := waitFlags(cmd)
+		if err != nil {
+			return err
+		}
 
 		c, err := client.New()
 		if err != nil {
@@ -99,6 +115,62 @@ const (
 // waitForTask polls the task status until completion or failure.
 // This is a shared helper used by commands that need to wait for async operations.
 func waitForTask(c *client.Client, taskID string, timeout, pollInterval time.Duration) error {
+	return poll(timeout, pollInterval, "task "+taskID, func(ctx context.Context) (bool, error) {
+		return taskDone(ctx, c, taskID)
+	})
+}
+
+// taskDone reports whether a task has completed, or returns an error if it
+// failed.
+func taskDone(ctx context.Context, c *client.Client, taskID string) (bool, error) {
+	task, err := c.Task.Get(ctx, taskID)
+	if err != nil {
+		return false, fmt.Errorf("getting task: %w", err)
+	}
+
+	status := ""
+	if task.Status != nil {
+		status = *task.Status
+	}
+
+	switch status {
+	case "completed":
+		return true, nil
+	case "failed":
+		errMsg := "unknown error"
+		if task.Error != nil && task.Error.Message != nil {
+			errMsg = *task.Error.Message
+		}
+		return false, fmt.Errorf("task %s failed: %s", taskID, errMsg)
+	}
+	return false, nil
+}
+
+// waitFlags returns the --timeout and --poll-interval flags of a command
+// that can wait for an async operation.
+func waitFlags(cmd *cobra.Command) (timeout, pollInterval time.Duration, err error) {
+	timeout, _ = cmd.Flags().GetDuration("timeout")
+	pollInterval, _ = cmd.Flags().GetDuration("poll-interval")
+	if timeout <= 0 {
+		return 0, 0, invalidArgsf("--timeout must be positive")
+	}
+	if pollInterval <= 0 {
+		return 0, 0, invalidArgsf("--poll-interval must be positive")
+	}
+	return timeout, pollInterval, nil
+}
+
+// addWaitFlags registers the --timeout and --poll-interval flags read by
+// waitFlags.
+func addWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration("timeout", defaultTaskTimeout, "Maximum wait time")
+	cmd.Flags().Duration("poll-interval", defaultTaskPollInterval, "Polling interval")
+}
+
+// poll calls check every pollInterval until it reports done or fails. If
+// timeout passes first, poll returns an error that exits with ExitTimeout;
+// what names the thing being waited for in that error.
+func poll(timeout, pollInterval time.Duration, what string, check func(ctx context.Context) (bool, error)) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -108,27 +180,17 @@ func waitForTask(c *client.Client, taskID string, timeout, pollInterval time.Dur
 	for {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("timeout waiting for task %s", taskID)
+			return fmt.Errorf("timeout waiting for %s: %w", what, ctx.Err())
 		case <-ticker.C:
-			task, err := c.Task.Get(ctx, taskID)
+			done, err := check(ctx)
 			if err != nil {
-				return fmt.Errorf("getting task: %w", err)
-			}
-
-			status := ""
-			if task.Status != nil {
-				status = *task.Status
+				if ctx.Err() != nil {
+					return fmt.Errorf("timeout waiting for %s: %w", what, ctx.Err())
+				}
+				return err
 			}
-
-			switch status {
-			case "completed":
+			if done {
 				return nil
-			case "failed":
-				errMsg := "unknown error"
-				if task.Error != nil && task.Error.Message != nil {
-					errMsg = *task.Error.Message
-				}
-				return fmt.Errorf("task %s failed: %s", taskID, errMsg)
 			}
 		}
 	}
@@ -140,6 +202,5 @@ func init() {
 	taskCmd.AddCommand(taskWaitCmd)
 
 	// Wait flags
-	taskWaitCmd.Flags().Duration("timeout", defaultTaskTimeout, "Maximum wait time")
-	taskWaitCmd.Flags().Duration("poll-interval", defaultTaskPollInterval, "Polling interval")
+	addWaitFlags(taskWaitCmd)
 }
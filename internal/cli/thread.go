@@ -3,15 +3,14 @@ package cli
 import (
 	"bufio"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"strings"
 
 	"github.com/getzep/zep-go/v3"
 	"github.com/getzep/zepctl/internal/client"
 	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/pagination"
 	"github.com/spf13/cobra"
 )
 
@@ -35,47 +34,59 @@ var threadListCmd = &cobra.Command{
 		orderBy, _ := cmd.Flags().GetString("order-by")
 		asc, _ := cmd.Flags().GetBool("asc")
 
-		req := &zep.ThreadListAllRequest{
-			PageNumber: zep.Int(page),
-			PageSize:   zep.Int(pageSize),
-		}
-		if orderBy != "" {
-			req.OrderBy = zep.String(orderBy)
+		newRequest := func(pageNumber, pageSize int) *zep.ThreadListAllRequest {
+			req := &zep.ThreadListAllRequest{
+				PageNumber: zep.Int(pageNumber),
+				PageSize:   zep.Int(pageSize),
+			}
+			if orderBy != "" {
+				req.OrderBy = zep.String(orderBy)
+			}
+			if cmd.Flags().Changed("asc") {
+				req.Asc = zep.Bool(asc)
+			}
+			return req
 		}
-		if cmd.Flags().Changed("asc") {
-			req.Asc = zep.Bool(asc)
+
+		if paginate, maxItems := wantsAllPages(cmd); paginate {
+			it := pagination.NewPageIterator(page, pageSize, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.Thread, error) {
+				threads, err := c.Thread.ListAll(ctx, newRequest(pageNumber, pageSize))
+				if err != nil {
+					return nil, fmt.Errorf("listing threads: %w", err)
+				}
+				return threads.Threads, nil
+			}).WithMaxItems(maxItems)
+			return printPages(it, threadColumns)
 		}
 
-		threads, err := c.Thread.ListAll(context.Background(), req)
+		threads, err := c.Thread.ListAll(context.Background(), newRequest(page, pageSize))
 		if err != nil {
 			return fmt.Errorf("listing threads: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("THREAD ID", "USER ID", "CREATED AT")
-			tbl.WriteHeader()
-			for _, t := range threads.Threads {
-				threadID := ""
-				if t.ThreadID != nil {
-					threadID = *t.ThreadID
-				}
-				userID := ""
-				if t.UserID != nil {
-					userID = *t.UserID
-				}
-				createdAt := ""
-				if t.CreatedAt != nil {
-					createdAt = *t.CreatedAt
-				}
-				tbl.WriteRow(threadID, userID, createdAt)
-			}
-			return tbl.Flush()
-		}
-
-		return output.Print(threads)
+		return printList(threadColumns, threads.Threads, threads)
 	},
 }
 
+// threadColumns is the column registry for threads.
+var threadColumns = output.Columns[*zep.Thread]{
+	{Name: "THREAD ID", Value: func(t *zep.Thread) string { return stringValue(t.ThreadID) }},
+	{Name: "USER ID", Value: func(t *zep.Thread) string { return stringValue(t.UserID) }},
+	{Name: "CREATED AT", Value: func(t *zep.Thread) string { return stringValue(t.CreatedAt) }},
+	{Name: "UUID", Wide: true, Value: func(t *zep.Thread) string { return stringValue(t.UUID) }},
+}
+
+// messageColumns is the column registry for thread messages.
+var messageColumns = output.Columns[*zep.Message]{
+	{Name: "ROLE", Value: func(m *zep.Message) string { return string(m.Role) }},
+	{Name: "NAME", Value: func(m *zep.Message) string { return stringValue(m.Name) }},
+	{Name: "CONTENT", Value: func(m *zep.Message) string { return output.Truncate(m.Content, 50) }},
+	{Name: "CREATED AT", Value: func(m *zep.Message) string { return stringValue(m.CreatedAt) }},
+	{Name: "UUID", Wide: true, Value: func(m *zep.Message) string { return stringValue(m.UUID) }},
+	{Name: "PROCESSED", Wide: true, Value: func(m *zep.Message) string { return boolValue(m.Processed) }},
+	{Name: "METADATA KEYS", Wide: true, Value: func(m *zep.Message) string { return mapKeys(m.Metadata) }},
+}
+
 var threadCreateCmd = &cobra.Command{
 	Use:   "create <thread-id>",
 	Short: "Create a new thread",
@@ -85,7 +96,7 @@ var threadCreateCmd = &cobra.Command{
 
 		userID, _ := cmd.Flags().GetString("user")
 		if userID == "" {
-			return fmt.Errorf("--user flag is required")
+			return invalidArgsf("--user flag is required")
 		}
 
 		c, err := client.New()
@@ -103,15 +114,17 @@ var threadCreateCmd = &cobra.Command{
 			return fmt.Errorf("creating thread: %w", err)
 		}
 
+		invalidateCache(cacheThreadIDs)
 		output.Info("Created thread %q for user %q", threadID, userID)
 		return output.Print(thread)
 	},
 }
 
 var threadGetCmd = &cobra.Command{
-	Use:   "get <thread-id>",
-	Short: "Get thread messages",
-	Args:  cobra.ExactArgs(1),
+	Use:               "get <thread-id>",
+	Short:             "Get thread messages",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeThreadIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		threadID := args[0]
 		lastN, _ := cmd.Flags().GetInt("last")
@@ -131,35 +144,15 @@ var threadGetCmd = &cobra.Command{
 			return fmt.Errorf("getting thread: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("ROLE", "NAME", "CONTENT", "CREATED AT")
-			tbl.WriteHeader()
-			for _, m := range resp.Messages {
-				name := ""
-				if m.Name != nil {
-					name = *m.Name
-				}
-				content := m.Content
-				if len(content) > 50 {
-					content = content[:50] + "..."
-				}
-				createdAt := ""
-				if m.CreatedAt != nil {
-					createdAt = *m.CreatedAt
-				}
-				tbl.WriteRow(string(m.Role), name, content, createdAt)
-			}
-			return tbl.Flush()
-		}
-
-		return output.Print(resp)
+		return printList(messageColumns, resp.Messages, resp)
 	},
 }
 
 var threadDeleteCmd = &cobra.Command{
-	Use:   "delete <thread-id>",
-	Short: "Delete a thread",
-	Args:  cobra.ExactArgs(1),
+	Use:               "delete <thread-id>",
+	Short:             "Delete a thread",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeThreadIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		threadID := args[0]
 		force, _ := cmd.Flags().GetBool("force")
@@ -184,6 +177,7 @@ var threadDeleteCmd = &cobra.Command{
 			return fmt.Errorf("deleting thread: %w", err)
 		}
 
+		invalidateCache(cacheThreadIDs)
 		output.Info("Deleted thread %q", threadID)
 		return nil
 	},
@@ -192,17 +186,40 @@ var threadDeleteCmd = &cobra.Command{
 var threadMessagesCmd = &cobra.Command{
 	Use:   "messages <thread-id>",
 	Short: "List thread messages",
-	Args:  cobra.ExactArgs(1),
+	Long: `List the messages of a thread, oldest first.
+
+Without --all, one request returns the first --limit messages, or the last N
+with --last. --all and --max-items read the whole thread --limit messages at
+a time and print each page as it arrives, so -o ndjson streams long threads.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeThreadIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		threadID := args[0]
 		lastN, _ := cmd.Flags().GetInt("last")
 		limit, _ := cmd.Flags().GetInt("limit")
+		watch, interval, err := wantsWatch(cmd)
+		if err != nil {
+			return err
+		}
 
 		c, err := client.New()
 		if err != nil {
 			return err
 		}
 
+		if watch {
+			return watchList(context.Background(), interval, messageColumns, func(ctx context.Context) ([]*zep.Message, error) {
+				return watchedMessages(ctx, c, threadID, lastN, limit)
+			}, func(m *zep.Message) string { return stringValue(m.UUID) })
+		}
+
+		if paginate, maxItems := wantsAllPages(cmd); paginate {
+			if lastN > 0 {
+				return invalidArgsf("--last cannot be used with --all or --max-items")
+			}
+			return printPages(messagePages(c, threadID, limit).WithMaxItems(maxItems), messageColumns)
+		}
+
 		req := &zep.ThreadGetRequest{}
 		if lastN > 0 {
 			req.Lastn = zep.Int(lastN)
@@ -215,29 +232,42 @@ var threadMessagesCmd = &cobra.Command{
 			return fmt.Errorf("getting thread messages: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("ROLE", "NAME", "CONTENT", "CREATED AT")
-			tbl.WriteHeader()
-			for _, m := range messages.Messages {
-				name := ""
-				if m.Name != nil {
-					name = *m.Name
-				}
-				content := m.Content
-				if len(content) > 50 {
-					content = content[:50] + "..."
-				}
-				createdAt := ""
-				if m.CreatedAt != nil {
-					createdAt = *m.CreatedAt
-				}
-				tbl.WriteRow(string(m.Role), name, content, createdAt)
-			}
-			return tbl.Flush()
+		return printList(messageColumns, messages.Messages, messages)
+	},
+}
+
+// watchedMessages fetches the messages of a thread for --watch: the last N
+// messages with --last, otherwise every message, reading limit messages per
+// request.
+func watchedMessages(ctx context.Context, c *client.Client, threadID string, lastN, limit int) ([]*zep.Message, error) {
+	if lastN > 0 {
+		resp, err := c.Thread.Get(ctx, threadID, &zep.ThreadGetRequest{Lastn: zep.Int(lastN)})
+		if err != nil {
+			return nil, fmt.Errorf("getting thread messages: %w", err)
 		}
+		return resp.Messages, nil
+	}
 
-		return output.Print(messages)
-	},
+	return messagePages(c, threadID, limit).All(ctx)
+}
+
+// messagePages iterates over the messages of a thread, reading limit
+// messages per request. The message cursor is the number of messages already
+// read.
+func messagePages(c *client.Client, threadID string, limit int) *pagination.Iterator[*zep.Message] {
+	if limit <= 0 {
+		limit = snapshotPageSize
+	}
+	return pagination.NewPageIterator(1, limit, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.Message, error) {
+		resp, err := c.Thread.Get(ctx, threadID, &zep.ThreadGetRequest{
+			Limit:  zep.Int(pageSize),
+			Cursor: zep.Int((pageNumber - 1) * pageSize),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("getting thread messages: %w", err)
+		}
+		return resp.Messages, nil
+	})
 }
 
 // MessageInput represents the input format for adding messages.
@@ -254,9 +284,10 @@ type MessageData struct {
 }
 
 var threadAddMessagesCmd = &cobra.Command{
-	Use:   "add-messages <thread-id>",
-	Short: "Add messages to a thread",
-	Args:  cobra.ExactArgs(1),
+	Use:               "add-messages <thread-id>",
+	Short:             "Add messages to a thread",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeThreadIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		threadID := args[0]
 
@@ -264,36 +295,23 @@ var threadAddMessagesCmd = &cobra.Command{
 		useStdin, _ := cmd.Flags().GetBool("stdin")
 		batch, _ := cmd.Flags().GetBool("batch")
 		wait, _ := cmd.Flags().GetBool("wait")
+		inputFormatFlag, _ := cmd.Flags().GetString("input-format")
 
-		var data []byte
-		var err error
-
-		if file != "" {
-			data, err = os.ReadFile(file)
-			if err != nil {
-				return fmt.Errorf("reading file: %w", err)
-			}
-		} else if useStdin {
-			data, err = io.ReadAll(os.Stdin)
-			if err != nil {
-				return fmt.Errorf("reading stdin: %w", err)
-			}
-		} else {
-			return fmt.Errorf("either --file or --stdin is required")
+		if file == "" && !useStdin {
+			return invalidArgsf("either --file or --stdin is required")
 		}
-
-		var input MessageInput
-		if err := json.Unmarshal(data, &input); err != nil {
-			return fmt.Errorf("parsing messages: %w", err)
+		format, err := inputFormat(inputFormatFlag, file)
+		if err != nil {
+			return err
 		}
-
-		c, err := client.New()
+		in, err := openInput(file)
 		if err != nil {
 			return err
 		}
+		defer in.Close()
 
 		var messages []*zep.Message
-		for _, m := range input.Messages {
+		err = readRecords(in, format, "messages", func(m *MessageData, _ int) error {
 			msg := &zep.Message{
 				Role:    zep.RoleType(m.Role),
 				Content: m.Content,
@@ -305,6 +323,18 @@ var threadAddMessagesCmd = &cobra.Command{
 				msg.Metadata = m.Metadata
 			}
 			messages = append(messages, msg)
+			return nil
+		})
+		if err != nil {
+			return asInvalidArgs(fmt.Errorf("parsing messages: %w", err))
+		}
+		if len(messages) == 0 {
+			return invalidArgsf("no messages in input")
+		}
+
+		c, err := client.New()
+		if err != nil {
+			return err
 		}
 
 		if batch {
@@ -341,10 +371,11 @@ var threadAddMessagesCmd = &cobra.Command{
 }
 
 var threadContextCmd = &cobra.Command{
-	Use:   "context <thread-id>",
-	Short: "Get thread context",
-	Long:  `Returns relevant context from the user graph based on recent thread messages.`,
-	Args:  cobra.ExactArgs(1),
+	Use:               "context <thread-id>",
+	Short:             "Get thread context",
+	Long:              `Returns relevant context from the user graph based on recent thread messages.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeThreadIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		threadID := args[0]
 
@@ -377,6 +408,7 @@ func init() {
 	threadListCmd.Flags().Int("page-size", 50, "Results per page")
 	threadListCmd.Flags().String("order-by", "", "Order by field (created_at, updated_at, user_id, thread_id)")
 	threadListCmd.Flags().Bool("asc", false, "Sort ascending")
+	addPaginationFlags(threadListCmd)
 
 	// Create flags
 	threadCreateCmd.Flags().String("user", "", "User ID (required)")
@@ -390,10 +422,13 @@ func init() {
 
 	// Messages flags
 	threadMessagesCmd.Flags().Int("last", 0, "Get last N messages")
-	threadMessagesCmd.Flags().Int("limit", 50, "Maximum messages to return")
+	threadMessagesCmd.Flags().Int("limit", 50, "Maximum messages to return (messages per page with --all)")
+	addPaginationFlags(threadMessagesCmd)
+	addWatchFlags(threadMessagesCmd)
 
 	// Add messages flags
-	threadAddMessagesCmd.Flags().String("file", "", "Path to JSON file containing messages")
+	threadAddMessagesCmd.Flags().String("file", "", "Path to JSON, JSONL or YAML file containing messages")
+	threadAddMessagesCmd.Flags().String("input-format", "", "Input format: json, jsonl, or yaml (default from the file extension, json for stdin)")
 	threadAddMessagesCmd.Flags().Bool("stdin", false, "Read messages from stdin")
 	threadAddMessagesCmd.Flags().Bool("batch", false, "Use batch processing for large imports")
 	threadAddMessagesCmd.Flags().Bool("wait", false, "Wait for batch processing to complete")
@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zepctl/internal/chatimport"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var threadImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import chat history exports into threads",
+	Long: `Import the conversations of a chat history export, creating one thread per
+conversation with its messages in their original order and timestamps.
+
+Formats:
+  chatgpt          conversations.json of a ChatGPT data export, or its directory
+  slack            an unzipped Slack workspace export, or one channel directory
+  discord          DiscordChatExporter JSON files, or a directory of them
+  openai-messages  Chat Completions messages as JSON, or JSONL with one
+                   {"messages": [...]} conversation per line
+
+ChatGPT and OpenAI messages keep their roles. In Slack and Discord, bots are
+assistants and everyone else is a user; --assistant marks more speakers, by
+name or ID, as assistants. Speaker names are kept as message names.
+
+Threads are named <format>-<conversation id>, e.g. slack-C0123ABCD, so
+importing a newer export of the same conversations finds the threads from
+the last import. Threads that already exist are handled according to
+--conflict:
+  skip       leave the existing thread unchanged (default)
+  overwrite  delete the thread and import it again
+  fail       stop the import
+A thread that exists for another user is never overwritten: it is skipped
+with a warning, and stops the import with overwrite or fail.
+
+Conversations without messages are skipped with a warning.
+
+Without --user, the threads belong to a new user named after the format and
+the time of the import. A --user that does not exist is created.`,
+	Example: `  zepctl thread import --format chatgpt --file ./chatgpt-export --user alice
+  zepctl thread import --format slack --file ./slack-export --assistant support-bot
+  zepctl thread import --format openai-messages --file chats.jsonl --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		file, _ := cmd.Flags().GetString("file")
+		userID, _ := cmd.Flags().GetString("user")
+		assistants, _ := cmd.Flags().GetStringArray("assistant")
+		conflict, _ := cmd.Flags().GetString("conflict")
+		batch, _ := cmd.Flags().GetBool("batch")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		adapter, ok := chatimport.Lookup(format)
+		if !ok {
+			return invalidArgsf("invalid --format %q: must be one of %s", format, strings.Join(chatimport.Formats(), ", "))
+		}
+		switch conflict {
+		case conflictSkip, conflictOverwrite, conflictFail:
+		default:
+			return invalidArgsf("invalid --conflict %q: must be skip, overwrite or fail", conflict)
+		}
+
+		convs, err := adapter.Parse(file, chatimport.Options{Assistants: assistants})
+		if err != nil {
+			return asInvalidArgs(fmt.Errorf("parsing %s export: %w", format, err))
+		}
+		convs = slices.DeleteFunc(convs, func(conv *chatimport.Conversation) bool {
+			if len(conv.Messages) == 0 {
+				output.Warn("Skipping conversation %q: it has no messages", conv.ID)
+				return true
+			}
+			return false
+		})
+		if len(convs) == 0 {
+			return invalidArgsf("no conversations with messages in %s", file)
+		}
+		if userID == "" {
+			userID = fmt.Sprintf("%s-import-%s", format, time.Now().UTC().Format("20060102T150405Z"))
+		}
+
+		imported := make([]*importedThread, len(convs))
+		total := 0
+		for i, conv := range convs {
+			imported[i] = &importedThread{
+				ThreadID: format + "-" + conv.ID,
+				Title:    conv.Title,
+				Messages: len(conv.Messages),
+			}
+			if t := conv.Messages[0].CreatedAt; !t.IsZero() {
+				imported[i].StartedAt = t.Format(time.RFC3339)
+			}
+			total += len(conv.Messages)
+		}
+		if dryRun {
+			output.Info("Dry run: would import %d messages into %d threads for user %q", total, len(convs), userID)
+			return printList(importedThreadColumns, imported, imported)
+		}
+
+		c, err := client.New()
+		if err != nil {
+			return err
+		}
+		ti := &threadImporter{c: c, userID: userID, conflict: conflict, batch: batch}
+		ctx := context.Background()
+		if err := ti.ensureUser(ctx); err != nil {
+			return err
+		}
+
+		progress := output.NewProgress("Importing threads").WithTotal(len(convs))
+		changed := false
+		for i, conv := range convs {
+			t := imported[i]
+			t.Status, err = ti.importConversation(ctx, t.ThreadID, conv)
+			if err != nil {
+				break
+			}
+			changed = changed || t.Status != outcomeSkipped
+			progress.Add(t.Status, 1)
+		}
+		progress.Done()
+		if changed {
+			invalidateCache(cacheThreadIDs)
+		}
+		if err != nil {
+			return err
+		}
+
+		output.Info("Imported %d conversations for user %q", len(convs), userID)
+		return printList(importedThreadColumns, imported, imported)
+	},
+}
+
+// importedThread describes a conversation imported by thread import.
+type importedThread struct {
+	ThreadID  string `json:"thread_id"`
+	Title     string `json:"title,omitempty"`
+	Messages  int    `json:"messages"`
+	StartedAt string `json:"started_at,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+// importedThreadColumns is the column registry for thread import.
+var importedThreadColumns = output.Columns[*importedThread]{
+	{Name: "THREAD ID", Value: func(t *importedThread) string { return t.ThreadID }},
+	{Name: "TITLE", Value: func(t *importedThread) string { return t.Title }},
+	{Name: "MESSAGES", Value: func(t *importedThread) string { return strconv.Itoa(t.Messages) }},
+	{Name: "STARTED AT", Value: func(t *importedThread) string { return t.StartedAt }},
+	{Name: "STATUS", Value: func(t *importedThread) string { return t.Status }},
+}
+
+// threadImporter loads parsed conversations into threads of one user.
+type threadImporter struct {
+	c        *client.Client
+	userID   string
+	conflict string
+	batch    bool
+	// owned holds the IDs of the user's existing threads.
+	owned map[string]bool
+}
+
+// ensureUser creates the user the threads belong to, unless it exists, and
+// lists the threads it already has.
+func (ti *threadImporter) ensureUser(ctx context.Context) error {
+	ti.owned = make(map[string]bool)
+	_, err := ti.c.User.Get(ctx, ti.userID)
+	if err == nil {
+		threads, err := ti.c.User.GetThreads(ctx, ti.userID)
+		if err != nil {
+			return fmt.Errorf("listing threads of user %q: %w", ti.userID, err)
+		}
+		for _, t := range threads {
+			ti.owned[stringValue(t.ThreadID)] = true
+		}
+		return nil
+	}
+	if !isNotFound(err) {
+		return fmt.Errorf("getting user %q: %w", ti.userID, err)
+	}
+	if _, err := ti.c.User.Add(ctx, &zep.CreateUserRequest{UserID: ti.userID}); err != nil {
+		return fmt.Errorf("creating user %q: %w", ti.userID, err)
+	}
+	output.Info("Created user %q", ti.userID)
+	invalidateCache(cacheUsers)
+	invalidateCache(cacheUserIDs)
+	return nil
+}
+
+// importConversation creates a thread for a conversation and adds its
+// messages in order, returning the outcome for the thread.
+func (ti *threadImporter) importConversation(ctx context.Context, threadID string, conv *chatimport.Conversation) (string, error) {
+	outcome := outcomeImported
+	_, err := ti.c.Thread.Get(ctx, threadID, &zep.ThreadGetRequest{Limit: zep.Int(1)})
+	switch {
+	case err == nil && !ti.owned[threadID]:
+		if ti.conflict == conflictSkip {
+			output.Warn("Skipping thread %q: it belongs to another user", threadID)
+			return outcomeSkipped, nil
+		}
+		return "", fmt.Errorf("thread %q already exists and belongs to another user, not %q", threadID, ti.userID)
+	case err == nil:
+		switch ti.conflict {
+		case conflictOverwrite:
+		case conflictFail:
+			return "", fmt.Errorf("thread %q already exists (use --conflict skip or --conflict overwrite)", threadID)
+		default:
+			return outcomeSkipped, nil
+		}
+		if _, err := ti.c.Thread.Delete(ctx, threadID); err != nil {
+			return "", fmt.Errorf("deleting thread %q: %w", threadID, err)
+		}
+		outcome = outcomeOverwritten
+	case !isNotFound(err):
+		return "", fmt.Errorf("getting thread %q: %w", threadID, err)
+	}
+
+	_, err = ti.c.Thread.Create(ctx, &zep.CreateThreadRequest{ThreadID: threadID, UserID: ti.userID})
+	if err != nil {
+		return "", fmt.Errorf("creating thread %q: %w", threadID, err)
+	}
+
+	messages := make([]*zep.Message, len(conv.Messages))
+	for i, m := range conv.Messages {
+		msg := &zep.Message{Role: zep.RoleType(m.Role), Content: m.Content}
+		if m.Name != "" {
+			msg.Name = zep.String(m.Name)
+		}
+		if !m.CreatedAt.IsZero() {
+			msg.CreatedAt = zep.String(m.CreatedAt.Format(time.RFC3339Nano))
+		}
+		messages[i] = msg
+	}
+	for start := 0; start < len(messages); start += importMessageBatch {
+		req := &zep.AddThreadMessagesRequest{Messages: messages[start:min(start+importMessageBatch, len(messages))]}
+		if ti.batch {
+			_, err = ti.c.Thread.AddMessagesBatch(ctx, threadID, req)
+		} else {
+			_, err = ti.c.Thread.AddMessages(ctx, threadID, req)
+		}
+		if err != nil {
+			return "", fmt.Errorf("adding messages to thread %q: %w", threadID, err)
+		}
+	}
+	return outcome, nil
+}
+
+func init() {
+	threadCmd.AddCommand(threadImportCmd)
+
+	threadImportCmd.Flags().String("format", "", "Export format: "+strings.Join(chatimport.Formats(), ", ")+" (required)")
+	threadImportCmd.Flags().String("file", "", "Export file or directory (required)")
+	threadImportCmd.Flags().String("user", "", "User the threads belong to, created if missing (default <format>-import-<time>)")
+	threadImportCmd.Flags().StringArray("assistant", nil, "Speaker name or ID to import as the assistant (repeatable)")
+	threadImportCmd.Flags().String("conflict", conflictSkip, "How to handle threads that already exist: skip, overwrite or fail")
+	threadImportCmd.Flags().Bool("batch", false, "Add messages with batch processing")
+	threadImportCmd.Flags().Bool("dry-run", false, "Show the conversations that would be imported without importing them")
+	_ = threadImportCmd.MarkFlagRequired("format")
+	_ = threadImportCmd.MarkFlagRequired("file")
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestThreadImport(t *testing.T) {
+	server := newSandbox(t)
+	path := filepath.Join(t.TempDir(), "chats.jsonl")
+	chats := `{"id": "c1", "title": "Tea", "messages": [{"role": "user", "name": "Alice", "content": "What tea is this?", "created_at": "2024-01-15T09:00:00Z"}, {"role": "assistant", "content": "Oolong", "created_at": "2024-01-15T09:00:05Z"}]}
+{"id": "c2", "messages": [{"role": "user", "content": "Hi"}]}
+`
+	if err := os.WriteFile(path, []byte(chats), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	importChats := func(args ...string) []importedThread {
+		t.Helper()
+		args = append([]string{"thread", "import", "--format", "openai-messages", "--file", path, "--user", "alice", "-o", "json"}, args...)
+		out, err := runCLI(t, server, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var threads []importedThread
+		if err := json.Unmarshal([]byte(out), &threads); err != nil {
+			t.Fatalf("output is not a list of threads: %v\n%s", err, out)
+		}
+		return threads
+	}
+
+	threads := importChats("--dry-run")
+	if len(threads) != 2 || threads[0].ThreadID != "openai-messages-c1" || threads[0].Status != "" {
+		t.Fatalf("dry run = %+v, want 2 threads that are not imported", threads)
+	}
+	if _, err := runCLI(t, server, "user", "get", "alice"); ExitCode(err) != ExitNotFound {
+		t.Errorf("dry run created the user (err: %v)", err)
+	}
+
+	threads = importChats()
+	for _, th := range threads {
+		if th.Status != outcomeImported {
+			t.Errorf("thread %s: status %q, want %q", th.ThreadID, th.Status, outcomeImported)
+		}
+	}
+	out, err := runCLI(t, server, "thread", "messages", "openai-messages-c1", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Messages []struct {
+			Role      string `json:"role"`
+			Name      string `json:"name"`
+			Content   string `json:"content"`
+			CreatedAt string `json:"created_at"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil || len(resp.Messages) != 2 {
+		t.Fatalf("thread messages = %s (err: %v), want 2 messages", out, err)
+	}
+	if m := resp.Messages[0]; m.Role != "user" || m.Name != "Alice" || m.Content != "What tea is this?" || m.CreatedAt != "2024-01-15T09:00:00Z" {
+		t.Errorf("first message = %+v", m)
+	}
+	if m := resp.Messages[1]; m.Role != "assistant" || m.CreatedAt != "2024-01-15T09:00:05Z" {
+		t.Errorf("second message = %+v", m)
+	}
+
+	for _, th := range importChats() {
+		if th.Status != outcomeSkipped {
+			t.Errorf("re-import of thread %s: status %q, want %q", th.ThreadID, th.Status, outcomeSkipped)
+		}
+	}
+	for _, th := range importChats("--conflict", "overwrite") {
+		if th.Status != outcomeOverwritten {
+			t.Errorf("overwrite of thread %s: status %q, want %q", th.ThreadID, th.Status, outcomeOverwritten)
+		}
+	}
+	out, err = runCLI(t, server, "thread", "messages", "openai-messages-c1", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Messages = nil
+	if err := json.Unmarshal([]byte(out), &resp); err != nil || len(resp.Messages) != 2 {
+		t.Errorf("overwritten thread has %d messages (err: %v), want 2", len(resp.Messages), err)
+	}
+
+	_, err = runCLI(t, server, "thread", "import", "--format", "openai-messages", "--file", path, "--user", "alice", "--conflict", "fail")
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("--conflict fail: err %v, want the thread to already exist", err)
+	}
+
+	// Threads of another user are never taken over.
+	out, err = runCLI(t, server, "thread", "import", "--format", "openai-messages", "--file", path, "--user", "bob", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	threads = nil
+	if err := json.Unmarshal([]byte(out), &threads); err != nil {
+		t.Fatal(err)
+	}
+	for _, th := range threads {
+		if th.Status != outcomeSkipped {
+			t.Errorf("import of alice's thread %s for bob: status %q, want %q", th.ThreadID, th.Status, outcomeSkipped)
+		}
+	}
+	_, err = runCLI(t, server, "thread", "import", "--format", "openai-messages", "--file", path, "--user", "bob", "--conflict", "overwrite")
+	if err == nil || !strings.Contains(err.Error(), "belongs to another user") {
+		t.Errorf("overwrite of alice's threads for bob: err %v, want an ownership conflict", err)
+	}
+	if _, err := runCLI(t, server, "thread", "messages", "openai-messages-c1"); err != nil {
+		t.Errorf("alice's thread was deleted: %v", err)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.jsonl")
+	if err := os.WriteFile(empty, []byte(`{"id": "e1", "messages": []}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := [][]string{
+		{"thread", "import", "--format", "openai-messages", "--file", empty, "--dry-run"},
+		{"thread", "import", "--format", "whatsapp", "--file", path},
+		{"thread", "import", "--format", "slack", "--file", path, "--conflict", "merge"},
+		{"thread", "import", "--format", "openai-messages", "--file", filepath.Join(t.TempDir(), "missing.json")},
+	}
+	for _, args := range tests {
+		if _, err := runCLI(t, server, args...); ExitCode(err) != ExitInvalidArgs {
+			t.Errorf("zepctl %s: exit code %d, want %d (err: %v)", strings.Join(args, " "), ExitCode(err), ExitInvalidArgs, err)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/getzep/zep-go/v3"
 	"github.com/getzep/zepctl/internal/client"
 	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/pagination"
 	"github.com/spf13/cobra"
 )
 
@@ -32,6 +33,20 @@ var userListCmd = &cobra.Command{
 		page, _ := cmd.Flags().GetInt("page")
 		pageSize, _ := cmd.Flags().GetInt("page-size")
 
+		if paginate, maxItems := wantsAllPages(cmd); paginate {
+			it := pagination.NewPageIterator(page, pageSize, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.User, error) {
+				users, err := c.User.ListOrdered(ctx, &zep.UserListOrderedRequest{
+					PageNumber: zep.Int(pageNumber),
+					PageSize:   zep.Int(pageSize),
+				})
+				if err != nil {
+					return nil, fmt.Errorf("listing users: %w", err)
+				}
+				return users.Users, nil
+			}).WithMaxItems(maxItems)
+			return printPages(it, userColumns)
+		}
+
 		users, err := c.User.ListOrdered(context.Background(), &zep.UserListOrderedRequest{
 			PageNumber: zep.Int(page),
 			PageSize:   zep.Int(pageSize),
@@ -40,43 +55,27 @@ var userListCmd = &cobra.Command{
 			return fmt.Errorf("listing users: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("USER ID", "EMAIL", "FIRST NAME", "LAST NAME", "CREATED AT")
-			tbl.WriteHeader()
-			for _, u := range users.Users {
-				email := ""
-				if u.Email != nil {
-					email = *u.Email
-				}
-				firstName := ""
-				if u.FirstName != nil {
-					firstName = *u.FirstName
-				}
-				lastName := ""
-				if u.LastName != nil {
-					lastName = *u.LastName
-				}
-				createdAt := ""
-				if u.CreatedAt != nil {
-					createdAt = *u.CreatedAt
-				}
-				userID := ""
-				if u.UserID != nil {
-					userID = *u.UserID
-				}
-				tbl.WriteRow(userID, email, firstName, lastName, createdAt)
-			}
-			return tbl.Flush()
-		}
-
-		return output.Print(users)
+		return printList(userColumns, users.Users, users)
 	},
 }
 
+// userColumns is the column registry for users.
+var userColumns = output.Columns[*zep.User]{
+	{Name: "USER ID", Value: func(u *zep.User) string { return stringValue(u.UserID) }},
+	{Name: "EMAIL", Value: func(u *zep.User) string { return stringValue(u.Email) }},
+	{Name: "FIRST NAME", Value: func(u *zep.User) string { return stringValue(u.FirstName) }},
+	{Name: "LAST NAME", Value: func(u *zep.User) string { return stringValue(u.LastName) }},
+	{Name: "CREATED AT", Value: func(u *zep.User) string { return stringValue(u.CreatedAt) }},
+	{Name: "UUID", Wide: true, Value: func(u *zep.User) string { return stringValue(u.UUID) }},
+	{Name: "METADATA KEYS", Wide: true, Value: func(u *zep.User) string { return mapKeys(u.Metadata) }},
+	{Name: "UPDATED AT", Wide: true, Value: func(u *zep.User) string { return stringValue(u.UpdatedAt) }},
+}
+
 var userGetCmd = &cobra.Command{
-	Use:   "get <user-id>",
-	Short: "Get user details",
-	Args:  cobra.ExactArgs(1),
+	Use:               "get <user-id>",
+	Short:             "Get user details",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeUserIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		userID := args[0]
 
@@ -85,12 +84,14 @@ var userGetCmd = &cobra.Command{
 			return err
 		}
 
-		user, err := c.User.Get(context.Background(), userID)
+		user, err := cached(cacheUsers, userID, userCacheTTL, func() (*zep.User, error) {
+			return c.User.Get(context.Background(), userID)
+		})
 		if err != nil {
 			return fmt.Errorf("getting user: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
+		if output.IsTabular() {
 			tbl := output.NewTable("FIELD", "VALUE")
 			tbl.WriteHeader()
 			userIDStr := ""
@@ -163,7 +164,7 @@ var userCreateCmd = &cobra.Command{
 		if metadataStr != "" {
 			var metadata map[string]any
 			if err := json.Unmarshal([]byte(metadataStr), &metadata); err != nil {
-				return fmt.Errorf("parsing metadata: %w", err)
+				return asInvalidArgs(fmt.Errorf("parsing metadata: %w", err))
 			}
 			req.Metadata = metadata
 		}
@@ -173,15 +174,17 @@ var userCreateCmd = &cobra.Command{
 			return fmt.Errorf("creating user: %w", err)
 		}
 
+		invalidateCache(cacheUserIDs)
 		output.Info("Created user %q", userID)
 		return output.Print(user)
 	},
 }
 
 var userUpdateCmd = &cobra.Command{
-	Use:   "update <user-id>",
-	Short: "Update an existing user",
-	Args:  cobra.ExactArgs(1),
+	Use:               "update <user-id>",
+	Short:             "Update an existing user",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeUserIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		userID := args[0]
 
@@ -219,7 +222,7 @@ var userUpdateCmd = &cobra.Command{
 		if metadataStr != "" {
 			var metadata map[string]any
 			if err := json.Unmarshal([]byte(metadataStr), &metadata); err != nil {
-				return fmt.Errorf("parsing metadata: %w", err)
+				return asInvalidArgs(fmt.Errorf("parsing metadata: %w", err))
 			}
 			req.Metadata = metadata
 		}
@@ -229,16 +232,19 @@ var userUpdateCmd = &cobra.Command{
 			return fmt.Errorf("updating user: %w", err)
 		}
 
+		invalidateCache(cacheUsers, userID)
+		invalidateCache(cacheUserIDs)
 		output.Info("Updated user %q", userID)
 		return output.Print(user)
 	},
 }
 
 var userDeleteCmd = &cobra.Command{
-	Use:   "delete <user-id>",
-	Short: "Delete a user",
-	Long:  `Delete a user and all associated data (threads, graph, knowledge). Supports RTBF compliance.`,
-	Args:  cobra.ExactArgs(1),
+	Use:               "delete <user-id>",
+	Short:             "Delete a user",
+	Long:              `Delete a user and all associated data (threads, graph, knowledge). Supports RTBF compliance.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeUserIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		userID := args[0]
 		force, _ := cmd.Flags().GetBool("force")
@@ -263,15 +269,20 @@ var userDeleteCmd = &cobra.Command{
 			return fmt.Errorf("deleting user: %w", err)
 		}
 
+		// Deleting a user also deletes its threads.
+		invalidateCache(cacheUsers, userID)
+		invalidateCache(cacheUserIDs)
+		invalidateCache(cacheThreadIDs)
 		output.Info("Deleted user %q", userID)
 		return nil
 	},
 }
 
 var userThreadsCmd = &cobra.Command{
-	Use:   "threads <user-id>",
-	Short: "List user threads",
-	Args:  cobra.ExactArgs(1),
+	Use:               "threads <user-id>",
+	Short:             "List user threads",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeUserIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		userID := args[0]
 
@@ -285,31 +296,15 @@ var userThreadsCmd = &cobra.Command{
 			return fmt.Errorf("getting user threads: %w", err)
 		}
 
-		if output.GetFormat() == output.FormatTable {
-			tbl := output.NewTable("THREAD ID", "CREATED AT")
-			tbl.WriteHeader()
-			for _, t := range threads {
-				createdAt := ""
-				if t.CreatedAt != nil {
-					createdAt = *t.CreatedAt
-				}
-				threadID := ""
-				if t.ThreadID != nil {
-					threadID = *t.ThreadID
-				}
-				tbl.WriteRow(threadID, createdAt)
-			}
-			return tbl.Flush()
-		}
-
-		return output.Print(threads)
+		return printList(threadColumns.WithDefault("THREAD ID", "CREATED AT"), threads, threads)
 	},
 }
 
 var userNodeCmd = &cobra.Command{
-	Use:   "node <user-id>",
-	Short: "Get user graph node",
-	Args:  cobra.ExactArgs(1),
+	Use:               "node <user-id>",
+	Short:             "Get user graph node",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeUserIDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		userID := args[0]
 
@@ -340,6 +335,7 @@ func init() {
 	// List flags
 	userListCmd.Flags().Int("page", 1, "Page number")
 	userListCmd.Flags().Int("page-size", 50, "Results per page")
+	addPaginationFlags(userListCmd)
 
 	// Create flags
 	userCreateCmd.Flags().String("email", "", "User email address")
@@ -0,0 +1,471 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/getzep/zepctl/internal/client"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/pagination"
+	"github.com/spf13/cobra"
+)
+
+// Input formats of user import.
+const (
+	userImportCSV   = "csv"
+	userImportJSONL = "jsonl"
+)
+
+// Outcomes of a row in a user import.
+const (
+	rowCreated = "created"
+	rowUpdated = "updated"
+	rowSkipped = "skipped"
+	rowFailed  = "failed"
+)
+
+// userImportFields are the user fields a column can be mapped to. Columns
+// mapped to "-" are ignored.
+var userImportFields = []string{"user_id", "email", "first_name", "last_name", "metadata"}
+
+var userImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Create or update users from a CSV or JSONL file",
+	Long: `Create or update many users from a CSV file with a header row, or a JSONL
+file with one JSON object per line.
+
+Columns (or keys) named user_id, email, first_name and last_name set those
+fields, and a metadata column holds a JSON object. Any other column is added
+to the user's metadata. Use --map to rename columns, e.g.
+--map id=user_id,mail=email, or map a column to - to ignore it.
+
+Rows are imported by --concurrency workers in parallel. Users that already
+exist are handled according to --conflict:
+  skip       leave the existing user unchanged (default)
+  overwrite  update the existing user with the non-empty fields of the row
+  fail       report the row as failed
+
+A user ID that appears on several rows is imported from its first row, and
+the later rows are skipped.
+
+The outcome of every row is written to a JSONL results file. Existing users
+are looked up before importing, so if a run fails part way, running it again
+with --conflict skip only imports the users that are still missing.`,
+	Example: `  zepctl user import --file users.csv
+  zepctl user import --file users.jsonl --conflict overwrite --concurrency 16
+  zepctl user import --file export.csv --map id=user_id,mail=email,notes=-`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		format, _ := cmd.Flags().GetString("format")
+		mapping, _ := cmd.Flags().GetStringToString("map")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		conflict, _ := cmd.Flags().GetString("conflict")
+		resultsPath, _ := cmd.Flags().GetString("results")
+
+		if file == "" {
+			return invalidArgsf("--file is required")
+		}
+		if format == "" {
+			format = userImportFormat(file)
+		}
+		if format != userImportCSV && format != userImportJSONL {
+			return invalidArgsf("invalid --format %q: must be csv or jsonl", format)
+		}
+		switch conflict {
+		case conflictSkip, conflictOverwrite, conflictFail:
+		default:
+			return invalidArgsf("invalid --conflict %q: must be skip, overwrite or fail", conflict)
+		}
+		if concurrency < 1 {
+			return invalidArgsf("--concurrency must be at least 1")
+		}
+		for column, field := range mapping {
+			if field != "-" && !slices.Contains(userImportFields, field) {
+				return invalidArgsf("invalid --map %s=%s: must map to %s or -", column, field, strings.Join(userImportFields, ", "))
+			}
+		}
+		if resultsPath == "" {
+			resultsPath = file + ".results.jsonl"
+		}
+
+		rows, err := readUserRows(file, format, mapping)
+		if err != nil {
+			return err
+		}
+
+		c, err := client.New()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		existing, err := existingUserIDs(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		results, err := os.Create(resultsPath)
+		if err != nil {
+			return fmt.Errorf("creating results file: %w", err)
+		}
+		defer results.Close()
+
+		im := &userImporter{
+			c:        c,
+			conflict: conflict,
+			existing: existing,
+			results:  json.NewEncoder(results),
+			progress: output.NewProgress("Importing users"),
+			counts:   make(map[string]int),
+		}
+		im.run(ctx, rows, concurrency)
+		im.progress.Done()
+		if err := im.err; err != nil {
+			return fmt.Errorf("writing results file: %w", err)
+		}
+
+		if im.counts[rowCreated]+im.counts[rowUpdated] > 0 {
+			invalidateCache(cacheUsers)
+			invalidateCache(cacheUserIDs)
+		}
+
+		summary := userImportSummary{
+			Created: im.counts[rowCreated],
+			Updated: im.counts[rowUpdated],
+			Skipped: im.counts[rowSkipped],
+			Failed:  im.counts[rowFailed],
+			Results: resultsPath,
+		}
+		if output.IsTabular() {
+			tbl := output.NewTable("CREATED", "UPDATED", "SKIPPED", "FAILED")
+			tbl.WriteHeader()
+			tbl.WriteRow(strconv.Itoa(summary.Created), strconv.Itoa(summary.Updated), strconv.Itoa(summary.Skipped), strconv.Itoa(summary.Failed))
+			if err := tbl.Flush(); err != nil {
+				return err
+			}
+		} else if err := output.Print(summary); err != nil {
+			return err
+		}
+
+		if summary.Failed > 0 {
+			return fmt.Errorf("%d of %d rows failed; see %s", summary.Failed, len(rows), resultsPath)
+		}
+		output.Info("Wrote results to %s", resultsPath)
+		return nil
+	},
+}
+
+// userImportSummary counts the outcomes of a user import.
+type userImportSummary struct {
+	Created int    `json:"created"`
+	Updated int    `json:"updated"`
+	Skipped int    `json:"skipped"`
+	Failed  int    `json:"failed"`
+	Results string `json:"results"`
+}
+
+// userImportResult is the outcome of one row, written to the results file.
+type userImportResult struct {
+	Line   int    `json:"line"`
+	UserID string `json:"user_id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// userRow is a user read from an import file, or the reason the row cannot
+// be imported.
+type userRow struct {
+	line int
+	user *zep.CreateUserRequest
+	err  error
+}
+
+// userImportFormat guesses the format of a file from its extension.
+func userImportFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".jsonl", ".ndjson":
+		return userImportJSONL
+	default:
+		return userImportCSV
+	}
+}
+
+// readUserRows reads every row of an import file. A file that cannot be
+// parsed fails as a whole, while rows with invalid values are returned with
+// their error so they are reported in the results.
+func readUserRows(file, format string, mapping map[string]string) ([]userRow, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("opening import file: %w", err)
+	}
+	defer f.Close()
+
+	column := func(name string) string {
+		if field, ok := mapping[name]; ok {
+			return field
+		}
+		return name
+	}
+
+	var rows []userRow
+	if format == userImportJSONL {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 64<<20)
+		for line := 1; scanner.Scan(); line++ {
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+			var obj map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &obj); err != nil {
+				rows = append(rows, userRow{line: line, err: fmt.Errorf("parsing JSON: %w", err)})
+				continue
+			}
+			fields := make(map[string]any, len(obj))
+			for k, v := range obj {
+				fields[column(k)] = v
+			}
+			user, err := userFromFields(fields)
+			rows = append(rows, userRow{line: line, user: user, err: err})
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading import file: %w", err)
+		}
+		return rows, nil
+	}
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, invalidArgsf("%s is empty", file)
+	}
+	if err != nil {
+		return nil, asInvalidArgs(fmt.Errorf("parsing CSV: %w", err))
+	}
+	for i, name := range header {
+		header[i] = column(strings.TrimSpace(name))
+	}
+	if !slices.Contains(header, "user_id") {
+		return nil, invalidArgsf("%s has no user_id column (use --map to map one)", file)
+	}
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, asInvalidArgs(fmt.Errorf("parsing CSV: %w", err))
+		}
+		line, _ := r.FieldPos(0)
+		fields := make(map[string]any, len(record))
+		for i, value := range record {
+			if value != "" {
+				fields[header[i]] = value
+			}
+		}
+		user, err := userFromFields(fields)
+		rows = append(rows, userRow{line: line, user: user, err: err})
+	}
+}
+
+// userFromFields builds a user from the mapped columns of a row. Columns
+// that are not user fields become metadata.
+func userFromFields(fields map[string]any) (*zep.CreateUserRequest, error) {
+	user := &zep.CreateUserRequest{}
+	str := func(name string) (*string, error) {
+		v, ok := fields[name]
+		if !ok || v == nil {
+			return nil, nil
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", name)
+		}
+		if s == "" {
+			return nil, nil
+		}
+		return zep.String(s), nil
+	}
+
+	userID, err := str("user_id")
+	if err != nil {
+		return nil, err
+	}
+	if userID == nil {
+		return nil, errors.New("user_id is required")
+	}
+	user.UserID = *userID
+	if user.Email, err = str("email"); err != nil {
+		return nil, err
+	}
+	if user.FirstName, err = str("first_name"); err != nil {
+		return nil, err
+	}
+	if user.LastName, err = str("last_name"); err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]any)
+	switch v := fields["metadata"].(type) {
+	case nil:
+	case map[string]any:
+		metadata = v
+	case string:
+		// CSV cells hold metadata as a JSON string.
+		if err := json.Unmarshal([]byte(v), &metadata); err != nil {
+			return nil, fmt.Errorf("parsing metadata: %w", err)
+		}
+	default:
+		return nil, errors.New("metadata must be a JSON object")
+	}
+	for name, v := range fields {
+		if name == "-" || slices.Contains(userImportFields, name) {
+			continue
+		}
+		metadata[name] = v
+	}
+	if len(metadata) > 0 {
+		user.Metadata = metadata
+	}
+	return user, nil
+}
+
+// existingUserIDs returns the IDs of every user in the project.
+func existingUserIDs(ctx context.Context, c *client.Client) (map[string]bool, error) {
+	users, err := pagination.NewPageIterator(1, exportPageSize, func(ctx context.Context, pageNumber, pageSize int) ([]*zep.User, error) {
+		users, err := c.User.ListOrdered(ctx, &zep.UserListOrderedRequest{
+			PageNumber: zep.Int(pageNumber),
+			PageSize:   zep.Int(pageSize),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing users: %w", err)
+		}
+		return users.Users, nil
+	}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool, len(users))
+	for _, u := range users {
+		ids[stringValue(u.UserID)] = true
+	}
+	return ids, nil
+}
+
+// userImporter imports rows concurrently and records their outcomes.
+type userImporter struct {
+	c        *client.Client
+	conflict string
+	existing map[string]bool
+	progress *output.Progress
+
+	mu      sync.Mutex
+	results *json.Encoder
+	counts  map[string]int
+	err     error // first error writing results
+}
+
+// run imports rows with a pool of workers. A user ID that appears on several
+// rows is imported from its first row; the later rows are skipped.
+func (im *userImporter) run(ctx context.Context, rows []userRow, workers int) {
+	jobs := make(chan userRow)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range jobs {
+				im.record(im.importRow(ctx, row))
+			}
+		}()
+	}
+
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		if row.err == nil {
+			if seen[row.user.UserID] {
+				im.record(userImportResult{Line: row.line, UserID: row.user.UserID, Status: rowSkipped})
+				continue
+			}
+			seen[row.user.UserID] = true
+		}
+		jobs <- row
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func (im *userImporter) importRow(ctx context.Context, row userRow) userImportResult {
+	result := userImportResult{Line: row.line}
+	if row.err != nil {
+		result.Status, result.Error = rowFailed, row.err.Error()
+		return result
+	}
+
+	u := row.user
+	result.UserID = u.UserID
+	if !im.existing[u.UserID] {
+		if _, err := im.c.User.Add(ctx, u); err != nil {
+			result.Status, result.Error = rowFailed, fmt.Sprintf("creating user: %v", err)
+			return result
+		}
+		result.Status = rowCreated
+		return result
+	}
+
+	switch im.conflict {
+	case conflictOverwrite:
+		_, err := im.c.User.Update(ctx, u.UserID, &zep.UpdateUserRequest{
+			Email:     u.Email,
+			FirstName: u.FirstName,
+			LastName:  u.LastName,
+			Metadata:  u.Metadata,
+		})
+		if err != nil {
+			result.Status, result.Error = rowFailed, fmt.Sprintf("updating user: %v", err)
+			return result
+		}
+		result.Status = rowUpdated
+	case conflictFail:
+		result.Status, result.Error = rowFailed, "user already exists"
+	default:
+		result.Status = rowSkipped
+	}
+	return result
+}
+
+func (im *userImporter) record(result userImportResult) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.counts[result.Status]++
+	im.progress.Add(result.Status, 1)
+	if err := im.results.Encode(result); err != nil && im.err == nil {
+		im.err = err
+	}
+}
+
+func init() {
+	userCmd.AddCommand(userImportCmd)
+
+	userImportCmd.Flags().String("file", "", "CSV or JSONL file of users")
+	userImportCmd.Flags().String("format", "", "Input format: csv or jsonl (default from the file extension)")
+	userImportCmd.Flags().StringToString("map", nil, "Map columns to user fields, e.g. id=user_id,mail=email (- ignores a column)")
+	userImportCmd.Flags().Int("concurrency", 8, "Number of users to import in parallel")
+	userImportCmd.Flags().String("conflict", conflictSkip, "How to handle users that already exist: skip, overwrite or fail")
+	userImportCmd.Flags().String("results", "", "Path of the JSONL results file (default <file>.results.jsonl)")
+}
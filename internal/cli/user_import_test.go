@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readImportResults returns the status of every line in a results file.
+func readImportResults(t *testing.T, path string) map[int]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	statuses := make(map[int]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r userImportResult
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("parsing result %q: %v", scanner.Text(), err)
+		}
+		statuses[r.Line] = r.Status
+	}
+	return statuses
+}
+
+func TestUserImport(t *testing.T) {
+	server := newSandbox(t)
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "users.csv")
+	csvData := "id,email,first_name,metadata,plan,notes\n" +
+		"alice,alice@example.com,Alice,\"{\"\"seats\"\": 3}\",pro,skip me\n" +
+		"bob,,Bob,,,\n" +
+		",nobody@example.com,,,,\n" +
+		"carol,,,{not json},,\n" +
+		"bob,,Robert,,,\n"
+	if err := os.WriteFile(csvPath, []byte(csvData), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, server, "user", "import", "--file", csvPath, "--map", "id=user_id,notes=-", "--concurrency", "2", "-o", "json")
+	if ExitCode(err) != ExitGeneral {
+		t.Fatalf("exit code %d, want %d (err: %v)", ExitCode(err), ExitGeneral, err)
+	}
+	var summary userImportSummary
+	if err := json.Unmarshal([]byte(out), &summary); err != nil {
+		t.Fatalf("parsing summary %q: %v", out, err)
+	}
+	if summary.Created != 2 || summary.Failed != 2 || summary.Skipped != 1 {
+		t.Errorf("summary = %+v, want 2 created, 2 failed and the repeated bob skipped", summary)
+	}
+	want := map[int]string{2: rowCreated, 3: rowCreated, 4: rowFailed, 5: rowFailed, 6: rowSkipped}
+	if got := readImportResults(t, csvPath+".results.jsonl"); !maps.Equal(got, want) {
+		t.Errorf("results = %v, want %v", got, want)
+	}
+
+	out, err = runCLI(t, server, "user", "get", "alice", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var alice struct {
+		Email    string         `json:"email"`
+		Metadata map[string]any `json:"metadata"`
+	}
+	if err := json.Unmarshal([]byte(out), &alice); err != nil {
+		t.Fatal(err)
+	}
+	if alice.Email != "alice@example.com" || alice.Metadata["plan"] != "pro" || alice.Metadata["seats"] != float64(3) || alice.Metadata["notes"] != nil {
+		t.Errorf("alice = %+v", alice)
+	}
+
+	// Re-running skips the users that exist and updates them on overwrite.
+	jsonlPath := filepath.Join(dir, "users.jsonl")
+	jsonlData := `{"user_id": "alice", "last_name": "Smith"}` + "\n\n" + `{"user_id": "dave"}` + "\n"
+	if err := os.WriteFile(jsonlPath, []byte(jsonlData), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	results := filepath.Join(dir, "results.jsonl")
+	if _, err := runCLI(t, server, "user", "import", "--file", jsonlPath, "--results", results); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := readImportResults(t, results), map[int]string{1: rowSkipped, 3: rowCreated}; !maps.Equal(got, want) {
+		t.Errorf("results = %v, want %v", got, want)
+	}
+	if _, err := runCLI(t, server, "user", "import", "--file", jsonlPath, "--results", results, "--conflict", "overwrite"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := readImportResults(t, results), map[int]string{1: rowUpdated, 3: rowUpdated}; !maps.Equal(got, want) {
+		t.Errorf("results = %v, want %v", got, want)
+	}
+	out, err = runCLI(t, server, "user", "get", "alice", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `"last_name": "Smith"`) || !strings.Contains(out, `"email": "alice@example.com"`) {
+		t.Errorf("alice was not updated:\n%s", out)
+	}
+}
+
+func TestUserImportInvalidArgs(t *testing.T) {
+	server := newSandbox(t)
+	dir := t.TempDir()
+	noID := filepath.Join(dir, "users.csv")
+	if err := os.WriteFile(noID, []byte("email\na@example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := [][]string{
+		{"user", "import"},
+		{"user", "import", "--file", noID},
+		{"user", "import", "--file", noID, "--format", "xml"},
+		{"user", "import", "--file", noID, "--conflict", "replace"},
+		{"user", "import", "--file", noID, "--concurrency", "0"},
+		{"user", "import", "--file", noID, "--map", "email=mail"},
+	}
+	for _, args := range tests {
+		if _, err := runCLI(t, server, args...); ExitCode(err) != ExitInvalidArgs {
+			t.Errorf("zepctl %s: exit code %d, want %d (err: %v)", strings.Join(args, " "), ExitCode(err), ExitInvalidArgs, err)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// defaultWatchInterval is how often --watch polls unless --watch-interval is
+// given.
+const defaultWatchInterval = 2 * time.Second
+
+// addWatchFlags registers the --watch and --watch-interval flags on a list
+// command.
+func addWatchFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("watch", "w", false, "After listing, keep polling and print records that are new or changed")
+	cmd.Flags().Duration("watch-interval", defaultWatchInterval, "How often to poll in watch mode")
+}
+
+// wantsWatch reports whether a list command should watch for changes, and
+// how often to poll.
+func wantsWatch(cmd *cobra.Command) (bool, time.Duration, error) {
+	watch, _ := cmd.Flags().GetBool("watch")
+	interval, _ := cmd.Flags().GetDuration("watch-interval")
+	if watch && interval <= 0 {
+		return false, 0, invalidArgsf("--watch-interval must be positive, got %s", interval)
+	}
+	return watch, interval, nil
+}
+
+// watchList prints every record returned by fetch, then polls every interval
+// and prints only the records that are new or changed since the previous
+// poll, like kubectl get --watch. It returns when interrupted with Ctrl-C.
+// Table output writes the header once; JSON and YAML output is a single list
+// that is terminated on interrupt.
+func watchList[T any](ctx context.Context, interval time.Duration, cols output.Columns[T], fetch func(context.Context) ([]T, error), key func(T) string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	p, err := newWatchPrinter(cols)
+	if err != nil {
+		return err
+	}
+	w := newWatcher(key)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		items, err := fetch(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return p.close()
+		case err != nil && isTransient(err):
+			output.Warn("%v; retrying in %s", err, interval)
+		case err != nil:
+			_ = p.close()
+			return err
+		default:
+			changed, err := w.changes(items)
+			if err != nil {
+				return err
+			}
+			if err := p.print(changed); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return p.close()
+		case <-ticker.C:
+		}
+	}
+}
+
+// isTransient reports whether a failed poll is worth retrying on the next
+// tick rather than ending the watch.
+func isTransient(err error) bool {
+	switch classifyError(err).ExitCode {
+	case ExitServer, ExitRateLimit, ExitTimeout:
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// watcher remembers the records seen so far to detect new and changed ones.
+type watcher[T any] struct {
+	key  func(T) string
+	seen map[string]string
+}
+
+func newWatcher[T any](key func(T) string) *watcher[T] {
+	return &watcher[T]{key: key, seen: make(map[string]string)}
+}
+
+// changes returns the items that were not seen before or whose JSON encoding
+// differs from when they were last seen.
+func (w *watcher[T]) changes(items []T) ([]T, error) {
+	var changed []T
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("encoding record: %w", err)
+		}
+		k := w.key(item)
+		if prev, ok := w.seen[k]; ok && prev == string(data) {
+			continue
+		}
+		w.seen[k] = string(data)
+		changed = append(changed, item)
+	}
+	return changed, nil
+}
+
+// watchPrinter writes batches of records as they arrive. Templates are
+// rendered once per record, since there is no end of the list to wait for.
+type watchPrinter[T any] struct {
+	cols output.Columns[T]
+	tbl  *output.ColumnTable[T]
+	lw   *output.ListWriter
+}
+
+func newWatchPrinter[T any](cols output.Columns[T]) (*watchPrinter[T], error) {
+	p := &watchPrinter[T]{cols: cols}
+	switch {
+	case output.IsTabular():
+		tbl, err := cols.NewColumnTable()
+		if err != nil {
+			return nil, err
+		}
+		p.tbl = tbl
+		if err := tbl.Flush(); err != nil {
+			return nil, err
+		}
+	case output.IsTemplate():
+	default:
+		p.lw = output.NewListWriter(os.Stdout)
+	}
+	return p, nil
+}
+
+func (p *watchPrinter[T]) print(items []T) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if err := p.cols.Sort(items); err != nil {
+		return err
+	}
+	switch {
+	case p.tbl != nil:
+		p.tbl.Write(items...)
+		return p.tbl.Flush()
+	case p.lw != nil:
+		for _, item := range items {
+			if err := p.lw.Write(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		for _, item := range items {
+			if err := output.Print(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (p *watchPrinter[T]) close() error {
+	if p.lw != nil {
+		return p.lw.Close()
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/spf13/viper"
+)
+
+type watchRecord struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+var watchRecordColumns = output.Columns[watchRecord]{
+	{Name: "ID", Value: func(r watchRecord) string { return r.ID }},
+	{Name: "STATUS", Value: func(r watchRecord) string { return r.Status }},
+}
+
+// captureStdout returns what fn writes to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	fn()
+
+	os.Stdout = stdout
+	_ = w.Close()
+	return <-done
+}
+
+func TestWatchList(t *testing.T) {
+	polls := [][]watchRecord{
+		{{ID: "a", Status: "pending"}},
+		{{ID: "a", Status: "pending"}, {ID: "b", Status: "pending"}},
+		{{ID: "a", Status: "done"}, {ID: "b", Status: "pending"}},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "table",
+			want:   "ID  STATUS\na  pending\nb  pending\na  done\n",
+		},
+		{
+			format: "ndjson",
+			want: `{"id":"a","status":"pending"}
+{"id":"b","status":"pending"}
+{"id":"a","status":"done"}
+`,
+		},
+		{
+			format: "json",
+			want: `[
+  {
+    "id": "a",
+    "status": "pending"
+  },
+  {
+    "id": "b",
+    "status": "pending"
+  },
+  {
+    "id": "a",
+    "status": "done"
+  }
+]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			viper.Set("output", tt.format)
+			t.Cleanup(viper.Reset)
+
+			// Stop watching once every poll has been served, as Ctrl-C would.
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			n := 0
+			fetch := func(context.Context) ([]watchRecord, error) {
+				if n == len(polls) {
+					cancel()
+					return nil, context.Canceled
+				}
+				n++
+				return polls[n-1], nil
+			}
+
+			var err error
+			got := captureStdout(t, func() {
+				err = watchList(ctx, time.Millisecond, watchRecordColumns, fetch, func(r watchRecord) string { return r.ID })
+			})
+			if err != nil {
+				t.Fatalf("watchList: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,31 +1,116 @@
 package client
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"sync"
 
 	zepclient "github.com/getzep/zep-go/v3/client"
 	"github.com/getzep/zep-go/v3/option"
 	"github.com/getzep/zepctl/internal/config"
+	"github.com/getzep/zepctl/internal/output"
+	"github.com/getzep/zepctl/internal/sandbox"
 )
 
+// ErrNoAPIKey is returned by New when no API key is configured.
+var ErrNoAPIKey = errors.New("no API key configured; set ZEP_API_KEY or configure a profile")
+
 // Client is an alias for the Zep client.
 type Client = zepclient.Client
 
+// sandboxAPIKey is sent to the sandbox emulator, which does not check it.
+const sandboxAPIKey = "sandbox"
+
+var (
+	sandboxServer *sandbox.Server
+	sandboxOnce   sync.Once
+	sandboxErr    error
+
+	traceHAR   *harRecorder
+	traceHARMu sync.Mutex
+)
+
+// startSandbox starts the sandbox emulator on first use. The emulator lives
+// for the rest of the process so every client shares the same state.
+func startSandbox() (*sandbox.Server, error) {
+	sandboxOnce.Do(func() {
+		sandboxServer, sandboxErr = sandbox.Start(config.GetSandboxStatePath())
+		if sandboxErr != nil {
+			sandboxErr = fmt.Errorf("starting sandbox: %w", sandboxErr)
+		}
+	})
+	return sandboxServer, sandboxErr
+}
+
 // New creates a new Zep client using the current configuration.
 func New() (*Client, error) {
+	if config.IsSandbox() {
+		server, err := startSandbox()
+		if err != nil {
+			return nil, err
+		}
+		return newClient(sandboxAPIKey, server.URL()), nil
+	}
+
 	apiKey := config.GetAPIKey()
 	if apiKey == "" {
-		return nil, fmt.Errorf("no API key configured; set ZEP_API_KEY or configure a profile")
+		return nil, ErrNoAPIKey
+	}
+
+	// Only set base URL if explicitly configured; otherwise use SDK default
+	return newClient(apiKey, config.GetAPIURL()), nil
+}
+
+// harRecorderFor returns the recorder shared by every client when a HAR trace
+// file is configured, or nil.
+func harRecorderFor() *harRecorder {
+	path := config.GetTraceHARPath()
+	if path == "" {
+		return nil
+	}
+
+	traceHARMu.Lock()
+	defer traceHARMu.Unlock()
+	if traceHAR == nil || traceHAR.path != path {
+		traceHAR = &harRecorder{path: path}
+	}
+	return traceHAR
+}
+
+// FlushTrace writes the HTTP exchanges recorded since the last flush to the
+// file set with --trace-har, recording version as the creator version. It does
+// nothing if no HAR file is configured.
+func FlushTrace(version string) error {
+	traceHARMu.Lock()
+	har := traceHAR
+	traceHAR = nil
+	traceHARMu.Unlock()
+
+	if har == nil {
+		return nil
+	}
+	return har.write(version)
+}
+
+func newClient(apiKey, apiURL string) *Client {
+	// Retries are handled by our own transport so they can honor Retry-After
+	// and the configured policy; disable the SDK's built-in retrier. Tracing
+	// sits below the retrier so every attempt is logged.
+	trace := newTraceTransport(http.DefaultTransport, output.VerboseLevel(), harRecorderFor())
+	httpClient := &http.Client{
+		Transport: newRetryTransport(trace, config.GetRetryConfig()),
 	}
 
 	opts := []option.RequestOption{
 		option.WithAPIKey(apiKey),
+		option.WithHTTPClient(httpClient),
+		option.WithMaxAttempts(1),
 	}
 
-	// Only set base URL if explicitly configured; otherwise use SDK default
-	if apiURL := config.GetAPIURL(); apiURL != "" {
+	if apiURL != "" {
 		opts = append(opts, option.WithBaseURL(apiURL))
 	}
 
-	return zepclient.NewClient(opts...), nil
+	return zepclient.NewClient(opts...)
 }
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getzep/zepctl/internal/config"
+	"github.com/getzep/zepctl/internal/output"
+)
+
+// maxRetryDelay caps both computed backoff and server-provided Retry-After delays.
+const maxRetryDelay = 30 * time.Second
+
+// safeWritePaths lists POST endpoints that only read data and can be retried
+// like idempotent requests.
+var safeWritePaths = []string{
+	"/graph/search",
+	"/graph/node/user/",
+	"/graph/node/graph/",
+	"/graph/edge/user/",
+	"/graph/edge/graph/",
+}
+
+// retryTransport retries failed requests with exponential backoff.
+// Rate-limited (429) requests are always retried because the server rejected
+// them before doing any work. Server errors and network failures are only
+// retried for idempotent methods and the read-only POST endpoints in safeWritePaths.
+type retryTransport struct {
+	next        http.RoundTripper
+	maxAttempts int
+	baseDelay   time.Duration
+	jitter      float64
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryTransport(next http.RoundTripper, rc config.RetryConfig) *retryTransport {
+	return &retryTransport{
+		next:        next,
+		maxAttempts: max(rc.MaxAttempts, 1),
+		baseDelay:   rc.BaseDelay,
+		jitter:      rc.Jitter,
+		sleep:       sleepContext,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.maxAttempts <= 1 {
+		return t.next.RoundTrip(req)
+	}
+
+	// Buffer the body so it can be replayed on each attempt.
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req.Clone(req.Context())
+		if body != nil {
+			attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+			attemptReq.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if attempt >= t.maxAttempts || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			if d, ok := retryAfter(resp); ok {
+				delay = d
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		output.Verbose("Retrying %s %s in %s (attempt %d/%d): %s",
+			req.Method, req.URL.Path, delay.Round(time.Millisecond), attempt+1, t.maxAttempts, reason)
+
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *retryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return isRetryableRequest(req)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented:
+		return isRetryableRequest(req)
+	default:
+		return false
+	}
+}
+
+// backoff returns the exponential delay before the given retry attempt.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := float64(t.baseDelay) * math.Pow(2, float64(attempt-1))
+	if t.jitter > 0 {
+		delay += delay * t.jitter * (2*rand.Float64() - 1) //nolint:gosec // jitter does not need a secure source
+	}
+	return min(time.Duration(delay), maxRetryDelay)
+}
+
+// isRetryableRequest reports whether a request can be safely sent again.
+func isRetryableRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		for _, p := range safeWritePaths {
+			if strings.Contains(req.URL.Path, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header, which may be a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return min(time.Duration(secs)*time.Second, maxRetryDelay), true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return min(max(time.Until(at), 0), maxRetryDelay), true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
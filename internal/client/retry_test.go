@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getzep/zepctl/internal/config"
+)
+
+func newTestTransport(maxAttempts int, delays *[]time.Duration) *retryTransport {
+	t := newRetryTransport(http.DefaultTransport, config.RetryConfig{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   10 * time.Millisecond,
+	})
+	t.sleep = func(_ context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+	return t
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		statuses     []int
+		retryAfter   string
+		wantStatus   int
+		wantAttempts int32
+	}{
+		{
+			name:         "retries idempotent request on server error",
+			method:       http.MethodGet,
+			path:         "/users/u1",
+			statuses:     []int{503, 502, 200},
+			wantStatus:   200,
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after max attempts",
+			method:       http.MethodGet,
+			path:         "/users/u1",
+			statuses:     []int{503, 503, 503, 503},
+			wantStatus:   503,
+			wantAttempts: 3,
+		},
+		{
+			name:         "does not retry unsafe POST on server error",
+			method:       http.MethodPost,
+			path:         "/users",
+			statuses:     []int{503, 200},
+			wantStatus:   503,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retries safe POST on server error",
+			method:       http.MethodPost,
+			path:         "/graph/search",
+			statuses:     []int{500, 200},
+			wantStatus:   200,
+			wantAttempts: 2,
+		},
+		{
+			name:         "retries any method on rate limit",
+			method:       http.MethodPost,
+			path:         "/users",
+			statuses:     []int{429, 200},
+			retryAfter:   "2",
+			wantStatus:   200,
+			wantAttempts: 2,
+		},
+		{
+			name:         "does not retry client errors",
+			method:       http.MethodGet,
+			path:         "/users/missing",
+			statuses:     []int{404, 200},
+			wantStatus:   404,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := attempts.Add(1)
+				body, _ := io.ReadAll(r.Body)
+				if r.Method == http.MethodPost && string(body) != `{"a":1}` {
+					t.Errorf("attempt %d: body = %q, want replayed body", n, body)
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.statuses[n-1])
+			}))
+			defer srv.Close()
+
+			var delays []time.Duration
+			hc := &http.Client{Transport: newTestTransport(3, &delays)}
+
+			var body io.Reader
+			if tt.method == http.MethodPost {
+				body = strings.NewReader(`{"a":1}`)
+			}
+			req, err := http.NewRequestWithContext(context.Background(), tt.method, srv.URL+tt.path, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := hc.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			if tt.retryAfter != "" && (len(delays) == 0 || delays[0] != 2*time.Second) {
+				t.Errorf("delays = %v, want Retry-After of 2s", delays)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	rt := &retryTransport{baseDelay: 100 * time.Millisecond}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+	for i, w := range want {
+		if got := rt.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+	if got := rt.backoff(20); got != maxRetryDelay {
+		t.Errorf("backoff(20) = %s, want cap of %s", got, maxRetryDelay)
+	}
+}
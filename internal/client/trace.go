@@ -0,0 +1,376 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxTraceBody caps how much of a request or response body is logged at -vv
+// or recorded in a HAR file.
+const maxTraceBody = 64 << 10
+
+const redacted = "REDACTED"
+
+// sensitiveHeaders are never logged or recorded.
+var sensitiveHeaders = []string{"Authorization", "X-Api-Key", "Api-Key", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// sensitiveKeys are JSON fields and query parameters whose values are redacted.
+var sensitiveKeys = []string{"api_key", "apikey", "api-key", "token", "access_token", "secret", "password"}
+
+// requestIDHeaders are checked in order for the server's request ID.
+var requestIDHeaders = []string{"X-Request-Id", "X-Zep-Request-Id", "Request-Id"}
+
+// traceTransport logs each HTTP exchange and optionally records it for a HAR
+// file. At level 1 it logs one line per request with the method, URL, status,
+// latency and request ID; at level 2 it also logs headers and bodies.
+// Credentials are redacted everywhere.
+type traceTransport struct {
+	next  http.RoundTripper
+	level int
+	har   *harRecorder
+	logf  func(format string, args ...any)
+	now   func() time.Time
+}
+
+func newTraceTransport(next http.RoundTripper, level int, har *harRecorder) *traceTransport {
+	return &traceTransport{
+		next:  next,
+		level: level,
+		har:   har,
+		logf: func(format string, args ...any) {
+			fmt.Fprintf(os.Stderr, format+"\n", args...)
+		},
+		now: time.Now,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.level <= 0 && t.har == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	if t.level >= 2 {
+		t.logf("> %s %s", req.Method, redactURL(req.URL))
+		t.logHeaders(">", req.Header)
+		if len(reqBody) > 0 {
+			t.logf("> %s", redactBody(reqBody))
+		}
+	}
+
+	started := t.now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := t.now().Sub(started)
+
+	var respBody []byte
+	if err == nil && resp.Body != nil {
+		respBody, err = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		if err != nil {
+			err = fmt.Errorf("reading response body: %w", err)
+		}
+	}
+
+	if t.level >= 1 {
+		if err != nil {
+			t.logf("%s %s failed after %s: %v", req.Method, redactURL(req.URL), elapsed.Round(time.Millisecond), err)
+		} else {
+			line := fmt.Sprintf("%s %s %d %s", req.Method, redactURL(req.URL), resp.StatusCode, elapsed.Round(time.Millisecond))
+			if id := requestID(resp.Header); id != "" {
+				line += " request-id=" + id
+			}
+			t.logf("%s", line)
+		}
+	}
+	if t.level >= 2 && err == nil {
+		t.logHeaders("<", resp.Header)
+		if len(respBody) > 0 {
+			t.logf("< %s", redactBody(respBody))
+		}
+	}
+
+	if t.har != nil {
+		t.har.add(newHAREntry(req, reqBody, resp, respBody, err, started, elapsed))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (t *traceTransport) logHeaders(prefix string, h http.Header) {
+	for _, name := range slices.Sorted(maps.Keys(h)) {
+		for _, v := range redactHeader(name, h[name]) {
+			t.logf("%s %s: %s", prefix, name, v)
+		}
+	}
+}
+
+func requestID(h http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := h.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func isSensitiveHeader(name string) bool {
+	return slices.ContainsFunc(sensitiveHeaders, func(s string) bool { return strings.EqualFold(s, name) })
+}
+
+func isSensitiveKey(key string) bool {
+	return slices.Contains(sensitiveKeys, strings.ToLower(key))
+}
+
+func redactHeader(name string, values []string) []string {
+	if !isSensitiveHeader(name) {
+		return values
+	}
+	out := make([]string, len(values))
+	for i := range values {
+		out[i] = redacted
+	}
+	return out
+}
+
+// redactURL returns u as a string with sensitive query parameters redacted.
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	q := u.Query()
+	for key := range q {
+		if isSensitiveKey(key) {
+			q.Set(key, redacted)
+		}
+	}
+	clean := *u
+	clean.RawQuery = q.Encode()
+	return clean.String()
+}
+
+// redactBody returns a body for logging, with sensitive JSON fields redacted
+// and anything past maxTraceBody cut off.
+func redactBody(body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		if data, err := json.Marshal(redactValue(v)); err == nil {
+			body = data
+		}
+	}
+	if len(body) > maxTraceBody {
+		return string(body[:maxTraceBody]) + fmt.Sprintf("... (%d bytes)", len(body))
+	}
+	return string(body)
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if isSensitiveKey(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(value)
+			}
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
+
+// HAR 1.2 document types. See http://www.softwareishard.com/blog/har-12-spec/.
+type (
+	harLog struct {
+		Log harContent `json:"log"`
+	}
+	harContent struct {
+		Version string      `json:"version"`
+		Creator harCreator  `json:"creator"`
+		Entries []*harEntry `json:"entries"`
+	}
+	harCreator struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	harEntry struct {
+		StartedDateTime string      `json:"startedDateTime"`
+		Time            float64     `json:"time"`
+		Request         harRequest  `json:"request"`
+		Response        harResponse `json:"response"`
+		Cache           struct{}    `json:"cache"`
+		Timings         harTimings  `json:"timings"`
+		Error           string      `json:"_error,omitempty"`
+	}
+	harRequest struct {
+		Method      string       `json:"method"`
+		URL         string       `json:"url"`
+		HTTPVersion string       `json:"httpVersion"`
+		Cookies     []harNV      `json:"cookies"`
+		Headers     []harNV      `json:"headers"`
+		QueryString []harNV      `json:"queryString"`
+		PostData    *harPostData `json:"postData,omitempty"`
+		HeadersSize int          `json:"headersSize"`
+		BodySize    int          `json:"bodySize"`
+	}
+	harResponse struct {
+		Status      int     `json:"status"`
+		StatusText  string  `json:"statusText"`
+		HTTPVersion string  `json:"httpVersion"`
+		Cookies     []harNV `json:"cookies"`
+		Headers     []harNV `json:"headers"`
+		Content     harBody `json:"content"`
+		RedirectURL string  `json:"redirectURL"`
+		HeadersSize int     `json:"headersSize"`
+		BodySize    int     `json:"bodySize"`
+	}
+	harNV struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	harPostData struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+	}
+	harBody struct {
+		Size     int    `json:"size"`
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text,omitempty"`
+	}
+	harTimings struct {
+		Send    float64 `json:"send"`
+		Wait    float64 `json:"wait"`
+		Receive float64 `json:"receive"`
+	}
+)
+
+func harHeaders(h http.Header) []harNV {
+	nvs := []harNV{}
+	for name, values := range h {
+		for _, v := range redactHeader(name, values) {
+			nvs = append(nvs, harNV{Name: name, Value: v})
+		}
+	}
+	slices.SortFunc(nvs, func(a, b harNV) int { return strings.Compare(a.Name, b.Name) })
+	return nvs
+}
+
+func newHAREntry(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, err error, started time.Time, elapsed time.Duration) *harEntry {
+	ms := float64(elapsed) / float64(time.Millisecond)
+	entry := &harEntry{
+		StartedDateTime: started.UTC().Format(time.RFC3339Nano),
+		Time:            ms,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         redactURL(req.URL),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNV{},
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNV{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Cookies:     []harNV{},
+			Headers:     []harNV{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: harTimings{Wait: ms},
+	}
+
+	for key, values := range req.URL.Query() {
+		for _, v := range values {
+			if isSensitiveKey(key) {
+				v = redacted
+			}
+			entry.Request.QueryString = append(entry.Request.QueryString, harNV{Name: key, Value: v})
+		}
+	}
+	if len(reqBody) > 0 {
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     redactBody(reqBody),
+		}
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Response.Status = resp.StatusCode
+	entry.Response.StatusText = http.StatusText(resp.StatusCode)
+	entry.Response.HTTPVersion = resp.Proto
+	entry.Response.Headers = harHeaders(resp.Header)
+	entry.Response.BodySize = len(respBody)
+	entry.Response.Content = harBody{
+		Size:     len(respBody),
+		MimeType: resp.Header.Get("Content-Type"),
+		Text:     redactBody(respBody),
+	}
+	return entry
+}
+
+// harRecorder collects HTTP exchanges and writes them to a HAR file.
+type harRecorder struct {
+	path    string
+	mu      sync.Mutex
+	entries []*harEntry
+}
+
+func (r *harRecorder) add(entry *harEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+// write saves the recorded exchanges to the HAR file.
+func (r *harRecorder) write(version string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doc := harLog{Log: harContent{
+		Version: "1.2",
+		Creator: harCreator{Name: "zepctl", Version: version},
+		Entries: r.entries,
+	}}
+	if doc.Log.Entries == nil {
+		doc.Log.Entries = []*harEntry{}
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding HAR: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0o600); err != nil {
+		return fmt.Errorf("writing HAR file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTraceTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Request-Id", "req-123")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"echo":%s,"api_key":"server-secret"}`, body)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		level     int
+		want      []string
+		wantLines int
+	}{
+		{name: "disabled", level: 0, wantLines: 0},
+		{
+			name:      "summary",
+			level:     1,
+			want:      []string{"POST " + server.URL + "/users?api_key=REDACTED&page=1 200", "request-id=req-123"},
+			wantLines: 1,
+		},
+		{
+			name:  "headers and bodies",
+			level: 2,
+			want: []string{
+				"> Authorization: REDACTED",
+				"> X-Custom: visible",
+				`> {"password":"REDACTED","user_id":"alice"}`,
+				"< X-Request-Id: req-123",
+				`"api_key":"REDACTED"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []string
+			tr := newTraceTransport(http.DefaultTransport, tt.level, nil)
+			tr.logf = func(format string, args ...any) {
+				lines = append(lines, fmt.Sprintf(format, args...))
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, server.URL+"/users?page=1&api_key=secret-key",
+				strings.NewReader(`{"user_id":"alice","password":"hunter2"}`))
+			req.Header.Set("Authorization", "Api-Key secret-key")
+			req.Header.Set("X-Custom", "visible")
+
+			resp, err := tr.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if !strings.Contains(string(body), `"password":"hunter2"`) {
+				t.Errorf("response body altered: %s", body)
+			}
+
+			log := strings.Join(lines, "\n")
+			for _, want := range tt.want {
+				if !strings.Contains(log, want) {
+					t.Errorf("log missing %q:\n%s", want, log)
+				}
+			}
+			if strings.Contains(log, "secret") || strings.Contains(log, "hunter2") {
+				t.Errorf("log leaks a secret:\n%s", log)
+			}
+			if tt.wantLines > 0 || tt.level == 0 {
+				if len(lines) != tt.wantLines {
+					t.Errorf("got %d log lines, want %d:\n%s", len(lines), tt.wantLines, log)
+				}
+			}
+		})
+	}
+}
+
+func TestTraceHAR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"message":"not found"}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "trace.har")
+	har := &harRecorder{path: path}
+	tr := newTraceTransport(http.DefaultTransport, 0, har)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/users/bob", nil)
+	req.Header.Set("Authorization", "Api-Key secret-key")
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if err := har.write("1.2.3"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-key") {
+		t.Errorf("HAR file leaks the API key:\n%s", data)
+	}
+
+	var doc harLog
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decoding HAR: %v", err)
+	}
+	if doc.Log.Version != "1.2" || doc.Log.Creator.Version != "1.2.3" {
+		t.Errorf("unexpected log header: %+v", doc.Log)
+	}
+	if len(doc.Log.Entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(doc.Log.Entries))
+	}
+	entry := doc.Log.Entries[0]
+	if entry.Request.Method != http.MethodGet || entry.Response.Status != http.StatusNotFound {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.Response.Content.Text != `{"message":"not found"}` {
+		t.Errorf("response content = %q", entry.Response.Content.Text)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/getzep/zepctl/internal/keyring"
 	"github.com/spf13/viper"
@@ -20,10 +22,31 @@ var (
 // Profile represents a named configuration profile.
 // API keys are stored in the system keychain, not in this config file.
 type Profile struct {
-	Name   string `yaml:"name"`
-	APIURL string `yaml:"api-url,omitempty"`
+	Name         string       `yaml:"name"`
+	Type         string       `yaml:"type,omitempty"`
+	APIURL       string       `yaml:"api-url,omitempty"`
+	SandboxState string       `yaml:"sandbox-state,omitempty"`
+	Retry        *RetryConfig `yaml:"retry,omitempty"`
 }
 
+// ProfileTypeSandbox marks a profile that runs commands against the local
+// sandbox emulator instead of a Zep project.
+const ProfileTypeSandbox = "sandbox"
+
+// RetryConfig configures automatic retries of failed API requests.
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max-attempts,omitempty"`
+	BaseDelay   time.Duration `yaml:"base-delay,omitempty"`
+	Jitter      float64       `yaml:"jitter,omitempty"`
+}
+
+// Default retry settings.
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 500 * time.Millisecond
+	DefaultRetryJitter      = 0.2
+)
+
 // Config represents the zepctl configuration.
 type Config struct {
 	CurrentProfile string    `yaml:"current-profile"`
@@ -137,6 +160,21 @@ func (c *Config) GetCurrentProfile() *Profile {
 	return c.GetProfile(c.CurrentProfile)
 }
 
+// GetProfileName returns the name of the profile in use, checking flags, env,
+// and the config file. Returns empty string if no profile is in use.
+func GetProfileName() string {
+	// Flag/env takes precedence
+	if profile := viper.GetString("profile"); profile != "" {
+		return profile
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		return ""
+	}
+	return cfg.CurrentProfile
+}
+
 // GetAPIKey returns the API key to use, checking flags, env, and profile keychain.
 func GetAPIKey() string {
 	// Flag/env takes precedence
@@ -179,3 +217,98 @@ func GetAPIURL() string {
 
 	return ""
 }
+
+// GetRetryConfig returns the retry settings to use, checking flags, env, and profile.
+// Each setting is resolved independently and falls back to the package defaults.
+func GetRetryConfig() RetryConfig {
+	rc := RetryConfig{
+		MaxAttempts: DefaultRetryMaxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		Jitter:      DefaultRetryJitter,
+	}
+
+	// Profile settings override defaults
+	if cfg, err := Load(); err == nil {
+		if profile := cfg.GetCurrentProfile(); profile != nil && profile.Retry != nil {
+			if profile.Retry.MaxAttempts > 0 {
+				rc.MaxAttempts = profile.Retry.MaxAttempts
+			}
+			if profile.Retry.BaseDelay > 0 {
+				rc.BaseDelay = profile.Retry.BaseDelay
+			}
+			if profile.Retry.Jitter > 0 {
+				rc.Jitter = profile.Retry.Jitter
+			}
+		}
+	}
+
+	// Flag/env takes precedence
+	if viper.IsSet("retry") {
+		rc.MaxAttempts = viper.GetInt("retry")
+	}
+	if viper.IsSet("retry-base-delay") {
+		rc.BaseDelay = viper.GetDuration("retry-base-delay")
+	}
+	if viper.IsSet("retry-jitter") {
+		rc.Jitter = viper.GetFloat64("retry-jitter")
+	}
+
+	return rc
+}
+
+// IsSandbox reports whether commands should run against the local sandbox
+// emulator, either because --sandbox is set or the current profile is a
+// sandbox profile.
+func IsSandbox() bool {
+	// Flag/env takes precedence
+	if viper.GetBool("sandbox") {
+		return true
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		return false
+	}
+
+	profile := cfg.GetCurrentProfile()
+	return profile != nil && profile.Type == ProfileTypeSandbox
+}
+
+// GetTraceHARPath returns the file HTTP traces should be written to, or "" if
+// tracing to a HAR file is disabled.
+func GetTraceHARPath() string {
+	return expandHome(viper.GetString("trace-har"))
+}
+
+// GetSandboxStatePath returns the file the sandbox persists its state to,
+// checking flags, env, and profile. Returns empty string for in-memory state.
+func GetSandboxStatePath() string {
+	// Flag/env takes precedence
+	if path := viper.GetString("sandbox-state"); path != "" {
+		return path
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		return ""
+	}
+
+	if profile := cfg.GetCurrentProfile(); profile != nil {
+		return expandHome(profile.SandboxState)
+	}
+
+	return ""
+}
+
+// expandHome expands a leading "~/" in paths read from the config file.
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
@@ -0,0 +1,67 @@
+// Package explore implements the full-screen graph explorer behind
+// zepctl explore. It draws with ANSI escape sequences on a terminal in raw
+// mode, so it needs no UI library.
+package explore
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Run takes over the terminal and runs the explorer until the user quits.
+// The model should already be loaded, so errors such as a missing graph are
+// reported before the screen is cleared.
+func Run(ctx context.Context, m *Model, in, out *os.File) error {
+	fd := int(in.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("setting up terminal: %w", err)
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	// Switch to the alternate screen and hide the cursor, restoring both on
+	// exit so the shell's scrollback is left as it was.
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	buf := make([]byte, 256)
+	for !m.Done() {
+		if err := draw(out, m); err != nil {
+			return err
+		}
+		n, err := in.Read(buf)
+		if err != nil {
+			return fmt.Errorf("reading input: %w", err)
+		}
+		for _, k := range decodeKeys(buf[:n]) {
+			m.HandleKey(ctx, k)
+			if m.Done() {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// draw renders the model to fit the terminal, whose size is read on every
+// frame so resizing takes effect on the next key press.
+func draw(out *os.File, m *Model) error {
+	width, height, err := term.GetSize(int(out.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+
+	w := bufio.NewWriter(out)
+	w.WriteString("\x1b[H")
+	w.WriteString(strings.Join(m.View(width, height), "\x1b[K\r\n"))
+	w.WriteString("\x1b[K\x1b[J")
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("drawing screen: %w", err)
+	}
+	return nil
+}
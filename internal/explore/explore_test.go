@@ -0,0 +1,268 @@
+package explore
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/getzep/zep-go/v3"
+)
+
+// fakeSource is an in-memory graph. Nodes outside listed are only reachable
+// with Node, like nodes beyond the explorer's list limit.
+type fakeSource struct {
+	listed   []string
+	nodes    map[string]*zep.EntityNode
+	edges    []*zep.EntityEdge
+	episodes map[string][]*zep.Episode
+	deleted  []string
+}
+
+func newFakeSource() *fakeSource {
+	node := func(uuid, name string) *zep.EntityNode { return &zep.EntityNode{UUID: uuid, Name: name} }
+	return &fakeSource{
+		listed: []string{"n1", "n2"},
+		nodes: map[string]*zep.EntityNode{
+			"n1": node("n1", "Alice"),
+			"n2": node("n2", "Acme"),
+			"n3": node("n3", "Bob"),
+		},
+		edges: []*zep.EntityEdge{
+			{UUID: "e1", Fact: "Alice works at Acme", SourceNodeUUID: "n1", TargetNodeUUID: "n2"},
+			{UUID: "e2", Fact: "Bob knows Alice", SourceNodeUUID: "n3", TargetNodeUUID: "n1"},
+		},
+		episodes: map[string][]*zep.Episode{
+			"n1": {{UUID: "ep1", Content: "Alice joined Acme"}},
+		},
+	}
+}
+
+func (s *fakeSource) Nodes(ctx context.Context) ([]*zep.EntityNode, error) {
+	var nodes []*zep.EntityNode
+	for _, uuid := range s.listed {
+		nodes = append(nodes, s.nodes[uuid])
+	}
+	return nodes, nil
+}
+
+func (s *fakeSource) Search(ctx context.Context, query string) ([]*zep.EntityNode, error) {
+	var nodes []*zep.EntityNode
+	for _, uuid := range []string{"n1", "n2", "n3"} {
+		if n, ok := s.nodes[uuid]; ok && strings.Contains(strings.ToLower(n.Name), strings.ToLower(query)) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil
+}
+
+func (s *fakeSource) Node(ctx context.Context, uuid string) (*zep.EntityNode, error) {
+	n, ok := s.nodes[uuid]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return n, nil
+}
+
+func (s *fakeSource) NodeEdges(ctx context.Context, uuid string) ([]*zep.EntityEdge, error) {
+	var edges []*zep.EntityEdge
+	for _, e := range s.edges {
+		if e.SourceNodeUUID == uuid || e.TargetNodeUUID == uuid {
+			edges = append(edges, e)
+		}
+	}
+	return edges, nil
+}
+
+func (s *fakeSource) NodeEpisodes(ctx context.Context, uuid string) ([]*zep.Episode, error) {
+	return s.episodes[uuid], nil
+}
+
+func (s *fakeSource) DeleteNode(ctx context.Context, uuid string) error {
+	s.deleted = append(s.deleted, uuid)
+	return nil
+}
+
+func (s *fakeSource) DeleteEdge(ctx context.Context, uuid string) error {
+	s.deleted = append(s.deleted, uuid)
+	s.edges = slices.DeleteFunc(s.edges, func(e *zep.EntityEdge) bool { return e.UUID == uuid })
+	return nil
+}
+
+func (s *fakeSource) DeleteEpisode(ctx context.Context, uuid string) error {
+	s.deleted = append(s.deleted, uuid)
+	return nil
+}
+
+func loadModel(t *testing.T, src Source) *Model {
+	t.Helper()
+	m := New(src, `user "alice"`)
+	if err := m.Load(context.Background()); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return m
+}
+
+func press(m *Model, keys ...Key) {
+	for _, k := range keys {
+		m.HandleKey(context.Background(), k)
+	}
+}
+
+func selectedNode(m *Model) string {
+	n, ok := m.nodes.selected()
+	if !ok {
+		return ""
+	}
+	return n.Name
+}
+
+func TestNavigation(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []Key
+		wantNode string
+		wantPane pane
+		wantEdge string
+	}{
+		{"first node", nil, "Alice", nodesPane, "e1"},
+		{"move down", []Key{KeyDown}, "Acme", nodesPane, "e1"},
+		{"move past end", []Key{"j", "j", "j"}, "Acme", nodesPane, "e1"},
+		{"enter opens edges", []Key{KeyEnter, KeyDown}, "Alice", edgesPane, "e2"},
+		{"enter follows edge", []Key{KeyEnter, KeyEnter}, "Acme", nodesPane, "e1"},
+		{"source of unlisted node", []Key{KeyEnter, KeyDown, "s"}, "Bob", nodesPane, "e2"},
+		{"target", []Key{KeyEnter, KeyDown, "t"}, "Alice", nodesPane, "e1"},
+		{"back", []Key{KeyEnter, KeyDown, "s", "b"}, "Alice", nodesPane, "e1"},
+		{"back without history", []Key{"b"}, "Alice", nodesPane, "e1"},
+		{"tab cycles panes", []Key{KeyTab, KeyTab, KeyTab, KeyBacktab}, "Alice", episodesPane, "e1"},
+		{"search", []Key{"/", "b", "o", KeyEnter}, "Bob", nodesPane, "e2"},
+		{"cancel search", []Key{"/", "b", KeyEsc}, "Alice", nodesPane, "e1"},
+		{"clear search", []Key{"/", "b", "o", KeyEnter, "/", KeyBackspace, KeyBackspace, KeyEnter}, "Alice", nodesPane, "e1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := loadModel(t, newFakeSource())
+			press(m, tt.keys...)
+
+			if got := selectedNode(m); got != tt.wantNode {
+				t.Errorf("selected node = %q, want %q", got, tt.wantNode)
+			}
+			if m.focus != tt.wantPane {
+				t.Errorf("focus = %d, want %d", m.focus, tt.wantPane)
+			}
+			if e, _ := m.edges.selected(); e == nil || e.UUID != tt.wantEdge {
+				t.Errorf("selected edge = %v, want %s", e, tt.wantEdge)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		keys        []Key
+		wantDeleted []string
+		wantStatus  string
+	}{
+		{"declined", []Key{"d", "n"}, nil, "Aborted"},
+		{"node", []Key{"d", "y"}, []string{"n1"}, `Deleted node "Alice"`},
+		{"edge", []Key{KeyTab, "d", "y"}, []string{"e1"}, `Deleted edge "Alice works at Acme"`},
+		{"episode", []Key{"e", "d", "Y"}, []string{"ep1"}, `Deleted episode "Alice joined Acme"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newFakeSource()
+			m := loadModel(t, src)
+			press(m, tt.keys[:len(tt.keys)-1]...)
+			if m.mode != confirming {
+				t.Fatalf("delete did not ask for confirmation")
+			}
+			press(m, tt.keys[len(tt.keys)-1])
+
+			if !slices.Equal(src.deleted, tt.wantDeleted) {
+				t.Errorf("deleted %v, want %v", src.deleted, tt.wantDeleted)
+			}
+			if m.status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", m.status, tt.wantStatus)
+			}
+		})
+	}
+
+	t.Run("node selection moves on", func(t *testing.T) {
+		m := loadModel(t, newFakeSource())
+		press(m, "d", "y")
+		if got := selectedNode(m); got != "Acme" {
+			t.Errorf("selected node = %q, want Acme", got)
+		}
+	})
+}
+
+func TestQuit(t *testing.T) {
+	for _, k := range []Key{"q", KeyCtrlC} {
+		m := loadModel(t, newFakeSource())
+		press(m, k)
+		if !m.Done() {
+			t.Errorf("%q did not quit", k)
+		}
+	}
+
+	// q is text while searching, but ctrl-c still quits.
+	m := loadModel(t, newFakeSource())
+	press(m, "/", "q")
+	if m.Done() {
+		t.Error("q quit while searching")
+	}
+	press(m, KeyCtrlC)
+	if !m.Done() {
+		t.Error("ctrl-c did not quit while searching")
+	}
+}
+
+func TestView(t *testing.T) {
+	m := loadModel(t, newFakeSource())
+	press(m, KeyTab, "d")
+
+	lines := m.View(100, 20)
+	if len(lines) != 20 {
+		t.Fatalf("View returned %d lines, want 20", len(lines))
+	}
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{
+		`zepctl explore: user "alice"`,
+		"NODES (2)",
+		"▸ EDGES (2)",
+		"EPISODES (1)",
+		"Alice → Acme: Alice works at Acme",
+		"n3 → Alice: Bob knows Alice",
+		"Fact: Alice works at Acme",
+		`Delete edge "Alice works at Acme"? [y/N]`,
+	} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("screen is missing %q:\n%s", want, screen)
+		}
+	}
+}
+
+func TestDecodeKeys(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Key
+	}{
+		{"abc", []Key{"a", "b", "c"}},
+		{"\x1b[A\x1b[B", []Key{KeyUp, KeyDown}},
+		{"\x1bOC\x1b[D", []Key{KeyRight, KeyLeft}},
+		{"\x1b[5~\x1b[6~", []Key{KeyPgUp, KeyPgDown}},
+		{"\t\x1b[Z", []Key{KeyTab, KeyBacktab}},
+		{"\r\x7f\x03", []Key{KeyEnter, KeyBackspace, KeyCtrlC}},
+		{"\x1b", []Key{KeyEsc}},
+		{"\x1bq", []Key{KeyEsc, "q"}},
+		{"\x1b[1;5Cx", []Key{"x"}},
+		{"é\x01", []Key{"é"}},
+	}
+	for _, tt := range tests {
+		if got := decodeKeys([]byte(tt.in)); !slices.Equal(got, tt.want) {
+			t.Errorf("decodeKeys(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
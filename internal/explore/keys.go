@@ -0,0 +1,106 @@
+package explore
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// Key is a decoded key press: either the name of a special key such as "up"
+// or "enter", or the single character that was typed.
+type Key string
+
+// Special keys.
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyPgUp      Key = "pgup"
+	KeyPgDown    Key = "pgdown"
+	KeyEnter     Key = "enter"
+	KeyTab       Key = "tab"
+	KeyBacktab   Key = "backtab"
+	KeyEsc       Key = "esc"
+	KeyBackspace Key = "backspace"
+	KeyCtrlC     Key = "ctrl+c"
+)
+
+// isChar reports whether k is a typed character rather than a special key.
+func (k Key) isChar() bool {
+	return utf8.RuneCountInString(string(k)) == 1
+}
+
+// escapes maps the escape sequences terminals send for special keys.
+var escapes = []struct {
+	seq string
+	key Key
+}{
+	{"\x1b[A", KeyUp},
+	{"\x1b[B", KeyDown},
+	{"\x1b[C", KeyRight},
+	{"\x1b[D", KeyLeft},
+	{"\x1bOA", KeyUp},
+	{"\x1bOB", KeyDown},
+	{"\x1bOC", KeyRight},
+	{"\x1bOD", KeyLeft},
+	{"\x1b[5~", KeyPgUp},
+	{"\x1b[6~", KeyPgDown},
+	{"\x1b[Z", KeyBacktab},
+}
+
+// decodeKeys splits raw terminal input into key presses. Unrecognized escape
+// sequences and control characters are dropped.
+func decodeKeys(b []byte) []Key {
+	var keys []Key
+	for len(b) > 0 {
+		if b[0] == 0x1b {
+			n, key := decodeEscape(b)
+			if key != "" {
+				keys = append(keys, key)
+			}
+			b = b[n:]
+			continue
+		}
+
+		switch b[0] {
+		case '\r', '\n':
+			keys = append(keys, KeyEnter)
+		case '\t':
+			keys = append(keys, KeyTab)
+		case 0x7f, 0x08:
+			keys = append(keys, KeyBackspace)
+		case 0x03:
+			keys = append(keys, KeyCtrlC)
+		default:
+			r, size := utf8.DecodeRune(b)
+			if r >= 0x20 && r != utf8.RuneError {
+				keys = append(keys, Key(string(r)))
+			}
+			b = b[size:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+// decodeEscape decodes the escape sequence at the start of b, returning how
+// many bytes it used and the key, which is empty for unknown sequences. An
+// escape byte that does not start a sequence is the Esc key.
+func decodeEscape(b []byte) (int, Key) {
+	for _, e := range escapes {
+		if bytes.HasPrefix(b, []byte(e.seq)) {
+			return len(e.seq), e.key
+		}
+	}
+	if len(b) < 2 || (b[1] != '[' && b[1] != 'O') {
+		return 1, KeyEsc
+	}
+	// Skip an unknown CSI or SS3 sequence up to its final byte.
+	for i := 2; i < len(b); i++ {
+		if b[i] >= 0x40 && b[i] <= 0x7e {
+			return i + 1, ""
+		}
+	}
+	return len(b), ""
+}
@@ -0,0 +1,418 @@
+package explore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/getzep/zep-go/v3"
+)
+
+// Source is the data the explorer browses: the nodes of one user graph or
+// standalone graph, and the edges and episodes around them.
+type Source interface {
+	// Nodes lists the graph's nodes.
+	Nodes(ctx context.Context) ([]*zep.EntityNode, error)
+	// Search returns the nodes matching a query.
+	Search(ctx context.Context, query string) ([]*zep.EntityNode, error)
+	// Node gets a single node.
+	Node(ctx context.Context, uuid string) (*zep.EntityNode, error)
+	// NodeEdges lists the edges connected to a node.
+	NodeEdges(ctx context.Context, uuid string) ([]*zep.EntityEdge, error)
+	// NodeEpisodes lists the episodes that mention a node.
+	NodeEpisodes(ctx context.Context, uuid string) ([]*zep.Episode, error)
+
+	DeleteNode(ctx context.Context, uuid string) error
+	DeleteEdge(ctx context.Context, uuid string) error
+	DeleteEpisode(ctx context.Context, uuid string) error
+}
+
+type pane int
+
+const (
+	nodesPane pane = iota
+	edgesPane
+	episodesPane
+	paneCount
+)
+
+type mode int
+
+const (
+	browsing mode = iota
+	searching
+	confirming
+)
+
+// pageSize is how far PgUp and PgDown move the selection.
+const pageSize = 10
+
+// list is the items shown in a pane with the selected row and the first
+// visible row.
+type list[T any] struct {
+	items []T
+	sel   int
+	top   int
+}
+
+func (l *list[T]) set(items []T) {
+	l.items, l.sel, l.top = items, 0, 0
+}
+
+func (l *list[T]) selected() (T, bool) {
+	if l.sel < 0 || l.sel >= len(l.items) {
+		var zero T
+		return zero, false
+	}
+	return l.items[l.sel], true
+}
+
+// move moves the selection by delta rows and reports whether it changed.
+func (l *list[T]) move(delta int) bool {
+	sel := max(0, min(l.sel+delta, len(l.items)-1))
+	if sel == l.sel {
+		return false
+	}
+	l.sel = sel
+	return true
+}
+
+func (l *list[T]) removeSelected() {
+	if _, ok := l.selected(); !ok {
+		return
+	}
+	l.items = slices.Delete(l.items, l.sel, l.sel+1)
+	l.sel = max(0, min(l.sel, len(l.items)-1))
+}
+
+// pendingDelete is a delete waiting for confirmation.
+type pendingDelete struct {
+	kind  string
+	uuid  string
+	label string
+}
+
+// Model is the explorer's state. Keys are fed to HandleKey, which makes any
+// API calls needed before returning, and View renders the current state.
+type Model struct {
+	src   Source
+	title string
+
+	nodes    list[*zep.EntityNode]
+	edges    list[*zep.EntityEdge]
+	episodes list[*zep.Episode]
+	focus    pane
+	mode     mode
+
+	// query is the search the node list shows results for, empty for every
+	// node; input is the search box while it is being edited.
+	query string
+	input []rune
+
+	confirm *pendingDelete
+	history []string
+	status  string
+	quit    bool
+}
+
+// New creates a model browsing src. The title is shown in the header.
+func New(src Source, title string) *Model {
+	return &Model{src: src, title: title}
+}
+
+// Load fetches the node list and the edges and episodes of the first node.
+func (m *Model) Load(ctx context.Context) error {
+	nodes, err := m.src.Nodes(ctx)
+	if err != nil {
+		return err
+	}
+	m.nodes.set(nodes)
+	m.loadDetails(ctx)
+	return nil
+}
+
+// Done reports whether the user has quit.
+func (m *Model) Done() bool {
+	return m.quit
+}
+
+// HandleKey applies a key press. Failed API calls are shown in the status
+// line rather than returned, so the explorer keeps running.
+func (m *Model) HandleKey(ctx context.Context, k Key) {
+	if k == KeyCtrlC {
+		m.quit = true
+		return
+	}
+	switch m.mode {
+	case searching:
+		m.handleSearchKey(ctx, k)
+	case confirming:
+		m.handleConfirmKey(ctx, k)
+	default:
+		m.handleBrowseKey(ctx, k)
+	}
+}
+
+func (m *Model) handleBrowseKey(ctx context.Context, k Key) {
+	m.status = ""
+	switch k {
+	case "q":
+		m.quit = true
+	case KeyUp, "k":
+		m.move(ctx, -1)
+	case KeyDown, "j":
+		m.move(ctx, 1)
+	case KeyPgUp:
+		m.move(ctx, -pageSize)
+	case KeyPgDown:
+		m.move(ctx, pageSize)
+	case KeyTab, KeyRight:
+		m.focus = (m.focus + 1) % paneCount
+	case KeyBacktab, KeyLeft:
+		m.focus = (m.focus + paneCount - 1) % paneCount
+	case KeyEnter:
+		m.open(ctx)
+	case "e":
+		m.focus = episodesPane
+	case "s":
+		m.followEdge(ctx, func(e *zep.EntityEdge) string { return e.SourceNodeUUID })
+	case "t":
+		m.followEdge(ctx, func(e *zep.EntityEdge) string { return e.TargetNodeUUID })
+	case "b", KeyBackspace:
+		m.back(ctx)
+	case "/":
+		m.mode = searching
+		m.input = []rune(m.query)
+	case "d":
+		m.askDelete()
+	case "r":
+		m.reload(ctx)
+	}
+}
+
+func (m *Model) handleSearchKey(ctx context.Context, k Key) {
+	switch {
+	case k == KeyEnter:
+		m.mode = browsing
+		m.search(ctx, string(m.input))
+	case k == KeyEsc:
+		m.mode = browsing
+	case k == KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case k.isChar():
+		m.input = append(m.input, []rune(string(k))...)
+	}
+}
+
+func (m *Model) handleConfirmKey(ctx context.Context, k Key) {
+	pending := m.confirm
+	m.mode, m.confirm = browsing, nil
+	if k != "y" && k != "Y" {
+		m.status = "Aborted"
+		return
+	}
+	m.delete(ctx, pending)
+}
+
+// move moves the selection in the focused pane. Selecting another node loads
+// its edges and episodes.
+func (m *Model) move(ctx context.Context, delta int) {
+	switch m.focus {
+	case nodesPane:
+		if m.nodes.move(delta) {
+			m.loadDetails(ctx)
+		}
+	case edgesPane:
+		m.edges.move(delta)
+	case episodesPane:
+		m.episodes.move(delta)
+	}
+}
+
+// open moves from a node to its edges, and follows an edge to the node at
+// its other end.
+func (m *Model) open(ctx context.Context) {
+	switch m.focus {
+	case nodesPane:
+		m.focus = edgesPane
+	case edgesPane:
+		current, _ := m.nodes.selected()
+		m.followEdge(ctx, func(e *zep.EntityEdge) string {
+			if current != nil && e.SourceNodeUUID == current.UUID {
+				return e.TargetNodeUUID
+			}
+			return e.SourceNodeUUID
+		})
+	}
+}
+
+func (m *Model) followEdge(ctx context.Context, end func(*zep.EntityEdge) string) {
+	edge, ok := m.edges.selected()
+	if m.focus != edgesPane || !ok {
+		m.status = "Select an edge first"
+		return
+	}
+	m.jumpTo(ctx, end(edge), true)
+}
+
+// jumpTo selects the node with the given UUID, fetching it if it is not in
+// the node list. With remember set, the previously selected node is pushed
+// onto the history so back can return to it.
+func (m *Model) jumpTo(ctx context.Context, uuid string, remember bool) {
+	i := slices.IndexFunc(m.nodes.items, func(n *zep.EntityNode) bool { return n.UUID == uuid })
+	if i < 0 {
+		node, err := m.src.Node(ctx, uuid)
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		m.nodes.items = append(m.nodes.items, node)
+		i = len(m.nodes.items) - 1
+	}
+	if current, ok := m.nodes.selected(); ok && remember {
+		m.history = append(m.history, current.UUID)
+	}
+	m.nodes.sel = i
+	m.focus = nodesPane
+	m.loadDetails(ctx)
+}
+
+func (m *Model) back(ctx context.Context) {
+	if len(m.history) == 0 {
+		m.status = "No previous node"
+		return
+	}
+	uuid := m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+	m.jumpTo(ctx, uuid, false)
+}
+
+// search replaces the node list with the nodes matching query, or with every
+// node if query is empty.
+func (m *Model) search(ctx context.Context, query string) {
+	var nodes []*zep.EntityNode
+	var err error
+	if query == "" {
+		nodes, err = m.src.Nodes(ctx)
+	} else {
+		nodes, err = m.src.Search(ctx, query)
+	}
+	if err != nil {
+		m.fail(err)
+		return
+	}
+
+	m.query = query
+	m.nodes.set(nodes)
+	m.focus = nodesPane
+	m.loadDetails(ctx)
+	if len(nodes) == 0 && query != "" {
+		m.status = fmt.Sprintf("No nodes match %q", query)
+	}
+}
+
+// reload fetches the node list again, keeping the selected node if it still
+// exists.
+func (m *Model) reload(ctx context.Context) {
+	current, _ := m.nodes.selected()
+	m.search(ctx, m.query)
+	if current != nil {
+		if i := slices.IndexFunc(m.nodes.items, func(n *zep.EntityNode) bool { return n.UUID == current.UUID }); i > 0 {
+			m.nodes.sel = i
+			m.loadDetails(ctx)
+		}
+	}
+}
+
+// loadDetails fetches the edges and episodes of the selected node.
+func (m *Model) loadDetails(ctx context.Context) {
+	m.edges.set(nil)
+	m.episodes.set(nil)
+	node, ok := m.nodes.selected()
+	if !ok {
+		return
+	}
+
+	edges, err := m.src.NodeEdges(ctx, node.UUID)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.edges.set(edges)
+
+	episodes, err := m.src.NodeEpisodes(ctx, node.UUID)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.episodes.set(episodes)
+}
+
+// askDelete asks to confirm deleting the item selected in the focused pane.
+func (m *Model) askDelete() {
+	var pending *pendingDelete
+	switch m.focus {
+	case nodesPane:
+		if n, ok := m.nodes.selected(); ok {
+			pending = &pendingDelete{kind: "node", uuid: n.UUID, label: n.Name}
+		}
+	case edgesPane:
+		if e, ok := m.edges.selected(); ok {
+			pending = &pendingDelete{kind: "edge", uuid: e.UUID, label: truncate(e.Fact, 40)}
+		}
+	case episodesPane:
+		if ep, ok := m.episodes.selected(); ok {
+			pending = &pendingDelete{kind: "episode", uuid: ep.UUID, label: truncate(ep.Content, 40)}
+		}
+	}
+	if pending == nil {
+		m.status = "Nothing selected"
+		return
+	}
+	m.mode, m.confirm = confirming, pending
+}
+
+func (m *Model) delete(ctx context.Context, p *pendingDelete) {
+	var err error
+	switch p.kind {
+	case "node":
+		err = m.src.DeleteNode(ctx, p.uuid)
+	case "edge":
+		err = m.src.DeleteEdge(ctx, p.uuid)
+	case "episode":
+		err = m.src.DeleteEpisode(ctx, p.uuid)
+	}
+	if err != nil {
+		m.fail(err)
+		return
+	}
+
+	switch p.kind {
+	case "node":
+		m.nodes.removeSelected()
+		m.history = slices.DeleteFunc(m.history, func(uuid string) bool { return uuid == p.uuid })
+		m.loadDetails(ctx)
+	case "edge":
+		m.edges.removeSelected()
+	case "episode":
+		m.episodes.removeSelected()
+	}
+	m.status = fmt.Sprintf("Deleted %s %q", p.kind, p.label)
+}
+
+func (m *Model) fail(err error) {
+	m.status = "Error: " + err.Error()
+}
+
+// nodeName returns the name of a node in the node list, or its UUID if it is
+// not loaded.
+func (m *Model) nodeName(uuid string) string {
+	for _, n := range m.nodes.items {
+		if n.UUID == uuid {
+			return n.Name
+		}
+	}
+	return uuid
+}
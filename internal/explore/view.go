@@ -0,0 +1,218 @@
+package explore
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/getzep/zep-go/v3"
+)
+
+const (
+	reverse = "\x1b[7m"
+	bold    = "\x1b[1m"
+	reset   = "\x1b[0m"
+)
+
+const help = "↑↓ move  tab pane  enter open  s/t source/target  e episodes  b back  / search  d delete  r reload  q quit"
+
+// View renders the screen as height lines of width columns, not counting
+// ANSI attributes.
+func (m *Model) View(width, height int) []string {
+	width, height = max(width, 40), max(height, 10)
+
+	lines := []string{reverse + fit(" "+m.header(), width) + reset}
+
+	bodyHeight := height - 3
+	leftWidth := max(24, width*2/5)
+	rightWidth := width - leftWidth - 3
+	left := renderList(&m.nodes, "NODES", leftWidth, bodyHeight, m.focus == nodesPane, func(n *zep.EntityNode) string {
+		return n.Name
+	})
+	right := m.viewRight(rightWidth, bodyHeight)
+	for i := range bodyHeight {
+		lines = append(lines, left[i]+" │ "+right[i])
+	}
+
+	lines = append(lines, fit(m.prompt(), width), fit(help, width))
+	return lines
+}
+
+func (m *Model) header() string {
+	h := "zepctl explore: " + m.title
+	if m.query != "" {
+		h += fmt.Sprintf("  search: %q", m.query)
+	}
+	return h
+}
+
+// prompt is the line above the help: the search box, a delete confirmation
+// or the status message.
+func (m *Model) prompt() string {
+	switch m.mode {
+	case searching:
+		return "Search: " + string(m.input) + "█"
+	case confirming:
+		return fmt.Sprintf("Delete %s %q? [y/N]", m.confirm.kind, m.confirm.label)
+	}
+	return m.status
+}
+
+// viewRight renders the details of the focused item above the edge and
+// episode panes.
+func (m *Model) viewRight(width, height int) []string {
+	detailsHeight := height / 3
+	edgesHeight := (height - detailsHeight) / 2
+	episodesHeight := height - detailsHeight - edgesHeight
+
+	details := []string{bold + fit("DETAILS", width) + reset}
+	for _, line := range m.details(width) {
+		details = append(details, fit(line, width))
+	}
+	details = pad(details, detailsHeight, width)
+
+	edges := renderList(&m.edges, "EDGES", width, edgesHeight, m.focus == edgesPane, func(e *zep.EntityEdge) string {
+		return fmt.Sprintf("%s → %s: %s", m.nodeName(e.SourceNodeUUID), m.nodeName(e.TargetNodeUUID), e.Fact)
+	})
+	episodes := renderList(&m.episodes, "EPISODES", width, episodesHeight, m.focus == episodesPane, func(ep *zep.Episode) string {
+		return ep.CreatedAt + "  " + ep.Content
+	})
+	return slices.Concat(details, edges, episodes)
+}
+
+// details describes the item selected in the focused pane.
+func (m *Model) details(width int) []string {
+	var lines []string
+	field := func(name, value string) {
+		if value != "" {
+			lines = append(lines, wrap(name+": "+value, width)...)
+		}
+	}
+
+	switch m.focus {
+	case nodesPane:
+		n, ok := m.nodes.selected()
+		if !ok {
+			return nil
+		}
+		field("Name", n.Name)
+		field("UUID", n.UUID)
+		field("Labels", strings.Join(n.Labels, ", "))
+		field("Created At", n.CreatedAt)
+		field("Summary", n.Summary)
+		for _, k := range slices.Sorted(maps.Keys(n.Attributes)) {
+			field(k, fmt.Sprint(n.Attributes[k]))
+		}
+	case edgesPane:
+		e, ok := m.edges.selected()
+		if !ok {
+			return nil
+		}
+		field("Name", e.Name)
+		field("UUID", e.UUID)
+		field("Fact", e.Fact)
+		field("Source", m.nodeName(e.SourceNodeUUID))
+		field("Target", m.nodeName(e.TargetNodeUUID))
+		field("Valid At", stringValue(e.ValidAt))
+		field("Invalid At", stringValue(e.InvalidAt))
+	case episodesPane:
+		ep, ok := m.episodes.selected()
+		if !ok {
+			return nil
+		}
+		field("UUID", ep.UUID)
+		if ep.Source != nil {
+			field("Source", string(*ep.Source))
+		}
+		field("Created At", ep.CreatedAt)
+		field("Content", ep.Content)
+	}
+	return lines
+}
+
+// renderList renders a titled pane, scrolling so the selected row is
+// visible. The selected row is highlighted when the pane has focus.
+func renderList[T any](l *list[T], title string, width, height int, focused bool, label func(T) string) []string {
+	header := fmt.Sprintf("%s (%d)", title, len(l.items))
+	if focused {
+		header = "▸ " + header
+	}
+	lines := []string{bold + fit(header, width) + reset}
+
+	rows := height - 1
+	if l.sel < l.top {
+		l.top = l.sel
+	}
+	if l.sel >= l.top+rows {
+		l.top = l.sel - rows + 1
+	}
+	for i := l.top; i < len(l.items) && i < l.top+rows; i++ {
+		line := fit("  "+label(l.items[i]), width)
+		if i == l.sel {
+			line = fit("› "+label(l.items[i]), width)
+			if focused {
+				line = reverse + line + reset
+			}
+		}
+		lines = append(lines, line)
+	}
+	return pad(lines, height, width)
+}
+
+// pad pads or cuts lines to height rows of width blanks.
+func pad(lines []string, height, width int) []string {
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines[:height]
+}
+
+var flatten = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
+
+// fit flattens s to one line and truncates or pads it to width runes.
+func fit(s string, width int) string {
+	s = flatten.Replace(s)
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	runes := []rune(strings.Join(strings.Fields(s), " "))
+	if len(runes) <= n {
+		return string(runes)
+	}
+	return string(runes[:n]) + "..."
+}
+
+// wrap breaks s into lines of at most width runes at word boundaries.
+func wrap(s string, width int) []string {
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(s) {
+		w := []rune(word)
+		if len(line) > 0 && len(line)+1+len(w) > width {
+			lines = append(lines, string(line))
+			line = nil
+		}
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+		line = append(line, w...)
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
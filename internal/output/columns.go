@@ -0,0 +1,229 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ErrInvalidColumn is returned when --columns or --sort-by names a column the
+// resource does not have.
+var ErrInvalidColumn = errors.New("invalid column")
+
+// Column is one column of a resource's table view.
+type Column[T any] struct {
+	// Name is the table header, e.g. "USER ID". --columns and --sort-by match
+	// it case-insensitively with spaces written as "-" or "_".
+	Name string
+	// Wide columns are only shown with -o wide or when selected by --columns.
+	Wide bool
+	// Value renders the cell for an item.
+	Value func(T) string
+}
+
+// Columns is the column registry of a resource type: every column that can be
+// shown for it, in display order.
+type Columns[T any] []Column[T]
+
+// columnKey normalizes a column name for matching, so "USER ID", "user-id"
+// and "user_id" all refer to the same column.
+func columnKey(name string) string {
+	return strings.NewReplacer(" ", "-", "_", "-").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+func (cs Columns[T]) lookup(name string) (Column[T], error) {
+	key := columnKey(name)
+	for _, c := range cs {
+		if columnKey(c.Name) == key {
+			return c, nil
+		}
+	}
+	return Column[T]{}, fmt.Errorf("%w %q (available: %s)", ErrInvalidColumn, name, strings.Join(cs.keys(), ", "))
+}
+
+func (cs Columns[T]) keys() []string {
+	keys := make([]string, len(cs))
+	for i, c := range cs {
+		keys[i] = columnKey(c.Name)
+	}
+	return keys
+}
+
+// WithDefault returns a copy of the registry for a view with its own default
+// columns: the named columns, in the given order, followed by every other
+// column as a wide column. It panics on unknown names, which are programming
+// errors.
+func (cs Columns[T]) WithDefault(names ...string) Columns[T] {
+	var view Columns[T]
+	for _, name := range names {
+		c, err := cs.lookup(name)
+		if err != nil {
+			panic(err)
+		}
+		c.Wide = false
+		view = append(view, c)
+	}
+	for _, c := range cs {
+		if !slices.ContainsFunc(names, func(name string) bool { return columnKey(name) == columnKey(c.Name) }) {
+			c.Wide = true
+			view = append(view, c)
+		}
+	}
+	return view
+}
+
+// Select returns the columns to display: the list given with --columns if
+// set, otherwise the default columns, plus the wide columns for -o wide.
+func (cs Columns[T]) Select() (Columns[T], error) {
+	if spec := viper.GetString("columns"); spec != "" {
+		var selected Columns[T]
+		for _, name := range strings.Split(spec, ",") {
+			c, err := cs.lookup(name)
+			if err != nil {
+				return nil, err
+			}
+			selected = append(selected, c)
+		}
+		return selected, nil
+	}
+
+	wide := GetFormat() == FormatWide
+	var selected Columns[T]
+	for _, c := range cs {
+		if !c.Wide || wide {
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
+
+// Sort orders items in place by the column named with --sort-by. A leading
+// "-" sorts in descending order. Values that are both numbers compare
+// numerically; everything else, including RFC 3339 timestamps, compares as
+// text. Items with equal values keep their original order.
+func (cs Columns[T]) Sort(items []T) error {
+	spec := viper.GetString("sort-by")
+	if spec == "" {
+		return nil
+	}
+	name, desc := strings.CutPrefix(spec, "-")
+	c, err := cs.lookup(name)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, len(items))
+	indexed := make([]int, len(items))
+	for i, item := range items {
+		indexed[i] = i
+		keys[i] = c.Value(item)
+	}
+	slices.SortStableFunc(indexed, func(a, b int) int {
+		n := compareValues(keys[a], keys[b])
+		if desc {
+			return -n
+		}
+		return n
+	})
+
+	sorted := make([]T, len(items))
+	for i, idx := range indexed {
+		sorted[i] = items[idx]
+	}
+	copy(items, sorted)
+	return nil
+}
+
+// IsSorted reports whether --sort-by is set.
+func IsSorted() bool {
+	return viper.GetString("sort-by") != ""
+}
+
+func compareValues(a, b string) int {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// ColumnTable writes items as rows of a Table using a set of columns.
+type ColumnTable[T any] struct {
+	cols Columns[T]
+	tbl  *Table
+}
+
+// NewColumnTable selects the columns to display and writes the table header.
+func (cs Columns[T]) NewColumnTable() (*ColumnTable[T], error) {
+	selected, err := cs.Select()
+	if err != nil {
+		return nil, err
+	}
+	headers := make([]string, len(selected))
+	for i, c := range selected {
+		headers[i] = c.Name
+	}
+	tbl := NewTable(headers...)
+	tbl.WriteHeader()
+	return &ColumnTable[T]{cols: selected, tbl: tbl}, nil
+}
+
+// Write adds a row for each item.
+func (t *ColumnTable[T]) Write(items ...T) {
+	for _, item := range items {
+		row := make([]string, len(t.cols))
+		for i, c := range t.cols {
+			row[i] = c.Value(item)
+		}
+		t.tbl.WriteRow(row...)
+	}
+}
+
+// Flush flushes the table output.
+func (t *ColumnTable[T]) Flush() error {
+	return t.tbl.Flush()
+}
+
+// Render sorts items and writes them as a table.
+func (cs Columns[T]) Render(items []T) error {
+	if err := cs.Sort(items); err != nil {
+		return err
+	}
+	t, err := cs.NewColumnTable()
+	if err != nil {
+		return err
+	}
+	t.Write(items...)
+	return t.Flush()
+}
+
+// RenderFields writes a single item as a FIELD/VALUE table with one row per
+// selected column, for resources that are shown one at a time. Empty values
+// are omitted unless the column was asked for with --columns.
+func (cs Columns[T]) RenderFields(item T) error {
+	selected, err := cs.Select()
+	if err != nil {
+		return err
+	}
+	explicit := viper.GetString("columns") != ""
+	tbl := NewTable("FIELD", "VALUE")
+	tbl.WriteHeader()
+	for _, c := range selected {
+		if v := c.Value(item); v != "" || explicit {
+			tbl.WriteRow(c.Name, v)
+		}
+	}
+	return tbl.Flush()
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+type row struct {
+	name string
+	size string
+}
+
+var testColumns = Columns[row]{
+	{Name: "NAME", Value: func(r row) string { return r.name }},
+	{Name: "SIZE", Value: func(r row) string { return r.size }},
+	{Name: "SIZE CLASS", Wide: true, Value: func(r row) string {
+		if len(r.size) > 1 {
+			return "big"
+		}
+		return "small"
+	}},
+}
+
+func TestColumnsSelect(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		columns string
+		want    []string
+		wantErr bool
+	}{
+		{name: "default", output: "table", want: []string{"NAME", "SIZE"}},
+		{name: "wide", output: "wide", want: []string{"NAME", "SIZE", "SIZE CLASS"}},
+		{name: "custom", output: "table", columns: "size-class,name", want: []string{"SIZE CLASS", "NAME"}},
+		{name: "custom normalized", output: "csv", columns: "Size_Class", want: []string{"SIZE CLASS"}},
+		{name: "unknown", output: "table", columns: "name,color", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("output", tt.output)
+			viper.Set("columns", tt.columns)
+			defer viper.Reset()
+
+			selected, err := testColumns.Select()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Select succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Select: %v", err)
+			}
+			var got []string
+			for _, c := range selected {
+				got = append(got, c.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnsWithDefault(t *testing.T) {
+	view := testColumns.WithDefault("SIZE CLASS", "NAME")
+	var got []string
+	for _, c := range view {
+		if !c.Wide {
+			got = append(got, c.Name)
+		}
+	}
+	if want := []string{"SIZE CLASS", "NAME"}; !slices.Equal(got, want) {
+		t.Errorf("default columns %v, want %v", got, want)
+	}
+	if len(view) != len(testColumns) || !view[2].Wide {
+		t.Errorf("SIZE should remain available as a wide column: %+v", view)
+	}
+}
+
+func TestColumnsSort(t *testing.T) {
+	rows := []row{{"b", "10"}, {"a", "9"}, {"c", "10"}, {"d", ""}}
+
+	tests := []struct {
+		sortBy  string
+		want    []string
+		wantErr bool
+	}{
+		{sortBy: "", want: []string{"b", "a", "c", "d"}},
+		{sortBy: "name", want: []string{"a", "b", "c", "d"}},
+		{sortBy: "-name", want: []string{"d", "c", "b", "a"}},
+		{sortBy: "size", want: []string{"d", "a", "b", "c"}},
+		{sortBy: "-size", want: []string{"b", "c", "a", "d"}},
+		{sortBy: "size-class", want: []string{"b", "c", "a", "d"}},
+		{sortBy: "color", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			viper.Set("sort-by", tt.sortBy)
+			defer viper.Reset()
+
+			items := slices.Clone(rows)
+			err := testColumns.Sort(items)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Sort succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Sort: %v", err)
+			}
+			var got []string
+			for _, r := range items {
+				got = append(got, r.name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
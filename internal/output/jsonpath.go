@@ -0,0 +1,560 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a compiled kubectl-style JSONPath template, such as
+// "{.users[*].user_id}" or `{range .edges[*]}{.name}{"\t"}{.fact}{"\n"}{end}`.
+// Expressions are evaluated against the JSON form of the data, so field names
+// are the JSON keys shown by -o json.
+//
+// Supported syntax: .field, ['field'], [n], [-n], [start:end], [*], .*, ..field
+// (recursive descent), [?(@.field)] and [?(@.field op literal)] filters with
+// ==, !=, <, <=, >, >=, quoted string literals, and {range}...{end} blocks.
+type JSONPath struct {
+	nodes []jpNode
+}
+
+type jpNode interface{}
+
+type jpText string
+
+type jpExpr struct {
+	steps []jpStep
+}
+
+type jpRange struct {
+	steps []jpStep
+	body  []jpNode
+}
+
+type jpStepKind int
+
+const (
+	stepField jpStepKind = iota
+	stepIndex
+	stepWildcard
+	stepRecursive
+	stepSlice
+	stepFilter
+)
+
+type jpStep struct {
+	kind       jpStepKind
+	name       string
+	index      int
+	start, end *int
+	filter     *jpFilter
+}
+
+type jpFilter struct {
+	steps   []jpStep
+	op      string
+	literal any
+}
+
+// ParseJSONPath compiles a JSONPath template. A template without braces is
+// treated as a single expression, so ".users[0].user_id" is accepted too.
+func ParseJSONPath(tmpl string) (*JSONPath, error) {
+	if !strings.Contains(tmpl, "{") {
+		tmpl = "{" + tmpl + "}"
+	}
+
+	p := &JSONPath{}
+	stack := []*[]jpNode{&p.nodes}
+	var ranges []*jpRange
+	appendNode := func(n jpNode) {
+		cur := stack[len(stack)-1]
+		*cur = append(*cur, n)
+	}
+
+	rest := tmpl
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			appendNode(jpText(rest))
+			break
+		}
+		if open > 0 {
+			appendNode(jpText(rest[:open]))
+		}
+		end, err := matchBrace(rest, open)
+		if err != nil {
+			return nil, err
+		}
+		action := strings.TrimSpace(rest[open+1 : end])
+		rest = rest[end+1:]
+
+		switch {
+		case action == "end":
+			if len(ranges) == 0 {
+				return nil, fmt.Errorf("unexpected {end}")
+			}
+			stack = stack[:len(stack)-1]
+			ranges = ranges[:len(ranges)-1]
+		case strings.HasPrefix(action, "range "):
+			steps, err := parsePath(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+			if err != nil {
+				return nil, err
+			}
+			r := &jpRange{steps: steps}
+			appendNode(r)
+			ranges = append(ranges, r)
+			stack = append(stack, &r.body)
+		case strings.HasPrefix(action, `"`):
+			text, err := strconv.Unquote(action)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string literal %s: %w", action, err)
+			}
+			appendNode(jpText(text))
+		default:
+			steps, err := parsePath(action)
+			if err != nil {
+				return nil, err
+			}
+			appendNode(&jpExpr{steps: steps})
+		}
+	}
+	if len(ranges) > 0 {
+		return nil, fmt.Errorf("{range} without matching {end}")
+	}
+	return p, nil
+}
+
+// matchBrace returns the index of the '}' closing the '{' at open, skipping
+// braces inside string literals.
+func matchBrace(s string, open int) (int, error) {
+	var quote byte
+	for i := open + 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unclosed { in %q", s[open:])
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// parsePath parses a path expression such as "$.users[*].user_id".
+func parsePath(expr string) ([]jpStep, error) {
+	var steps []jpStep
+	p := strings.TrimPrefix(strings.TrimSpace(expr), "$")
+	p = strings.TrimPrefix(p, "@")
+
+	for p != "" {
+		switch {
+		case strings.HasPrefix(p, ".."):
+			steps = append(steps, jpStep{kind: stepRecursive})
+			p = p[2:]
+			if p != "" && p[0] != '[' {
+				p = "." + p
+			}
+		case p[0] == '.':
+			p = p[1:]
+			switch {
+			case p == "":
+			case p[0] == '*':
+				steps = append(steps, jpStep{kind: stepWildcard})
+				p = p[1:]
+			case p[0] == '[' || p[0] == '.':
+			default:
+				n := 0
+				for n < len(p) && isIdentChar(p[n]) {
+					n++
+				}
+				if n == 0 {
+					return nil, fmt.Errorf("invalid path %q: unexpected %q", expr, p[0])
+				}
+				steps = append(steps, jpStep{kind: stepField, name: p[:n]})
+				p = p[n:]
+			}
+		case p[0] == '[':
+			end, err := matchBracket(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", expr, err)
+			}
+			step, err := parseSubscript(strings.TrimSpace(p[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", expr, err)
+			}
+			steps = append(steps, step)
+			p = p[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid path %q: expected '.' or '[' at %q", expr, p)
+		}
+	}
+	return steps, nil
+}
+
+// matchBracket returns the index of the ']' closing the '[' at the start of
+// s, skipping brackets inside string literals and filter expressions.
+func matchBracket(s string) (int, error) {
+	var quote byte
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unclosed [")
+}
+
+func parseSubscript(sub string) (jpStep, error) {
+	switch {
+	case sub == "*":
+		return jpStep{kind: stepWildcard}, nil
+	case strings.HasPrefix(sub, "'") || strings.HasPrefix(sub, `"`):
+		name, err := unquote(sub)
+		if err != nil {
+			return jpStep{}, err
+		}
+		return jpStep{kind: stepField, name: name}, nil
+	case strings.HasPrefix(sub, "?(") && strings.HasSuffix(sub, ")"):
+		f, err := parseFilter(strings.TrimSpace(sub[2 : len(sub)-1]))
+		if err != nil {
+			return jpStep{}, err
+		}
+		return jpStep{kind: stepFilter, filter: f}, nil
+	case strings.Contains(sub, ":"):
+		lo, hi, _ := strings.Cut(sub, ":")
+		step := jpStep{kind: stepSlice}
+		for _, b := range []struct {
+			s   string
+			dst **int
+		}{{lo, &step.start}, {hi, &step.end}} {
+			if s := strings.TrimSpace(b.s); s != "" {
+				n, err := strconv.Atoi(s)
+				if err != nil {
+					return jpStep{}, fmt.Errorf("invalid slice %q", sub)
+				}
+				*b.dst = &n
+			}
+		}
+		return step, nil
+	default:
+		n, err := strconv.Atoi(sub)
+		if err != nil {
+			return jpStep{}, fmt.Errorf("invalid subscript %q", sub)
+		}
+		return jpStep{kind: stepIndex, index: n}, nil
+	}
+}
+
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") && len(s) >= 2 {
+		return s[1 : len(s)-1], nil
+	}
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	return v, nil
+}
+
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func parseFilter(expr string) (*jpFilter, error) {
+	if !strings.HasPrefix(expr, "@") {
+		return nil, fmt.Errorf("filter %q must start with @", expr)
+	}
+	for _, op := range filterOps {
+		lhs, rhs, ok := strings.Cut(expr, op)
+		if !ok {
+			continue
+		}
+		steps, err := parsePath(strings.TrimSpace(lhs))
+		if err != nil {
+			return nil, err
+		}
+		lit, err := parseLiteral(strings.TrimSpace(rhs))
+		if err != nil {
+			return nil, err
+		}
+		return &jpFilter{steps: steps, op: op, literal: lit}, nil
+	}
+	steps, err := parsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &jpFilter{steps: steps}, nil
+}
+
+func parseLiteral(s string) (any, error) {
+	switch {
+	case strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`):
+		return unquote(s)
+	case s == "true" || s == "false":
+		return s == "true", nil
+	case s == "null":
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid literal %q", s)
+	}
+	return f, nil
+}
+
+// Execute evaluates the template against data and writes the result to w.
+func (p *JSONPath) Execute(w io.Writer, data any) error {
+	normalized, err := toJSONValue(data)
+	if err != nil {
+		return err
+	}
+	return executeNodes(w, p.nodes, normalized)
+}
+
+// toJSONValue converts data to its generic JSON representation.
+func toJSONValue(data any) (any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func executeNodes(w io.Writer, nodes []jpNode, cur any) error {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case jpText:
+			if _, err := io.WriteString(w, string(n)); err != nil {
+				return err
+			}
+		case *jpExpr:
+			results := evalSteps([]any{cur}, n.steps)
+			parts := make([]string, len(results))
+			for i, r := range results {
+				parts[i] = formatJSONValue(r)
+			}
+			if _, err := io.WriteString(w, strings.Join(parts, " ")); err != nil {
+				return err
+			}
+		case *jpRange:
+			for _, item := range evalSteps([]any{cur}, n.steps) {
+				if err := executeNodes(w, n.body, item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func evalSteps(values []any, steps []jpStep) []any {
+	for _, step := range steps {
+		var next []any
+		for _, v := range values {
+			next = append(next, evalStep(v, step)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func evalStep(v any, step jpStep) []any {
+	switch step.kind {
+	case stepField:
+		if m, ok := v.(map[string]any); ok {
+			if child, ok := m[step.name]; ok {
+				return []any{child}
+			}
+		}
+	case stepIndex:
+		if a, ok := v.([]any); ok {
+			i := step.index
+			if i < 0 {
+				i += len(a)
+			}
+			if i >= 0 && i < len(a) {
+				return []any{a[i]}
+			}
+		}
+	case stepWildcard:
+		return children(v)
+	case stepRecursive:
+		out := []any{v}
+		for _, c := range children(v) {
+			out = append(out, evalStep(c, step)...)
+		}
+		return out
+	case stepSlice:
+		if a, ok := v.([]any); ok {
+			start, end := 0, len(a)
+			if step.start != nil {
+				start = clampIndex(*step.start, len(a))
+			}
+			if step.end != nil {
+				end = clampIndex(*step.end, len(a))
+			}
+			if start < end {
+				return a[start:end]
+			}
+		}
+	case stepFilter:
+		var out []any
+		for _, c := range children(v) {
+			if step.filter.match(c) {
+				out = append(out, c)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	return max(0, min(i, n))
+}
+
+// children returns the elements of an array or the values of an object in
+// key order.
+func children(v any) []any {
+	switch v := v.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]any, len(keys))
+		for i, k := range keys {
+			out[i] = v[k]
+		}
+		return out
+	}
+	return nil
+}
+
+func (f *jpFilter) match(v any) bool {
+	results := evalSteps([]any{v}, f.steps)
+	if f.op == "" {
+		return len(results) > 0 && results[0] != nil && results[0] != false
+	}
+	if len(results) == 0 {
+		return f.op == "!="
+	}
+	return compare(results[0], f.op, f.literal)
+}
+
+func compare(v any, op string, literal any) bool {
+	if lit, ok := literal.(float64); ok {
+		n, isNum := v.(json.Number)
+		if !isNum {
+			return op == "!="
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return false
+		}
+		switch op {
+		case "==":
+			return f == lit
+		case "!=":
+			return f != lit
+		case "<":
+			return f < lit
+		case "<=":
+			return f <= lit
+		case ">":
+			return f > lit
+		case ">=":
+			return f >= lit
+		}
+		return false
+	}
+
+	if literal == nil {
+		switch op {
+		case "==":
+			return v == nil
+		case "!=":
+			return v != nil
+		}
+		return false
+	}
+
+	a, b := formatJSONValue(v), formatJSONValue(literal)
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+// formatJSONValue renders a JSON value the way kubectl does: scalars as plain
+// text and objects and arrays as compact JSON.
+func formatJSONValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
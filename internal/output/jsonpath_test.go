@@ -0,0 +1,111 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/getzep/zep-go/v3"
+)
+
+func TestJSONPath(t *testing.T) {
+	data := &zep.UserListResponse{
+		TotalCount: zep.Int(3),
+		Users: []*zep.User{
+			{UserID: zep.String("alice"), Email: zep.String("alice@example.com"), Metadata: map[string]any{"plan": "pro", "seats": 5}},
+			{UserID: zep.String("bob"), Metadata: map[string]any{"plan": "free", "seats": 1}},
+			{UserID: zep.String("carol"), Email: zep.String("carol@example.com")},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{name: "field", expr: "{.total_count}", want: "3"},
+		{name: "without braces", expr: ".users[0].user_id", want: "alice"},
+		{name: "root dollar", expr: "{$.users[1].user_id}", want: "bob"},
+		{name: "wildcard", expr: "{.users[*].user_id}", want: "alice bob carol"},
+		{name: "negative index", expr: "{.users[-1].user_id}", want: "carol"},
+		{name: "slice", expr: "{.users[0:2].user_id}", want: "alice bob"},
+		{name: "bracket field", expr: "{.users[0]['email']}", want: "alice@example.com"},
+		{name: "missing field", expr: "{.users[1].email}", want: ""},
+		{name: "recursive descent", expr: "{..plan}", want: "pro free"},
+		{name: "object value", expr: "{.users[1].metadata}", want: `{"plan":"free","seats":1}`},
+		{name: "filter existence", expr: "{.users[?(@.email)].user_id}", want: "alice carol"},
+		{name: "filter string", expr: `{.users[?(@.metadata.plan=="free")].user_id}`, want: "bob"},
+		{name: "filter number", expr: "{.users[?(@.metadata.seats>2)].user_id}", want: "alice"},
+		{name: "range", expr: `{range .users[*]}{.user_id}{"\t"}{.email}{"\n"}{end}`, want: "alice\talice@example.com\nbob\t\ncarol\tcarol@example.com\n"},
+		{name: "literal text", expr: "total={.total_count}", want: "total=3"},
+		{name: "unclosed brace", expr: "{.users", wantErr: true},
+		{name: "unclosed range", expr: "{range .users[*]}{.user_id}", wantErr: true},
+		{name: "stray end", expr: "{end}", wantErr: true},
+		{name: "bad subscript", expr: "{.users[x]}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jp, err := ParseJSONPath(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseJSONPath(%q) succeeded, want error", tt.expr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseJSONPath(%q): %v", tt.expr, err)
+			}
+
+			var buf bytes.Buffer
+			if err := jp.Execute(&buf, data); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	edges := []*zep.EntityEdge{
+		{Name: "WORKS_AT", Fact: "Alice works at Acme"},
+		{Name: "LIVES_IN", Fact: "Alice lives in Paris", Episodes: []string{"e1", "e2"}},
+	}
+	user := &zep.User{UserID: zep.String("alice")}
+
+	tests := []struct {
+		name string
+		tmpl string
+		data any
+		want string
+	}{
+		{name: "range list field", tmpl: "{{range .users}}{{.user_id}} {{end}}", data: &zep.UserListResponse{Users: []*zep.User{user}}, want: "alice "},
+		{name: "items of list response", tmpl: "{{range items .}}{{.user_id}}{{end}}", data: &zep.UserListResponse{Users: []*zep.User{user}, TotalCount: zep.Int(1)}, want: "alice"},
+		{name: "items of slice", tmpl: "{{range items .}}{{.name}};{{end}}", data: edges, want: "WORKS_AT;LIVES_IN;"},
+		{name: "deref missing", tmpl: "[{{deref .email}}]", data: user, want: "[]"},
+		{name: "default", tmpl: `{{default "-" .email}}`, data: user, want: "-"},
+		{name: "default zero number", tmpl: `{{default "none" .total_count}}`, data: &zep.UserListResponse{TotalCount: zep.Int(0)}, want: "none"},
+		{name: "number", tmpl: `{{.total_count}}`, data: &zep.UserListResponse{TotalCount: zep.Int(12)}, want: "12"},
+		{name: "join", tmpl: `{{range .}}{{join "," .episodes}}|{{end}}`, data: edges, want: "|e1,e2|"},
+		{name: "truncate", tmpl: `{{truncate 5 (index . 0).fact}}`, data: edges, want: "Alice..."},
+		{name: "json", tmpl: `{{json (index . 1).episodes}}`, data: edges, want: `["e1","e2"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.tmpl)
+			if err != nil {
+				t.Fatalf("ParseTemplate: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, tt.data); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,15 @@
 package output
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/viper"
@@ -15,22 +20,50 @@ import (
 type Format string
 
 const (
-	FormatTable Format = "table"
-	FormatJSON  Format = "json"
-	FormatYAML  Format = "yaml"
-	FormatWide  Format = "wide"
+	FormatTable  Format = "table"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatYAML   Format = "yaml"
+	FormatWide   Format = "wide"
+	FormatCSV    Format = "csv"
+	FormatTSV    Format = "tsv"
+
+	// Template formats take an argument after "=", e.g. -o jsonpath={.users}.
+	FormatJSONPath       Format = "jsonpath"
+	FormatGoTemplate     Format = "go-template"
+	FormatGoTemplateFile Format = "go-template-file"
 )
 
+func (f Format) isTemplate() bool {
+	return f == FormatJSONPath || f == FormatGoTemplate || f == FormatGoTemplateFile
+}
+
+// ErrInvalidTemplate is returned when a jsonpath or go-template output
+// expression cannot be parsed.
+var ErrInvalidTemplate = errors.New("invalid output template")
+
 // GetFormat returns the configured output format.
 func GetFormat() Format {
-	f := viper.GetString("output")
+	f, _, _ := strings.Cut(viper.GetString("output"), "=")
 	switch f {
 	case "json":
 		return FormatJSON
+	case "ndjson":
+		return FormatNDJSON
 	case "yaml":
 		return FormatYAML
 	case "wide":
 		return FormatWide
+	case "csv":
+		return FormatCSV
+	case "tsv":
+		return FormatTSV
+	case "jsonpath":
+		return FormatJSONPath
+	case "go-template":
+		return FormatGoTemplate
+	case "go-template-file":
+		return FormatGoTemplateFile
 	default:
 		return FormatTable
 	}
@@ -46,45 +79,249 @@ func Fprint(w io.Writer, data any) error {
 	switch GetFormat() {
 	case FormatJSON:
 		return printJSON(w, data)
+	case FormatNDJSON:
+		return printNDJSON(w, data)
 	case FormatYAML:
 		return printYAML(w, data)
-	case FormatTable, FormatWide:
-		// Table/Wide format should be handled by the caller with NewTable.
+	case FormatJSONPath, FormatGoTemplate, FormatGoTemplateFile:
+		return printTemplate(w, data)
+	case FormatTable, FormatWide, FormatCSV, FormatTSV:
+		// Table/Wide/CSV/TSV formats should be handled by the caller with NewTable.
 		// Fall through to JSON for generic Print calls.
 		return printJSON(w, data)
 	}
 	return printJSON(w, data)
 }
 
+// IsTabular reports whether the configured format is rendered with the column
+// layout of NewTable: table, wide, csv or tsv.
+func IsTabular() bool {
+	switch GetFormat() {
+	case FormatTable, FormatWide, FormatCSV, FormatTSV:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTemplate reports whether the configured format renders a jsonpath or Go
+// template.
+func IsTemplate() bool {
+	return GetFormat().isTemplate()
+}
+
+// Truncate shortens s to n characters for display in a table. Wide output
+// shows values in full, and CSV and TSV output is meant for further
+// processing, so values are never truncated in those formats.
+func Truncate(s string, n int) string {
+	if f := GetFormat(); f == FormatWide || f == FormatCSV || f == FormatTSV {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
+
+// ValidateFormat checks that a jsonpath or go-template expression given in
+// the output flag compiles, so mistakes are reported before any API calls.
+func ValidateFormat() error {
+	if !GetFormat().isTemplate() {
+		return nil
+	}
+	_, err := compileTemplate()
+	return err
+}
+
+// templateRenderer is implemented by *JSONPath and *GoTemplate.
+type templateRenderer interface {
+	Execute(w io.Writer, data any) error
+}
+
+// compileTemplate compiles the jsonpath or go-template expression given
+// after "=" in the output flag.
+func compileTemplate() (templateRenderer, error) {
+	format := GetFormat()
+	_, expr, _ := strings.Cut(viper.GetString("output"), "=")
+	if expr == "" {
+		return nil, fmt.Errorf("%w: -o %s requires an expression, e.g. -o %s=...", ErrInvalidTemplate, format, format)
+	}
+
+	if format == FormatJSONPath {
+		jp, err := ParseJSONPath(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+		return jp, nil
+	}
+
+	if format == FormatGoTemplateFile {
+		text, err := os.ReadFile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading template file: %v", ErrInvalidTemplate, err)
+		}
+		expr = string(text)
+	}
+	tmpl, err := ParseTemplate(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return tmpl, nil
+}
+
+func printTemplate(w io.Writer, data any) error {
+	tmpl, err := compileTemplate()
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
 func printJSON(w io.Writer, data any) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(data)
 }
 
+// printNDJSON writes each element of a slice as one compact JSON object per
+// line. Any other value is written as a single line.
+func printNDJSON(w io.Writer, data any) error {
+	rv := reflect.ValueOf(data)
+	if rv.Kind() != reflect.Slice {
+		return writeJSONLine(w, data)
+	}
+	for i := range rv.Len() {
+		if err := writeJSONLine(w, rv.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSONLine(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
 func printYAML(w io.Writer, data any) error {
 	encoder := yaml.NewEncoder(w)
 	encoder.SetIndent(2)
 	return encoder.Encode(data)
 }
 
-// Table provides a simple table writer.
+// ListWriter streams the items of a list in the configured machine-readable
+// format, so large result sets can be written page by page. JSON output is a
+// single array, NDJSON output one line per item and YAML output a single
+// sequence. Template formats need the whole list, so items are collected and
+// rendered on Close.
+type ListWriter struct {
+	w         io.Writer
+	format    Format
+	count     int
+	collected []any
+}
+
+// NewListWriter creates a list writer for the configured format.
+func NewListWriter(w io.Writer) *ListWriter {
+	return &ListWriter{w: w, format: GetFormat()}
+}
+
+// Write appends items to the list.
+func (l *ListWriter) Write(items ...any) error {
+	for _, item := range items {
+		if err := l.writeItem(item); err != nil {
+			return err
+		}
+		l.count++
+	}
+	return nil
+}
+
+func (l *ListWriter) writeItem(item any) error {
+	if l.format.isTemplate() {
+		l.collected = append(l.collected, item)
+		return nil
+	}
+	if l.format == FormatNDJSON {
+		return writeJSONLine(l.w, item)
+	}
+	if l.format == FormatYAML {
+		// Encoding a one-element sequence yields a "- ..." entry that can be
+		// concatenated with the entries before it.
+		return printYAML(l.w, []any{item})
+	}
+
+	data, err := json.MarshalIndent(item, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if l.count == 0 {
+		sep = "[\n  "
+	}
+	if _, err := io.WriteString(l.w, sep); err != nil {
+		return err
+	}
+	_, err = l.w.Write(data)
+	return err
+}
+
+// Close terminates the list.
+func (l *ListWriter) Close() error {
+	var end string
+	switch {
+	case l.format.isTemplate():
+		if l.collected == nil {
+			l.collected = []any{}
+		}
+		return printTemplate(l.w, l.collected)
+	case l.format == FormatNDJSON:
+		return nil
+	case l.count == 0:
+		end = "[]\n"
+	case l.format == FormatYAML:
+		return nil
+	default:
+		end = "\n]\n"
+	}
+	_, err := io.WriteString(l.w, end)
+	return err
+}
+
+// Table provides a simple table writer. In csv and tsv formats rows are
+// written as delimited records with standard quoting instead.
 type Table struct {
 	w       *tabwriter.Writer
+	csv     *csv.Writer
 	headers []string
 }
 
 // NewTable creates a new table with the given headers.
 func NewTable(headers ...string) *Table {
-	t := &Table{
-		w:       tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0),
-		headers: headers,
+	t := &Table{headers: headers}
+	switch GetFormat() {
+	case FormatCSV:
+		t.csv = csv.NewWriter(os.Stdout)
+	case FormatTSV:
+		t.csv = csv.NewWriter(os.Stdout)
+		t.csv.Comma = '\t'
+	default:
+		t.w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	}
 	return t
 }
 
 // WriteHeader writes the table header.
 func (t *Table) WriteHeader() {
+	if t.csv != nil {
+		_ = t.csv.Write(t.headers)
+		return
+	}
 	for i, h := range t.headers {
 		if i > 0 {
 			fmt.Fprint(t.w, "\t")
@@ -96,6 +333,10 @@ func (t *Table) WriteHeader() {
 
 // WriteRow writes a row to the table.
 func (t *Table) WriteRow(values ...string) {
+	if t.csv != nil {
+		_ = t.csv.Write(values)
+		return
+	}
 	for i, v := range values {
 		if i > 0 {
 			fmt.Fprint(t.w, "\t")
@@ -107,6 +348,10 @@ func (t *Table) WriteRow(values ...string) {
 
 // Flush flushes the table output.
 func (t *Table) Flush() error {
+	if t.csv != nil {
+		t.csv.Flush()
+		return t.csv.Error()
+	}
 	return t.w.Flush()
 }
 
@@ -115,6 +360,33 @@ func IsQuiet() bool {
 	return viper.GetBool("quiet")
 }
 
+// VerboseLevel returns how verbose output should be: 0 by default, 1 for -v
+// and 2 for -vv. The environment and config file may set verbose to true or
+// to a level.
+func VerboseLevel() int {
+	v := viper.GetString("verbose")
+	if b, err := strconv.ParseBool(v); err == nil {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	level, _ := strconv.Atoi(v)
+	return level
+}
+
+// IsVerbose returns true if verbose mode is enabled.
+func IsVerbose() bool {
+	return VerboseLevel() > 0
+}
+
+// Verbose prints a diagnostic message (only in verbose mode).
+func Verbose(format string, args ...any) {
+	if IsVerbose() {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+}
+
 // Info prints an informational message (suppressed in quiet mode).
 func Info(format string, args ...any) {
 	if !IsQuiet() {
@@ -131,3 +403,31 @@ func Warn(format string, args ...any) {
 func Error(format string, args ...any) {
 	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
 }
+
+// ErrorEnvelope is the structured error document written for machine-readable formats.
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error" yaml:"error"`
+}
+
+// ErrorBody describes a failed command.
+type ErrorBody struct {
+	Code    string         `json:"code" yaml:"code"`
+	Message string         `json:"message" yaml:"message"`
+	Details map[string]any `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+// PrintError writes a command failure to stderr. JSON, NDJSON and YAML formats
+// get a structured error envelope; other formats get a plain "Error: ..." line.
+func PrintError(code, message string, details map[string]any) {
+	envelope := ErrorEnvelope{Error: ErrorBody{Code: code, Message: message, Details: details}}
+	switch GetFormat() {
+	case FormatJSON:
+		_ = printJSON(os.Stderr, envelope)
+	case FormatNDJSON:
+		_ = writeJSONLine(os.Stderr, envelope)
+	case FormatYAML:
+		_ = printYAML(os.Stderr, envelope)
+	default:
+		Error("%s", message)
+	}
+}
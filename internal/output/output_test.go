@@ -0,0 +1,68 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/getzep/zep-go/v3"
+	"github.com/spf13/viper"
+)
+
+func TestNDJSON(t *testing.T) {
+	viper.Set("output", "ndjson")
+	defer viper.Reset()
+
+	edges := []*zep.EntityEdge{
+		{UUID: "e1", Name: "WORKS_AT", Fact: "Alice works at Acme"},
+		{UUID: "e2", Name: "LIVES_IN", Fact: "Alice lives in Paris"},
+	}
+	wantLines := `{"created_at":"","fact":"Alice works at Acme","name":"WORKS_AT","source_node_uuid":"","target_node_uuid":"","uuid":"e1"}` + "\n" +
+		`{"created_at":"","fact":"Alice lives in Paris","name":"LIVES_IN","source_node_uuid":"","target_node_uuid":"","uuid":"e2"}` + "\n"
+
+	tests := []struct {
+		name  string
+		write func(*bytes.Buffer) error
+		want  string
+	}{
+		{
+			name:  "slice",
+			write: func(buf *bytes.Buffer) error { return Fprint(buf, edges) },
+			want:  wantLines,
+		},
+		{
+			name:  "single value",
+			write: func(buf *bytes.Buffer) error { return Fprint(buf, &zep.User{UserID: zep.String("alice")}) },
+			want:  `{"user_id":"alice"}` + "\n",
+		},
+		{
+			name: "list writer",
+			write: func(buf *bytes.Buffer) error {
+				lw := NewListWriter(buf)
+				for _, e := range edges {
+					if err := lw.Write(e); err != nil {
+						return err
+					}
+				}
+				return lw.Close()
+			},
+			want: wantLines,
+		},
+		{
+			name:  "empty list writer",
+			write: func(buf *bytes.Buffer) error { return NewListWriter(buf).Close() },
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.write(&buf); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// progressInterval limits how often a progress line is redrawn.
+const progressInterval = 100 * time.Millisecond
+
+// Progress reports running counts for a long operation on stderr, e.g.
+// "Exporting: 12 users, 40 threads". With a total it also shows a bar, e.g.
+// "Adding [#####     ] 50/100: 50 episodes". On a terminal the line is
+// redrawn in place as counts change; otherwise only the final counts are
+// printed by Done. Nothing is printed in quiet mode.
+type Progress struct {
+	mu      sync.Mutex
+	w       io.Writer
+	label   string
+	tty     bool
+	enabled bool
+	counts  map[string]int
+	order   []string
+	drawn   time.Time
+	total   int
+}
+
+// progressBarWidth is the number of characters in a progress bar.
+const progressBarWidth = 20
+
+// NewProgress creates a progress reporter with the given label.
+func NewProgress(label string) *Progress {
+	return &Progress{
+		w:       os.Stderr,
+		label:   label,
+		tty:     term.IsTerminal(int(os.Stderr.Fd())),
+		enabled: !IsQuiet(),
+		counts:  make(map[string]int),
+	}
+}
+
+// WithTotal sets the number of items the operation processes in all, which
+// the counts are shown against as a bar.
+func (p *Progress) WithTotal(total int) *Progress {
+	p.total = total
+	return p
+}
+
+// Add increases the count for name by n. Names are shown in the order they
+// are first added.
+func (p *Progress) Add(name string, n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.counts[name]; !ok {
+		p.order = append(p.order, name)
+	}
+	p.counts[name] += n
+
+	if p.enabled && p.tty && time.Since(p.drawn) >= progressInterval {
+		p.drawn = time.Now()
+		fmt.Fprintf(p.w, "\r\033[K%s", p.line())
+	}
+}
+
+// Count returns the current count for name.
+func (p *Progress) Count(name string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.counts[name]
+}
+
+// Done prints the final counts.
+func (p *Progress) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.enabled {
+		return
+	}
+	if p.tty {
+		fmt.Fprint(p.w, "\r\033[K")
+	}
+	fmt.Fprintln(p.w, p.line())
+}
+
+func (p *Progress) line() string {
+	parts := make([]string, len(p.order))
+	for i, name := range p.order {
+		parts[i] = fmt.Sprintf("%d %s", p.counts[name], name)
+	}
+	if p.total > 0 {
+		done := 0
+		for _, n := range p.counts {
+			done += n
+		}
+		filled := min(done, p.total) * progressBarWidth / p.total
+		bar := strings.Repeat("#", filled) + strings.Repeat(" ", progressBarWidth-filled)
+		label := fmt.Sprintf("%s [%s] %d/%d", p.label, bar, done, p.total)
+		if len(parts) == 0 {
+			return label
+		}
+		return label + ": " + strings.Join(parts, ", ")
+	}
+	if len(parts) == 0 {
+		return p.label + ": nothing to do"
+	}
+	return p.label + ": " + strings.Join(parts, ", ")
+}
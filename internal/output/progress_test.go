@@ -0,0 +1,35 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestProgressLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		total int
+		add   map[string]int
+		want  string
+	}{
+		{"nothing", 0, nil, "Adding: nothing to do\n"},
+		{"counts", 0, map[string]int{"users": 2}, "Adding: 2 users\n"},
+		{"empty bar", 4, nil, "Adding [                    ] 0/4\n"},
+		{"bar", 4, map[string]int{"episodes": 1}, "Adding [#####               ] 1/4: 1 episodes\n"},
+		{"full bar", 4, map[string]int{"episodes": 4}, "Adding [####################] 4/4: 4 episodes\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := &Progress{w: &buf, label: "Adding", enabled: true, counts: make(map[string]int)}
+			p.WithTotal(tt.total)
+			for name, n := range tt.add {
+				p.Add(name, n)
+			}
+			p.Done()
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
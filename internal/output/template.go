@@ -0,0 +1,142 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// GoTemplate is a compiled -o go-template template.
+type GoTemplate struct {
+	tmpl *template.Template
+}
+
+// ParseTemplate compiles a Go template for -o go-template. Like jsonpath,
+// templates are executed against the JSON form of the output, so fields use
+// JSON names such as {{range .users}}{{.user_id}}{{end}}.
+func ParseTemplate(text string) (*GoTemplate, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &GoTemplate{tmpl: tmpl}, nil
+}
+
+// Execute evaluates the template against data and writes the result to w.
+func (t *GoTemplate) Execute(w io.Writer, data any) error {
+	normalized, err := toJSONValue(data)
+	if err != nil {
+		return err
+	}
+	return t.tmpl.Execute(w, normalized)
+}
+
+var templateFuncs = template.FuncMap{
+	"items":    templateItems,
+	"deref":    templateDeref,
+	"default":  templateDefault,
+	"join":     templateJoin,
+	"json":     templateJSON,
+	"truncate": templateTruncate,
+}
+
+// indirect follows pointers and interfaces, returning the invalid Value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// templateItems returns the list inside a value: a slice is returned as-is and
+// a list response such as {"users": [...], "total_count": 2} yields its first
+// non-empty list field in key order (users, edges, messages, ...). This lets
+// the same template range over paged and single-page output.
+func templateItems(v any) []any {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return sliceItems(rv)
+	case reflect.Map:
+		if m, ok := rv.Interface().(map[string]any); ok {
+			for _, key := range slices.Sorted(maps.Keys(m)) {
+				if f, ok := m[key].([]any); ok && len(f) > 0 {
+					return f
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func sliceItems(rv reflect.Value) []any {
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
+
+// templateDeref returns the value a pointer refers to, or "" for nil, so
+// optional fields like .email print cleanly when they are missing.
+func templateDeref(v any) any {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return ""
+	}
+	return rv.Interface()
+}
+
+// templateDefault returns def when v is missing, null or the zero value.
+func templateDefault(def, v any) any {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.IsZero() {
+		return def
+	}
+	if n, ok := v.(json.Number); ok {
+		if f, err := n.Float64(); err == nil && f == 0 {
+			return def
+		}
+	}
+	return rv.Interface()
+}
+
+// templateJoin joins the elements of a slice with sep.
+func templateJoin(sep string, v any) string {
+	items := templateItems(v)
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprint(templateDeref(item))
+	}
+	return strings.Join(parts, sep)
+}
+
+// templateJSON renders v as compact JSON.
+func templateJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// templateTruncate shortens s to n characters.
+func templateTruncate(n int, s any) string {
+	runes := []rune(fmt.Sprint(templateDeref(s)))
+	if len(runes) <= n {
+		return string(runes)
+	}
+	return string(runes[:n]) + "..."
+}
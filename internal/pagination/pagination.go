@@ -0,0 +1,109 @@
+// Package pagination provides iterators over the Zep API's paginated list endpoints.
+package pagination
+
+import "context"
+
+// Iterator walks a paginated API one page at a time so results can be
+// streamed without loading every page into memory.
+type Iterator[T any] struct {
+	fetch    func(ctx context.Context) ([]T, error)
+	maxItems int
+	seen     int
+	done     bool
+}
+
+// NewPageIterator returns an iterator over a page-number API such as
+// User.ListOrdered, Graph.ListAll or Thread.ListAll. Iteration starts at
+// startPage and stops at the first page shorter than pageSize.
+func NewPageIterator[T any](startPage, pageSize int, fetch func(ctx context.Context, pageNumber, pageSize int) ([]T, error)) *Iterator[T] {
+	page := max(startPage, 1)
+	it := &Iterator[T]{}
+	it.fetch = func(ctx context.Context) ([]T, error) {
+		items, err := fetch(ctx, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		page++
+		if len(items) < pageSize {
+			it.done = true
+		}
+		return items, nil
+	}
+	return it
+}
+
+// NewCursorIterator returns an iterator over a UUID-cursor API such as
+// Graph.Node.GetByUserID or Graph.Edge.GetByGraphID. Each request passes the
+// cursor of the last item from the previous page, starting from startCursor.
+// Iteration stops at the first page shorter than limit.
+func NewCursorIterator[T any](startCursor string, limit int, fetch func(ctx context.Context, cursor string, limit int) ([]T, error), cursorOf func(T) string) *Iterator[T] {
+	cursor := startCursor
+	it := &Iterator[T]{}
+	it.fetch = func(ctx context.Context) ([]T, error) {
+		items, err := fetch(ctx, cursor, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) < limit || len(items) == 0 {
+			it.done = true
+		} else {
+			cursor = cursorOf(items[len(items)-1])
+		}
+		return items, nil
+	}
+	return it
+}
+
+// WithMaxItems limits the total number of items returned. Zero means no limit.
+func (it *Iterator[T]) WithMaxItems(n int) *Iterator[T] {
+	it.maxItems = n
+	return it
+}
+
+// Next returns the next page of items. It returns a nil slice once the
+// results are exhausted or the item limit has been reached.
+func (it *Iterator[T]) Next(ctx context.Context) ([]T, error) {
+	for !it.done {
+		items, err := it.fetch(ctx)
+		if err != nil {
+			it.done = true
+			return nil, err
+		}
+		if it.maxItems > 0 && it.seen+len(items) >= it.maxItems {
+			items = items[:it.maxItems-it.seen]
+			it.done = true
+		}
+		it.seen += len(items)
+		if len(items) > 0 {
+			return items, nil
+		}
+	}
+	return nil, nil
+}
+
+// ForEachPage calls fn for each page of items until the results are
+// exhausted or fn returns an error.
+func (it *Iterator[T]) ForEachPage(ctx context.Context, fn func(items []T) error) error {
+	for {
+		items, err := it.Next(ctx)
+		if err != nil {
+			return err
+		}
+		if items == nil {
+			return nil
+		}
+		if err := fn(items); err != nil {
+			return err
+		}
+	}
+}
+
+// All fetches every remaining page and returns the items in order.
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	err := it.ForEachPage(ctx, func(items []T) error {
+		all = append(all, items...)
+		return nil
+	})
+	return all, err
+}
@@ -0,0 +1,130 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func makeItems(n int) []string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf("item-%02d", i)
+	}
+	return items
+}
+
+func collect[T any](t *testing.T, it *Iterator[T]) ([]T, int) {
+	t.Helper()
+	var all []T
+	pages := 0
+	err := it.ForEachPage(context.Background(), func(items []T) error {
+		pages++
+		all = append(all, items...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return all, pages
+}
+
+func TestPageIterator(t *testing.T) {
+	data := makeItems(25)
+	var requested []int
+	fetch := func(_ context.Context, page, size int) ([]string, error) {
+		requested = append(requested, page)
+		start := (page - 1) * size
+		if start >= len(data) {
+			return nil, nil
+		}
+		return data[start:min(start+size, len(data))], nil
+	}
+
+	tests := []struct {
+		name      string
+		startPage int
+		pageSize  int
+		maxItems  int
+		want      []string
+		wantPages []int
+	}{
+		{name: "all pages", startPage: 1, pageSize: 10, want: data, wantPages: []int{1, 2, 3}},
+		{name: "exact multiple", startPage: 1, pageSize: 5, want: data, wantPages: []int{1, 2, 3, 4, 5, 6}},
+		{name: "max items", startPage: 1, pageSize: 10, maxItems: 12, want: data[:12], wantPages: []int{1, 2}},
+		{name: "max items on page boundary", startPage: 1, pageSize: 10, maxItems: 10, want: data[:10], wantPages: []int{1}},
+		{name: "start page", startPage: 3, pageSize: 10, want: data[20:], wantPages: []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested = nil
+			it := NewPageIterator(tt.startPage, tt.pageSize, fetch).WithMaxItems(tt.maxItems)
+			got, _ := collect(t, it)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(requested, tt.wantPages) {
+				t.Errorf("requested pages %v, want %v", requested, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestCursorIterator(t *testing.T) {
+	data := makeItems(7)
+	var cursors []string
+	fetch := func(_ context.Context, cursor string, limit int) ([]string, error) {
+		cursors = append(cursors, cursor)
+		start := 0
+		for i, item := range data {
+			if item == cursor {
+				start = i + 1
+			}
+		}
+		return data[start:min(start+limit, len(data))], nil
+	}
+	identity := func(s string) string { return s }
+
+	got, pages := collect(t, NewCursorIterator("", 3, fetch, identity))
+	if !reflect.DeepEqual(got, data) {
+		t.Errorf("got %v, want %v", got, data)
+	}
+	if pages != 3 {
+		t.Errorf("pages = %d, want 3", pages)
+	}
+	if want := []string{"", "item-02", "item-05"}; !reflect.DeepEqual(cursors, want) {
+		t.Errorf("cursors = %v, want %v", cursors, want)
+	}
+}
+
+func TestIteratorError(t *testing.T) {
+	wantErr := errors.New("boom")
+	it := NewPageIterator(1, 10, func(context.Context, int, int) ([]string, error) {
+		return nil, wantErr
+	})
+	if _, err := it.Next(context.Background()); !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if items, err := it.Next(context.Background()); items != nil || err != nil {
+		t.Errorf("Next after error = %v, %v; want nil, nil", items, err)
+	}
+}
+
+func TestAll(t *testing.T) {
+	data := makeItems(12)
+	it := NewPageIterator(1, 5, func(_ context.Context, page, size int) ([]string, error) {
+		start := (page - 1) * size
+		return data[start:min(start+size, len(data))], nil
+	}).WithMaxItems(11)
+
+	got, err := it.All(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, data[:11]) {
+		t.Errorf("got %v, want %v", got, data[:11])
+	}
+}
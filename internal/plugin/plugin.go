@@ -0,0 +1,118 @@
+// Package plugin finds zepctl plugins: executables named zepctl-<name> in
+// ~/.zepctl/plugins or on PATH, which zepctl runs as "zepctl <name>".
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Prefix is the file name prefix that marks an executable as a plugin.
+const Prefix = "zepctl-"
+
+// Plugin is an executable that provides a zepctl subcommand.
+type Plugin struct {
+	// Name is the subcommand, e.g. "foo" for zepctl-foo. Dashes in the name
+	// are nested subcommands, so zepctl-foo-bar runs as "zepctl foo bar".
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Shadowed lists executables with the same name that are ignored
+	// because Path comes first in the search order.
+	Shadowed []string `json:"shadowed,omitempty"`
+}
+
+// Dirs returns the directories searched for plugins in order:
+// ~/.zepctl/plugins, then each directory on PATH.
+func Dirs() []string {
+	var dirs []string
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".zepctl", "plugins"))
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			dir = "."
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// List returns every plugin in Dirs, sorted by name. Missing or unreadable
+// directories are skipped.
+func List() []Plugin {
+	var plugins []Plugin
+	index := make(map[string]int)
+	for _, dir := range Dirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			if i, seen := index[name]; seen {
+				if plugins[i].Path != path {
+					plugins[i].Shadowed = append(plugins[i].Shadowed, path)
+				}
+				continue
+			}
+			index[name] = len(plugins)
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+	slices.SortFunc(plugins, func(a, b Plugin) int { return strings.Compare(a.Name, b.Name) })
+	return plugins
+}
+
+// Lookup finds the plugin for a command line. The longest run of leading
+// arguments joined with dashes that names a plugin wins, so for
+// "foo bar baz" zepctl-foo-bar-baz is tried before zepctl-foo-bar and
+// zepctl-foo. It returns the plugin and the arguments to pass to it.
+func Lookup(args []string) (Plugin, []string, bool) {
+	n := 0
+	for n < len(args) && !strings.HasPrefix(args[n], "-") {
+		n++
+	}
+	plugins := List()
+	for i := n; i > 0; i-- {
+		name := strings.Join(args[:i], "-")
+		j := slices.IndexFunc(plugins, func(p Plugin) bool { return p.Name == name })
+		if j >= 0 {
+			return plugins[j], args[i:], true
+		}
+	}
+	return Plugin{}, nil, false
+}
+
+// pluginName returns the plugin name for a file name, if it is one.
+func pluginName(file string) (string, bool) {
+	name, ok := strings.CutPrefix(file, Prefix)
+	if !ok {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name, name != ""
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0o111 != 0
+}
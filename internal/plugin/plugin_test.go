@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// install writes an executable script named file into dir.
+func install(t *testing.T, dir, file string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(dir, file)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestListAndLookup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are found by file extension on Windows")
+	}
+
+	home, bin := t.TempDir(), t.TempDir()
+	userDir := filepath.Join(home, ".zepctl", "plugins")
+	if err := os.MkdirAll(userDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", bin)
+
+	userHello := install(t, userDir, "zepctl-hello", 0o755)
+	pathHello := install(t, bin, "zepctl-hello", 0o755)
+	nested := install(t, bin, "zepctl-hello-world", 0o755)
+	install(t, bin, "zepctl-notexec", 0o644)
+	install(t, bin, "other-tool", 0o755)
+
+	want := []Plugin{
+		{Name: "hello", Path: userHello, Shadowed: []string{pathHello}},
+		{Name: "hello-world", Path: nested},
+	}
+	got := List()
+	if !slices.EqualFunc(got, want, func(a, b Plugin) bool {
+		return a.Name == b.Name && a.Path == b.Path && slices.Equal(a.Shadowed, b.Shadowed)
+	}) {
+		t.Errorf("List() = %+v, want %+v", got, want)
+	}
+
+	tests := []struct {
+		args     []string
+		wantPath string
+		wantArgs []string
+	}{
+		{[]string{"hello"}, userHello, []string{}},
+		{[]string{"hello", "--name", "x"}, userHello, []string{"--name", "x"}},
+		{[]string{"hello", "world", "x"}, nested, []string{"x"}},
+		{[]string{"hello", "there"}, userHello, []string{"there"}},
+		{[]string{"hello", "-v", "world"}, userHello, []string{"-v", "world"}},
+		{[]string{"notexec"}, "", nil},
+		{[]string{"missing"}, "", nil},
+	}
+	for _, tt := range tests {
+		p, args, ok := Lookup(tt.args)
+		if ok != (tt.wantPath != "") || p.Path != tt.wantPath || !slices.Equal(args, tt.wantArgs) {
+			t.Errorf("Lookup(%q) = %q, %q, %v; want %q, %q", tt.args, p.Path, args, ok, tt.wantPath, tt.wantArgs)
+		}
+	}
+}
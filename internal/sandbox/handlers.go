@@ -0,0 +1,1464 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// handlerFunc handles an emulated API request and returns the value to encode
+// as the JSON response body.
+type handlerFunc func(r *http.Request) (any, error)
+
+// routes registers every emulated endpoint. Paths mirror the hosted Zep API.
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	handle := func(pattern string, mutates bool, fn handlerFunc) {
+		method, path, _ := strings.Cut(pattern, " ")
+		mux.HandleFunc(method+" "+BasePath+path, s.wrap(mutates, fn))
+	}
+
+	// Users
+	handle("POST /users", true, s.createUser)
+	handle("GET /users-ordered", false, s.listUsers)
+	handle("GET /users/{userId}", false, s.getUser)
+	handle("PATCH /users/{userId}", true, s.updateUser)
+	handle("DELETE /users/{userId}", true, s.deleteUser)
+	handle("GET /users/{userId}/threads", false, s.listUserThreads)
+	handle("GET /users/{userId}/node", false, s.getUserNode)
+	handle("GET /user-summary-instructions", false, s.listInstructions)
+	handle("POST /user-summary-instructions", true, s.addInstructions)
+	handle("DELETE /user-summary-instructions", true, s.deleteInstructions)
+
+	// Threads
+	handle("GET /threads", false, s.listThreads)
+	handle("POST /threads", true, s.createThread)
+	handle("DELETE /threads/{threadId}", true, s.deleteThread)
+	handle("GET /threads/{threadId}/messages", false, s.getMessages)
+	handle("POST /threads/{threadId}/messages", true, s.addMessages)
+	handle("POST /threads/{threadId}/messages-batch", true, s.addMessagesBatch)
+	handle("GET /threads/{threadId}/context", false, s.getThreadContext)
+
+	// Graphs
+	handle("POST /graph", true, s.addData)
+	handle("POST /graph-batch", true, s.addDataBatch)
+	handle("POST /graph/create", true, s.createGraph)
+	handle("GET /graph/list-all", false, s.listGraphs)
+	handle("POST /graph/clone", true, s.cloneGraph)
+	handle("POST /graph/add-fact-triple", true, s.addFactTriple)
+	handle("POST /graph/search", false, s.search)
+	handle("GET /graph/{graphId}", false, s.getGraph)
+	handle("PATCH /graph/{graphId}", true, s.updateGraph)
+	handle("DELETE /graph/{graphId}", true, s.deleteGraph)
+	handle("GET /entity-types", false, s.listEntityTypes)
+	handle("PUT /entity-types", true, s.setEntityTypes)
+
+	// Nodes
+	handle("POST /graph/node/user/{userId}", false, s.listNodes)
+	handle("POST /graph/node/graph/{graphId}", false, s.listNodes)
+	handle("GET /graph/node/{uuid}", false, s.getNode)
+	handle("DELETE /graph/node/{uuid}", true, s.deleteNode)
+	handle("GET /graph/node/{uuid}/entity-edges", false, s.getNodeEdges)
+	handle("GET /graph/node/{uuid}/episodes", false, s.getNodeEpisodes)
+
+	// Edges
+	handle("POST /graph/edge/user/{userId}", false, s.listEdges)
+	handle("POST /graph/edge/graph/{graphId}", false, s.listEdges)
+	handle("GET /graph/edge/{uuid}", false, s.getEdge)
+	handle("DELETE /graph/edge/{uuid}", true, s.deleteEdge)
+
+	// Episodes. "/graph/episodes/user/{userId}" and
+	// "/graph/episodes/{uuid}/mentions" overlap, so both are served by one
+	// dispatcher.
+	handle("GET /graph/episodes/{first}/{second}", false, s.episodesSubresource)
+	handle("GET /graph/episodes/{uuid}", false, s.getEpisode)
+	handle("DELETE /graph/episodes/{uuid}", true, s.deleteEpisode)
+
+	// Tasks and projects
+	handle("GET /tasks/{taskId}", false, s.getTask)
+	handle("GET /projects/info", false, s.getProject)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, errNotFound("sandbox does not emulate %s %s", r.Method, r.URL.Path))
+	})
+	return mux
+}
+
+// wrap serializes access to the state and persists it after requests that
+// change it.
+func (s *Server) wrap(mutates bool, fn handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		result, err := fn(r)
+		if err == nil && mutates {
+			err = s.save()
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func success(message string) map[string]string {
+	return map[string]string{"message": message}
+}
+
+// queryInt returns the first of the named query parameters that is set,
+// or def if none is.
+func queryInt(r *http.Request, def int, names ...string) (int, error) {
+	for _, name := range names {
+		v := r.URL.Query().Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, errBadRequest("invalid %s: %q", name, v)
+		}
+		return n, nil
+	}
+	return def, nil
+}
+
+// paginate returns page pageNumber (1-based) of items.
+func paginate[T any](items []T, pageNumber, pageSize int) []T {
+	if pageNumber < 1 {
+		pageNumber = 1
+	}
+	if pageSize < 1 {
+		return items
+	}
+	start := (pageNumber - 1) * pageSize
+	if start >= len(items) {
+		return []T{}
+	}
+	return items[start:min(start+pageSize, len(items))]
+}
+
+// sortedBySeq returns the values of m ordered by insertion.
+func sortedBySeq[T any](m map[string]*T, seq func(*T) int64) []*T {
+	out := make([]*T, 0, len(m))
+	for _, v := range m {
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return seq(out[i]) < seq(out[j]) })
+	return out
+}
+
+// graphKey resolves the graph addressed by a request's userId or graphId
+// path values, failing if that user or graph does not exist.
+func (s *Server) graphKey(r *http.Request) (string, error) {
+	if userID := r.PathValue("userId"); userID != "" {
+		return s.userGraph(userID)
+	}
+	return s.standaloneGraph(r.PathValue("graphId"))
+}
+
+func (s *Server) userGraph(userID string) (string, error) {
+	if _, ok := s.state.Users[userID]; !ok {
+		return "", errNotFound("user not found: %s", userID)
+	}
+	return userGraphKey(userID), nil
+}
+
+func (s *Server) standaloneGraph(graphID string) (string, error) {
+	if _, ok := s.state.Graphs[graphID]; !ok {
+		return "", errNotFound("graph not found: %s", graphID)
+	}
+	return standaloneGraphKey(graphID), nil
+}
+
+// targetGraph resolves a graph from optional user_id and graph_id request
+// fields, exactly one of which must be set.
+func (s *Server) targetGraph(userID, graphID *string) (string, error) {
+	switch {
+	case userID != nil && *userID != "" && graphID != nil && *graphID != "":
+		return "", errBadRequest("only one of user_id or graph_id may be set")
+	case userID != nil && *userID != "":
+		return s.userGraph(*userID)
+	case graphID != nil && *graphID != "":
+		return s.standaloneGraph(*graphID)
+	default:
+		return "", errBadRequest("one of user_id or graph_id is required")
+	}
+}
+
+func (s *Server) completedTask(taskType string) *task {
+	ts := now()
+	t := &task{
+		TaskID:      newUUID(),
+		Type:        taskType,
+		Status:      "completed",
+		CreatedAt:   ts,
+		StartedAt:   ts,
+		CompletedAt: ts,
+	}
+	s.state.Tasks[t.TaskID] = t
+	return t
+}
+
+// Users
+
+type userRequest struct {
+	UserID    string         `json:"user_id"`
+	Email     *string        `json:"email"`
+	FirstName *string        `json:"first_name"`
+	LastName  *string        `json:"last_name"`
+	Metadata  map[string]any `json:"metadata"`
+}
+
+func (s *Server) createUser(r *http.Request) (any, error) {
+	var req userRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.UserID == "" {
+		return nil, errBadRequest("user_id is required")
+	}
+	if _, ok := s.state.Users[req.UserID]; ok {
+		return nil, errBadRequest("user already exists: %s", req.UserID)
+	}
+
+	ts := now()
+	rec := &userRecord{
+		Seq: s.state.nextSeq(),
+		User: user{
+			UUID:        newUUID(),
+			UserID:      req.UserID,
+			Email:       req.Email,
+			FirstName:   req.FirstName,
+			LastName:    req.LastName,
+			Metadata:    req.Metadata,
+			ProjectUUID: s.state.ProjectUUID,
+			CreatedAt:   ts,
+			UpdatedAt:   ts,
+		},
+	}
+	s.state.Users[req.UserID] = rec
+
+	// Zep seeds every user graph with a node representing the user.
+	s.findOrCreateNode(userGraphKey(req.UserID), userNodeName(&rec.User), []string{"Entity", "User"})
+	return rec.User, nil
+}
+
+func userNodeName(u *user) string {
+	var parts []string
+	if u.FirstName != nil && *u.FirstName != "" {
+		parts = append(parts, *u.FirstName)
+	}
+	if u.LastName != nil && *u.LastName != "" {
+		parts = append(parts, *u.LastName)
+	}
+	if len(parts) == 0 {
+		return u.UserID
+	}
+	return strings.Join(parts, " ")
+}
+
+func (s *Server) listUsers(r *http.Request) (any, error) {
+	pageNumber, err := queryInt(r, 1, "pageNumber", "page_number")
+	if err != nil {
+		return nil, err
+	}
+	pageSize, err := queryInt(r, 0, "pageSize", "page_size")
+	if err != nil {
+		return nil, err
+	}
+
+	all := sortedBySeq(s.state.Users, func(u *userRecord) int64 { return u.Seq })
+	page := paginate(all, pageNumber, pageSize)
+	users := make([]user, len(page))
+	for i, u := range page {
+		users[i] = u.User
+	}
+	return map[string]any{"users": users, "row_count": len(users), "total_count": len(all)}, nil
+}
+
+func (s *Server) lookupUser(userID string) (*userRecord, error) {
+	rec, ok := s.state.Users[userID]
+	if !ok {
+		return nil, errNotFound("user not found: %s", userID)
+	}
+	return rec, nil
+}
+
+func (s *Server) getUser(r *http.Request) (any, error) {
+	rec, err := s.lookupUser(r.PathValue("userId"))
+	if err != nil {
+		return nil, err
+	}
+	return rec.User, nil
+}
+
+func (s *Server) updateUser(r *http.Request) (any, error) {
+	rec, err := s.lookupUser(r.PathValue("userId"))
+	if err != nil {
+		return nil, err
+	}
+	var req userRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Email != nil {
+		rec.User.Email = req.Email
+	}
+	if req.FirstName != nil {
+		rec.User.FirstName = req.FirstName
+	}
+	if req.LastName != nil {
+		rec.User.LastName = req.LastName
+	}
+	if req.Metadata != nil {
+		rec.User.Metadata = req.Metadata
+	}
+	rec.User.UpdatedAt = now()
+	return rec.User, nil
+}
+
+func (s *Server) deleteUser(r *http.Request) (any, error) {
+	userID := r.PathValue("userId")
+	if _, err := s.lookupUser(userID); err != nil {
+		return nil, err
+	}
+
+	delete(s.state.Users, userID)
+	delete(s.state.Instructions, userID)
+	for id, t := range s.state.Threads {
+		if t.Thread.UserID == userID {
+			delete(s.state.Threads, id)
+		}
+	}
+	s.deleteGraphData(userGraphKey(userID))
+	return success("Deleted"), nil
+}
+
+func (s *Server) listUserThreads(r *http.Request) (any, error) {
+	userID := r.PathValue("userId")
+	if _, err := s.lookupUser(userID); err != nil {
+		return nil, err
+	}
+
+	threads := []thread{}
+	for _, t := range sortedBySeq(s.state.Threads, func(t *threadRecord) int64 { return t.Seq }) {
+		if t.Thread.UserID == userID {
+			threads = append(threads, t.Thread)
+		}
+	}
+	return threads, nil
+}
+
+func (s *Server) getUserNode(r *http.Request) (any, error) {
+	key, err := s.userGraph(r.PathValue("userId"))
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range s.graphNodes(key) {
+		if slices.Contains(n.Node.Labels, "User") {
+			return map[string]any{"node": n.Node}, nil
+		}
+	}
+	return map[string]any{}, nil
+}
+
+// Summary instructions. Project-wide instructions are stored under the empty
+// user ID.
+
+func (s *Server) listInstructions(r *http.Request) (any, error) {
+	userID := r.URL.Query().Get("user_id")
+	if _, ok := s.state.Users[userID]; userID != "" && !ok {
+		return nil, errNotFound("user not found: %s", userID)
+	}
+	instructions := s.state.Instructions[userID]
+	if instructions == nil {
+		instructions = []*userInstruction{}
+	}
+	return map[string]any{"instructions": instructions}, nil
+}
+
+func (s *Server) addInstructions(r *http.Request) (any, error) {
+	var req struct {
+		Instructions []*userInstruction `json:"instructions"`
+		UserIDs      []string           `json:"user_ids"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if len(req.Instructions) == 0 {
+		return nil, errBadRequest("instructions are required")
+	}
+
+	userIDs := req.UserIDs
+	if len(userIDs) == 0 {
+		userIDs = []string{""}
+	}
+	for _, userID := range userIDs {
+		existing := s.state.Instructions[userID]
+		for _, in := range req.Instructions {
+			existing = slices.DeleteFunc(existing, func(e *userInstruction) bool { return e.Name == in.Name })
+			existing = append(existing, &userInstruction{Name: in.Name, Text: in.Text})
+		}
+		s.state.Instructions[userID] = existing
+	}
+	return success("Added"), nil
+}
+
+func (s *Server) deleteInstructions(r *http.Request) (any, error) {
+	var req struct {
+		InstructionNames []string `json:"instruction_names"`
+		UserIDs          []string `json:"user_ids"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	userIDs := req.UserIDs
+	if len(userIDs) == 0 {
+		userIDs = []string{""}
+	}
+	for _, userID := range userIDs {
+		if len(req.InstructionNames) == 0 {
+			delete(s.state.Instructions, userID)
+			continue
+		}
+		s.state.Instructions[userID] = slices.DeleteFunc(s.state.Instructions[userID], func(e *userInstruction) bool {
+			return slices.Contains(req.InstructionNames, e.Name)
+		})
+	}
+	return success("Deleted"), nil
+}
+
+// Threads
+
+func (s *Server) listThreads(r *http.Request) (any, error) {
+	pageNumber, err := queryInt(r, 1, "page_number", "pageNumber")
+	if err != nil {
+		return nil, err
+	}
+	pageSize, err := queryInt(r, 0, "page_size", "pageSize")
+	if err != nil {
+		return nil, err
+	}
+
+	all := sortedBySeq(s.state.Threads, func(t *threadRecord) int64 { return t.Seq })
+	if r.URL.Query().Get("asc") == "false" {
+		slices.Reverse(all)
+	}
+	page := paginate(all, pageNumber, pageSize)
+	threads := make([]thread, len(page))
+	for i, t := range page {
+		threads[i] = t.Thread
+	}
+	return map[string]any{"threads": threads, "response_count": len(threads), "total_count": len(all)}, nil
+}
+
+func (s *Server) createThread(r *http.Request) (any, error) {
+	var req struct {
+		ThreadID string `json:"thread_id"`
+		UserID   string `json:"user_id"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.ThreadID == "" {
+		return nil, errBadRequest("thread_id is required")
+	}
+	if _, err := s.lookupUser(req.UserID); err != nil {
+		return nil, err
+	}
+	if _, ok := s.state.Threads[req.ThreadID]; ok {
+		return nil, errBadRequest("thread already exists: %s", req.ThreadID)
+	}
+
+	rec := &threadRecord{
+		Seq: s.state.nextSeq(),
+		Thread: thread{
+			UUID:        newUUID(),
+			ThreadID:    req.ThreadID,
+			UserID:      req.UserID,
+			ProjectUUID: s.state.ProjectUUID,
+			CreatedAt:   now(),
+		},
+		Messages: []*message{},
+	}
+	s.state.Threads[req.ThreadID] = rec
+	return rec.Thread, nil
+}
+
+func (s *Server) lookupThread(threadID string) (*threadRecord, error) {
+	rec, ok := s.state.Threads[threadID]
+	if !ok {
+		return nil, errNotFound("thread not found: %s", threadID)
+	}
+	return rec, nil
+}
+
+func (s *Server) deleteThread(r *http.Request) (any, error) {
+	threadID := r.PathValue("threadId")
+	if _, err := s.lookupThread(threadID); err != nil {
+		return nil, err
+	}
+	delete(s.state.Threads, threadID)
+	return success("Deleted"), nil
+}
+
+// getMessages returns a thread's messages. lastn returns the most recent
+// messages; otherwise cursor is the number of messages to skip.
+func (s *Server) getMessages(r *http.Request) (any, error) {
+	rec, err := s.lookupThread(r.PathValue("threadId"))
+	if err != nil {
+		return nil, err
+	}
+	lastn, err := queryInt(r, 0, "lastn")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := queryInt(r, 0, "limit")
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := queryInt(r, 0, "cursor")
+	if err != nil {
+		return nil, err
+	}
+
+	messages := rec.Messages
+	switch {
+	case lastn > 0:
+		messages = messages[max(0, len(messages)-lastn):]
+	default:
+		messages = messages[min(cursor, len(messages)):]
+		if limit > 0 && len(messages) > limit {
+			messages = messages[:limit]
+		}
+	}
+	return map[string]any{"messages": messages, "row_count": len(messages), "total_count": len(rec.Messages)}, nil
+}
+
+type addMessagesRequest struct {
+	Messages []*message `json:"messages"`
+}
+
+func (s *Server) appendMessages(r *http.Request) (*threadRecord, []string, error) {
+	rec, err := s.lookupThread(r.PathValue("threadId"))
+	if err != nil {
+		return nil, nil, err
+	}
+	var req addMessagesRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, nil, err
+	}
+	if len(req.Messages) == 0 {
+		return nil, nil, errBadRequest("messages are required")
+	}
+
+	key := userGraphKey(rec.Thread.UserID)
+	uuids := make([]string, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		if m.Content == "" || m.Role == "" {
+			return nil, nil, errBadRequest("messages require content and role")
+		}
+		m.UUID = newUUID()
+		m.Processed = true
+		if m.CreatedAt == "" {
+			m.CreatedAt = now()
+		}
+		rec.Messages = append(rec.Messages, m)
+		uuids = append(uuids, m.UUID)
+
+		role := m.Role
+		ep := s.addEpisode(key, m.Content, "message", nil, m.CreatedAt)
+		ep.Episode.RoleType = &role
+		ep.Episode.Role = m.Name
+	}
+	return rec, uuids, nil
+}
+
+func (s *Server) addMessages(r *http.Request) (any, error) {
+	_, uuids, err := s.appendMessages(r)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"message_uuids": uuids}, nil
+}
+
+func (s *Server) addMessagesBatch(r *http.Request) (any, error) {
+	_, uuids, err := s.appendMessages(r)
+	if err != nil {
+		return nil, err
+	}
+	t := s.completedTask("add_messages_batch")
+	return map[string]any{"message_uuids": uuids, "task_id": t.TaskID}, nil
+}
+
+// getThreadContext renders the facts and entities of the thread user's graph
+// in the same shape as Zep's context block.
+func (s *Server) getThreadContext(r *http.Request) (any, error) {
+	rec, err := s.lookupThread(r.PathValue("threadId"))
+	if err != nil {
+		return nil, err
+	}
+	key := userGraphKey(rec.Thread.UserID)
+
+	var b strings.Builder
+	b.WriteString("FACTS and ENTITIES represent relevant context to the current conversation.\n\n")
+	b.WriteString("<FACTS>\n")
+	for _, e := range s.graphEdges(key) {
+		b.WriteString("  - " + e.Edge.Fact + "\n")
+	}
+	b.WriteString("</FACTS>\n\n<ENTITIES>\n")
+	for _, n := range s.graphNodes(key) {
+		b.WriteString("  - " + n.Node.Name)
+		if n.Node.Summary != "" {
+			b.WriteString(": " + n.Node.Summary)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("</ENTITIES>")
+	return map[string]any{"context": b.String()}, nil
+}
+
+// Graphs
+
+type episodeData struct {
+	Data              string  `json:"data"`
+	Type              string  `json:"type"`
+	CreatedAt         *string `json:"created_at"`
+	SourceDescription *string `json:"source_description"`
+}
+
+// maxEpisodeLength is the most characters of data the API accepts in one
+// episode.
+const maxEpisodeLength = 10000
+
+func (d *episodeData) validate() error {
+	if d.Data == "" {
+		return errBadRequest("data is required")
+	}
+	if n := utf8.RuneCountInString(d.Data); n > maxEpisodeLength {
+		return errBadRequest("data exceeds maximum length of %d characters (got %d)", maxEpisodeLength, n)
+	}
+	switch d.Type {
+	case "text", "json", "message":
+		return nil
+	default:
+		return errBadRequest("invalid type %q: must be text, json, or message", d.Type)
+	}
+}
+
+func (s *Server) addEpisode(key, content, source string, sourceDescription *string, createdAt string) *episodeRecord {
+	if createdAt == "" {
+		createdAt = now()
+	}
+	rec := &episodeRecord{
+		Seq:   s.state.nextSeq(),
+		Graph: key,
+		Episode: episode{
+			UUID:              newUUID(),
+			Content:           content,
+			Source:            source,
+			SourceDescription: sourceDescription,
+			Processed:         true,
+			CreatedAt:         createdAt,
+		},
+	}
+	s.state.Episodes[rec.Episode.UUID] = rec
+	return rec
+}
+
+func (s *Server) addData(r *http.Request) (any, error) {
+	var req struct {
+		episodeData
+		UserID  *string `json:"user_id"`
+		GraphID *string `json:"graph_id"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	key, err := s.targetGraph(req.UserID, req.GraphID)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := ""
+	if req.CreatedAt != nil {
+		createdAt = *req.CreatedAt
+	}
+	return s.addEpisode(key, req.Data, req.Type, req.SourceDescription, createdAt).Episode, nil
+}
+
+// maxBatchEpisodes is the most episodes the API accepts in one batch.
+const maxBatchEpisodes = 20
+
+func (s *Server) addDataBatch(r *http.Request) (any, error) {
+	var req struct {
+		Episodes []*episodeData `json:"episodes"`
+		UserID   *string        `json:"user_id"`
+		GraphID  *string        `json:"graph_id"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	key, err := s.targetGraph(req.UserID, req.GraphID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Episodes) == 0 {
+		return nil, errBadRequest("episodes are required")
+	}
+	if len(req.Episodes) > maxBatchEpisodes {
+		return nil, errBadRequest("at most %d episodes can be added per batch, got %d", maxBatchEpisodes, len(req.Episodes))
+	}
+	for i, d := range req.Episodes {
+		if err := d.validate(); err != nil {
+			return nil, errBadRequest("episode %d: %v", i, err)
+		}
+	}
+
+	episodes := make([]episode, 0, len(req.Episodes))
+	for _, d := range req.Episodes {
+		createdAt := ""
+		if d.CreatedAt != nil {
+			createdAt = *d.CreatedAt
+		}
+		episodes = append(episodes, s.addEpisode(key, d.Data, d.Type, d.SourceDescription, createdAt).Episode)
+	}
+	return episodes, nil
+}
+
+func (s *Server) createGraph(r *http.Request) (any, error) {
+	var req struct {
+		GraphID     string  `json:"graph_id"`
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.GraphID == "" {
+		return nil, errBadRequest("graph_id is required")
+	}
+	if _, ok := s.state.Graphs[req.GraphID]; ok {
+		return nil, errBadRequest("graph already exists: %s", req.GraphID)
+	}
+
+	rec := &graphRecord{
+		Seq: s.state.nextSeq(),
+		Graph: graph{
+			UUID:        newUUID(),
+			GraphID:     req.GraphID,
+			Name:        req.Name,
+			Description: req.Description,
+			ProjectUUID: s.state.ProjectUUID,
+			CreatedAt:   now(),
+		},
+	}
+	s.state.Graphs[req.GraphID] = rec
+	return rec.Graph, nil
+}
+
+func (s *Server) listGraphs(r *http.Request) (any, error) {
+	pageNumber, err := queryInt(r, 1, "pageNumber", "page_number")
+	if err != nil {
+		return nil, err
+	}
+	pageSize, err := queryInt(r, 0, "pageSize", "page_size")
+	if err != nil {
+		return nil, err
+	}
+
+	all := sortedBySeq(s.state.Graphs, func(g *graphRecord) int64 { return g.Seq })
+	page := paginate(all, pageNumber, pageSize)
+	graphs := make([]graph, len(page))
+	for i, g := range page {
+		graphs[i] = g.Graph
+	}
+	return map[string]any{"graphs": graphs, "row_count": len(graphs), "total_count": len(all)}, nil
+}
+
+func (s *Server) lookupGraph(graphID string) (*graphRecord, error) {
+	rec, ok := s.state.Graphs[graphID]
+	if !ok {
+		return nil, errNotFound("graph not found: %s", graphID)
+	}
+	return rec, nil
+}
+
+func (s *Server) getGraph(r *http.Request) (any, error) {
+	rec, err := s.lookupGraph(r.PathValue("graphId"))
+	if err != nil {
+		return nil, err
+	}
+	return rec.Graph, nil
+}
+
+func (s *Server) updateGraph(r *http.Request) (any, error) {
+	rec, err := s.lookupGraph(r.PathValue("graphId"))
+	if err != nil {
+		return nil, err
+	}
+	var req struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		rec.Graph.Name = req.Name
+	}
+	if req.Description != nil {
+		rec.Graph.Description = req.Description
+	}
+	return rec.Graph, nil
+}
+
+func (s *Server) deleteGraph(r *http.Request) (any, error) {
+	graphID := r.PathValue("graphId")
+	if _, err := s.lookupGraph(graphID); err != nil {
+		return nil, err
+	}
+	delete(s.state.Graphs, graphID)
+	s.deleteGraphData(standaloneGraphKey(graphID))
+	return success("Deleted"), nil
+}
+
+// deleteGraphData removes every node, edge and episode in a graph.
+func (s *Server) deleteGraphData(key string) {
+	for id, n := range s.state.Nodes {
+		if n.Graph == key {
+			delete(s.state.Nodes, id)
+		}
+	}
+	for id, e := range s.state.Edges {
+		if e.Graph == key {
+			delete(s.state.Edges, id)
+		}
+	}
+	for id, ep := range s.state.Episodes {
+		if ep.Graph == key {
+			delete(s.state.Episodes, id)
+		}
+	}
+}
+
+// cloneGraph copies a user or standalone graph, creating the target user or
+// graph. Clones complete synchronously.
+func (s *Server) cloneGraph(r *http.Request) (any, error) {
+	var req struct {
+		SourceUserID  *string `json:"source_user_id"`
+		SourceGraphID *string `json:"source_graph_id"`
+		TargetUserID  *string `json:"target_user_id"`
+		TargetGraphID *string `json:"target_graph_id"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	source, err := s.targetGraph(req.SourceUserID, req.SourceGraphID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := map[string]any{}
+	var target string
+	switch {
+	case req.SourceUserID != nil && *req.SourceUserID != "":
+		src := s.state.Users[*req.SourceUserID]
+		targetID := newUUID()
+		if req.TargetUserID != nil && *req.TargetUserID != "" {
+			targetID = *req.TargetUserID
+		}
+		if _, ok := s.state.Users[targetID]; ok {
+			return nil, errBadRequest("user already exists: %s", targetID)
+		}
+		clone := src.User
+		clone.UUID = newUUID()
+		clone.UserID = targetID
+		clone.CreatedAt, clone.UpdatedAt = now(), now()
+		s.state.Users[targetID] = &userRecord{Seq: s.state.nextSeq(), User: clone}
+		target = userGraphKey(targetID)
+		resp["user_id"] = targetID
+	default:
+		src := s.state.Graphs[*req.SourceGraphID]
+		targetID := newUUID()
+		if req.TargetGraphID != nil && *req.TargetGraphID != "" {
+			targetID = *req.TargetGraphID
+		}
+		if _, ok := s.state.Graphs[targetID]; ok {
+			return nil, errBadRequest("graph already exists: %s", targetID)
+		}
+		clone := src.Graph
+		clone.UUID = newUUID()
+		clone.GraphID = targetID
+		clone.CreatedAt = now()
+		s.state.Graphs[targetID] = &graphRecord{Seq: s.state.nextSeq(), Graph: clone}
+		target = standaloneGraphKey(targetID)
+		resp["graph_id"] = targetID
+	}
+
+	s.copyGraphData(source, target)
+	resp["task_id"] = s.completedTask("clone_graph").TaskID
+	return resp, nil
+}
+
+func (s *Server) copyGraphData(source, target string) {
+	ids := map[string]string{}
+	for _, ep := range s.graphEpisodes(source) {
+		clone := *ep
+		clone.Seq = s.state.nextSeq()
+		clone.Graph = target
+		clone.Episode.UUID = newUUID()
+		ids[ep.Episode.UUID] = clone.Episode.UUID
+		s.state.Episodes[clone.Episode.UUID] = &clone
+	}
+	for _, n := range s.graphNodes(source) {
+		clone := *n
+		clone.Seq = s.state.nextSeq()
+		clone.Graph = target
+		clone.Node.UUID = newUUID()
+		ids[n.Node.UUID] = clone.Node.UUID
+		s.state.Nodes[clone.Node.UUID] = &clone
+	}
+	for _, e := range s.graphEdges(source) {
+		clone := *e
+		clone.Seq = s.state.nextSeq()
+		clone.Graph = target
+		clone.Edge.UUID = newUUID()
+		clone.Edge.SourceNodeUUID = ids[e.Edge.SourceNodeUUID]
+		clone.Edge.TargetNodeUUID = ids[e.Edge.TargetNodeUUID]
+		clone.Edge.Episodes = make([]string, len(e.Edge.Episodes))
+		for i, id := range e.Edge.Episodes {
+			clone.Edge.Episodes[i] = ids[id]
+		}
+		s.state.Edges[clone.Edge.UUID] = &clone
+	}
+}
+
+func (s *Server) findOrCreateNode(key, name string, labels []string) *nodeRecord {
+	for _, n := range s.graphNodes(key) {
+		if strings.EqualFold(n.Node.Name, name) {
+			return n
+		}
+	}
+	rec := &nodeRecord{
+		Seq:   s.state.nextSeq(),
+		Graph: key,
+		Node: node{
+			UUID:      newUUID(),
+			Name:      name,
+			Labels:    labels,
+			CreatedAt: now(),
+		},
+	}
+	s.state.Nodes[rec.Node.UUID] = rec
+	return rec
+}
+
+func (s *Server) addFactTriple(r *http.Request) (any, error) {
+	var req struct {
+		Fact                 string         `json:"fact"`
+		FactName             string         `json:"fact_name"`
+		UserID               *string        `json:"user_id"`
+		GraphID              *string        `json:"graph_id"`
+		SourceNodeName       *string        `json:"source_node_name"`
+		TargetNodeName       *string        `json:"target_node_name"`
+		SourceNodeAttributes map[string]any `json:"source_node_attributes"`
+		TargetNodeAttributes map[string]any `json:"target_node_attributes"`
+		EdgeAttributes       map[string]any `json:"edge_attributes"`
+		ValidAt              *string        `json:"valid_at"`
+		InvalidAt            *string        `json:"invalid_at"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	key, err := s.targetGraph(req.UserID, req.GraphID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Fact == "" || req.FactName == "" {
+		return nil, errBadRequest("fact and fact_name are required")
+	}
+	if req.SourceNodeName == nil || req.TargetNodeName == nil {
+		return nil, errBadRequest("source_node_name and target_node_name are required")
+	}
+
+	source := s.findOrCreateNode(key, *req.SourceNodeName, []string{"Entity"})
+	if req.SourceNodeAttributes != nil {
+		source.Node.Attributes = req.SourceNodeAttributes
+	}
+	target := s.findOrCreateNode(key, *req.TargetNodeName, []string{"Entity"})
+	if req.TargetNodeAttributes != nil {
+		target.Node.Attributes = req.TargetNodeAttributes
+	}
+
+	ep := s.addEpisode(key, req.Fact, "text", nil, "")
+	rec := &edgeRecord{
+		Seq:   s.state.nextSeq(),
+		Graph: key,
+		Edge: edge{
+			UUID:           newUUID(),
+			Name:           req.FactName,
+			Fact:           req.Fact,
+			SourceNodeUUID: source.Node.UUID,
+			TargetNodeUUID: target.Node.UUID,
+			Episodes:       []string{ep.Episode.UUID},
+			Attributes:     req.EdgeAttributes,
+			ValidAt:        req.ValidAt,
+			InvalidAt:      req.InvalidAt,
+			CreatedAt:      now(),
+		},
+	}
+	s.state.Edges[rec.Edge.UUID] = rec
+	return map[string]any{"edge": rec.Edge, "source_node": source.Node, "target_node": target.Node}, nil
+}
+
+func (s *Server) graphNodes(key string) []*nodeRecord {
+	var out []*nodeRecord
+	for _, n := range sortedBySeq(s.state.Nodes, func(n *nodeRecord) int64 { return n.Seq }) {
+		if n.Graph == key {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func (s *Server) graphEdges(key string) []*edgeRecord {
+	var out []*edgeRecord
+	for _, e := range sortedBySeq(s.state.Edges, func(e *edgeRecord) int64 { return e.Seq }) {
+		if e.Graph == key {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (s *Server) graphEpisodes(key string) []*episodeRecord {
+	var out []*episodeRecord
+	for _, ep := range sortedBySeq(s.state.Episodes, func(ep *episodeRecord) int64 { return ep.Seq }) {
+		if ep.Graph == key {
+			out = append(out, ep)
+		}
+	}
+	return out
+}
+
+// Search
+
+type searchFilters struct {
+	NodeLabels        []string `json:"node_labels"`
+	ExcludeNodeLabels []string `json:"exclude_node_labels"`
+	EdgeTypes         []string `json:"edge_types"`
+	ExcludeEdgeTypes  []string `json:"exclude_edge_types"`
+}
+
+// matchScore scores text by the fraction of query terms it contains.
+func matchScore(query, text string) float64 {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return 0
+	}
+	text = strings.ToLower(text)
+	var hits int
+	for _, term := range terms {
+		if strings.Contains(text, term) {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(terms))
+}
+
+type scored[T any] struct {
+	item  T
+	score float64
+}
+
+func topMatches[T any](items []scored[T], minScore float64, limit int) []scored[T] {
+	items = slices.DeleteFunc(items, func(s scored[T]) bool { return s.score <= 0 || s.score < minScore })
+	sort.SliceStable(items, func(i, j int) bool { return items[i].score > items[j].score })
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// search performs naive keyword matching in place of Zep's semantic search.
+func (s *Server) search(r *http.Request) (any, error) {
+	var req struct {
+		Query         string         `json:"query"`
+		UserID        *string        `json:"user_id"`
+		GraphID       *string        `json:"graph_id"`
+		Scope         string         `json:"scope"`
+		Limit         int            `json:"limit"`
+		MinScore      float64        `json:"min_score"`
+		SearchFilters *searchFilters `json:"search_filters"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	key, err := s.targetGraph(req.UserID, req.GraphID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Query == "" {
+		return nil, errBadRequest("query is required")
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+	filters := req.SearchFilters
+	if filters == nil {
+		filters = &searchFilters{}
+	}
+
+	switch req.Scope {
+	case "", "edges":
+		var matches []scored[edge]
+		for _, e := range s.graphEdges(key) {
+			if len(filters.EdgeTypes) > 0 && !slices.Contains(filters.EdgeTypes, e.Edge.Name) {
+				continue
+			}
+			if slices.Contains(filters.ExcludeEdgeTypes, e.Edge.Name) {
+				continue
+			}
+			matches = append(matches, scored[edge]{e.Edge, matchScore(req.Query, e.Edge.Name+" "+e.Edge.Fact)})
+		}
+		edges := []edge{}
+		for _, m := range topMatches(matches, req.MinScore, req.Limit) {
+			m.item.Score = &m.score
+			edges = append(edges, m.item)
+		}
+		return map[string]any{"edges": edges}, nil
+	case "nodes":
+		var matches []scored[node]
+		for _, n := range s.graphNodes(key) {
+			if len(filters.NodeLabels) > 0 && !slices.ContainsFunc(filters.NodeLabels, func(l string) bool { return slices.Contains(n.Node.Labels, l) }) {
+				continue
+			}
+			if slices.ContainsFunc(filters.ExcludeNodeLabels, func(l string) bool { return slices.Contains(n.Node.Labels, l) }) {
+				continue
+			}
+			matches = append(matches, scored[node]{n.Node, matchScore(req.Query, n.Node.Name+" "+n.Node.Summary)})
+		}
+		nodes := []node{}
+		for _, m := range topMatches(matches, req.MinScore, req.Limit) {
+			m.item.Score = &m.score
+			nodes = append(nodes, m.item)
+		}
+		return map[string]any{"nodes": nodes}, nil
+	case "episodes":
+		var matches []scored[episode]
+		for _, ep := range s.graphEpisodes(key) {
+			matches = append(matches, scored[episode]{ep.Episode, matchScore(req.Query, ep.Episode.Content)})
+		}
+		episodes := []episode{}
+		for _, m := range topMatches(matches, req.MinScore, req.Limit) {
+			m.item.Score = &m.score
+			episodes = append(episodes, m.item)
+		}
+		return map[string]any{"episodes": episodes}, nil
+	default:
+		return nil, errBadRequest("invalid scope %q", req.Scope)
+	}
+}
+
+// Entity types
+
+func (s *Server) listEntityTypes(*http.Request) (any, error) {
+	entityTypes := s.state.EntityTypes
+	if entityTypes == nil {
+		entityTypes = []json.RawMessage{}
+	}
+	edgeTypes := s.state.EdgeTypes
+	if edgeTypes == nil {
+		edgeTypes = []json.RawMessage{}
+	}
+	return map[string]any{"entity_types": entityTypes, "edge_types": edgeTypes}, nil
+}
+
+func (s *Server) setEntityTypes(r *http.Request) (any, error) {
+	var req struct {
+		EntityTypes []json.RawMessage `json:"entity_types"`
+		EdgeTypes   []json.RawMessage `json:"edge_types"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	s.state.EntityTypes = req.EntityTypes
+	s.state.EdgeTypes = req.EdgeTypes
+	return success("Entity types set"), nil
+}
+
+// Nodes and edges
+
+type listRequest struct {
+	Limit      int    `json:"limit"`
+	UUIDCursor string `json:"uuid_cursor"`
+}
+
+// afterCursor returns up to limit items following the item with the given
+// UUID. An empty cursor starts from the beginning.
+func afterCursor[T any](items []T, uuidOf func(T) string, cursor string, limit int) []T {
+	if cursor != "" {
+		idx := slices.IndexFunc(items, func(item T) bool { return uuidOf(item) == cursor })
+		if idx < 0 {
+			return nil
+		}
+		items = items[idx+1:]
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+func (s *Server) listNodes(r *http.Request) (any, error) {
+	key, err := s.graphKey(r)
+	if err != nil {
+		return nil, err
+	}
+	var req listRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	nodes := []node{}
+	for _, n := range afterCursor(s.graphNodes(key), func(n *nodeRecord) string { return n.Node.UUID }, req.UUIDCursor, req.Limit) {
+		nodes = append(nodes, n.Node)
+	}
+	return nodes, nil
+}
+
+func (s *Server) lookupNode(uuid string) (*nodeRecord, error) {
+	rec, ok := s.state.Nodes[uuid]
+	if !ok {
+		return nil, errNotFound("node not found: %s", uuid)
+	}
+	return rec, nil
+}
+
+func (s *Server) getNode(r *http.Request) (any, error) {
+	rec, err := s.lookupNode(r.PathValue("uuid"))
+	if err != nil {
+		return nil, err
+	}
+	return rec.Node, nil
+}
+
+func (s *Server) deleteNode(r *http.Request) (any, error) {
+	uuid := r.PathValue("uuid")
+	if _, err := s.lookupNode(uuid); err != nil {
+		return nil, err
+	}
+	delete(s.state.Nodes, uuid)
+	for id, e := range s.state.Edges {
+		if e.Edge.SourceNodeUUID == uuid || e.Edge.TargetNodeUUID == uuid {
+			delete(s.state.Edges, id)
+		}
+	}
+	return success("Deleted"), nil
+}
+
+func (s *Server) nodeEdges(n *nodeRecord) []*edgeRecord {
+	var out []*edgeRecord
+	for _, e := range s.graphEdges(n.Graph) {
+		if e.Edge.SourceNodeUUID == n.Node.UUID || e.Edge.TargetNodeUUID == n.Node.UUID {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (s *Server) getNodeEdges(r *http.Request) (any, error) {
+	rec, err := s.lookupNode(r.PathValue("uuid"))
+	if err != nil {
+		return nil, err
+	}
+	edges := []edge{}
+	for _, e := range s.nodeEdges(rec) {
+		edges = append(edges, e.Edge)
+	}
+	return edges, nil
+}
+
+func (s *Server) getNodeEpisodes(r *http.Request) (any, error) {
+	rec, err := s.lookupNode(r.PathValue("uuid"))
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, e := range s.nodeEdges(rec) {
+		for _, id := range e.Edge.Episodes {
+			seen[id] = true
+		}
+	}
+	episodes := []episode{}
+	for _, ep := range s.graphEpisodes(rec.Graph) {
+		if seen[ep.Episode.UUID] {
+			episodes = append(episodes, ep.Episode)
+		}
+	}
+	return map[string]any{"episodes": episodes}, nil
+}
+
+func (s *Server) listEdges(r *http.Request) (any, error) {
+	key, err := s.graphKey(r)
+	if err != nil {
+		return nil, err
+	}
+	var req listRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	edges := []edge{}
+	for _, e := range afterCursor(s.graphEdges(key), func(e *edgeRecord) string { return e.Edge.UUID }, req.UUIDCursor, req.Limit) {
+		edges = append(edges, e.Edge)
+	}
+	return edges, nil
+}
+
+func (s *Server) lookupEdge(uuid string) (*edgeRecord, error) {
+	rec, ok := s.state.Edges[uuid]
+	if !ok {
+		return nil, errNotFound("edge not found: %s", uuid)
+	}
+	return rec, nil
+}
+
+func (s *Server) getEdge(r *http.Request) (any, error) {
+	rec, err := s.lookupEdge(r.PathValue("uuid"))
+	if err != nil {
+		return nil, err
+	}
+	return rec.Edge, nil
+}
+
+func (s *Server) deleteEdge(r *http.Request) (any, error) {
+	uuid := r.PathValue("uuid")
+	if _, err := s.lookupEdge(uuid); err != nil {
+		return nil, err
+	}
+	delete(s.state.Edges, uuid)
+	return success("Deleted"), nil
+}
+
+// Episodes
+
+func (s *Server) episodesSubresource(r *http.Request) (any, error) {
+	first, second := r.PathValue("first"), r.PathValue("second")
+	switch {
+	case second == "mentions":
+		return s.getEpisodeMentions(first)
+	case first == "user":
+		key, err := s.userGraph(second)
+		if err != nil {
+			return nil, err
+		}
+		return s.listEpisodes(r, key)
+	case first == "graph":
+		key, err := s.standaloneGraph(second)
+		if err != nil {
+			return nil, err
+		}
+		return s.listEpisodes(r, key)
+	default:
+		return nil, errNotFound("sandbox does not emulate %s %s", r.Method, r.URL.Path)
+	}
+}
+
+// listEpisodes returns a graph's episodes, or its lastn most recent ones.
+func (s *Server) listEpisodes(r *http.Request, key string) (any, error) {
+	lastn, err := queryInt(r, 0, "lastn")
+	if err != nil {
+		return nil, err
+	}
+	recs := s.graphEpisodes(key)
+	if lastn > 0 && len(recs) > lastn {
+		recs = recs[len(recs)-lastn:]
+	}
+	episodes := make([]episode, len(recs))
+	for i, ep := range recs {
+		episodes[i] = ep.Episode
+	}
+	return map[string]any{"episodes": episodes}, nil
+}
+
+func (s *Server) lookupEpisode(uuid string) (*episodeRecord, error) {
+	rec, ok := s.state.Episodes[uuid]
+	if !ok {
+		return nil, errNotFound("episode not found: %s", uuid)
+	}
+	return rec, nil
+}
+
+func (s *Server) getEpisode(r *http.Request) (any, error) {
+	rec, err := s.lookupEpisode(r.PathValue("uuid"))
+	if err != nil {
+		return nil, err
+	}
+	return rec.Episode, nil
+}
+
+func (s *Server) getEpisodeMentions(uuid string) (any, error) {
+	rec, err := s.lookupEpisode(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := []edge{}
+	nodeIDs := map[string]bool{}
+	for _, e := range s.graphEdges(rec.Graph) {
+		if slices.Contains(e.Edge.Episodes, uuid) {
+			edges = append(edges, e.Edge)
+			nodeIDs[e.Edge.SourceNodeUUID] = true
+			nodeIDs[e.Edge.TargetNodeUUID] = true
+		}
+	}
+	nodes := []node{}
+	for _, n := range s.graphNodes(rec.Graph) {
+		if nodeIDs[n.Node.UUID] {
+			nodes = append(nodes, n.Node)
+		}
+	}
+	return map[string]any{"nodes": nodes, "edges": edges}, nil
+}
+
+func (s *Server) deleteEpisode(r *http.Request) (any, error) {
+	uuid := r.PathValue("uuid")
+	if _, err := s.lookupEpisode(uuid); err != nil {
+		return nil, err
+	}
+	delete(s.state.Episodes, uuid)
+	return success("Deleted"), nil
+}
+
+// Tasks and projects
+
+func (s *Server) getTask(r *http.Request) (any, error) {
+	t, ok := s.state.Tasks[r.PathValue("taskId")]
+	if !ok {
+		return nil, errNotFound("task not found: %s", r.PathValue("taskId"))
+	}
+	return t, nil
+}
+
+func (s *Server) getProject(*http.Request) (any, error) {
+	return map[string]any{
+		"project": map[string]any{
+			"uuid":        s.state.ProjectUUID,
+			"name":        "sandbox",
+			"description": "Local zepctl sandbox",
+		},
+	}, nil
+}
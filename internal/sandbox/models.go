@@ -0,0 +1,139 @@
+package sandbox
+
+// The types in this file mirror the JSON shapes of the Zep API.
+
+type user struct {
+	UUID        string         `json:"uuid"`
+	UserID      string         `json:"user_id"`
+	Email       *string        `json:"email,omitempty"`
+	FirstName   *string        `json:"first_name,omitempty"`
+	LastName    *string        `json:"last_name,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	ProjectUUID string         `json:"project_uuid"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+}
+
+type thread struct {
+	UUID        string `json:"uuid"`
+	ThreadID    string `json:"thread_id"`
+	UserID      string `json:"user_id"`
+	ProjectUUID string `json:"project_uuid"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type message struct {
+	UUID      string         `json:"uuid"`
+	Role      string         `json:"role"`
+	Name      *string        `json:"name,omitempty"`
+	Content   string         `json:"content"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Processed bool           `json:"processed"`
+	CreatedAt string         `json:"created_at"`
+}
+
+type graph struct {
+	UUID        string  `json:"uuid"`
+	GraphID     string  `json:"graph_id"`
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	ProjectUUID string  `json:"project_uuid"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+type node struct {
+	UUID       string         `json:"uuid"`
+	Name       string         `json:"name"`
+	Labels     []string       `json:"labels,omitempty"`
+	Summary    string         `json:"summary"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Score      *float64       `json:"score,omitempty"`
+	CreatedAt  string         `json:"created_at"`
+}
+
+type edge struct {
+	UUID           string         `json:"uuid"`
+	Name           string         `json:"name"`
+	Fact           string         `json:"fact"`
+	SourceNodeUUID string         `json:"source_node_uuid"`
+	TargetNodeUUID string         `json:"target_node_uuid"`
+	Episodes       []string       `json:"episodes,omitempty"`
+	Attributes     map[string]any `json:"attributes,omitempty"`
+	ValidAt        *string        `json:"valid_at,omitempty"`
+	InvalidAt      *string        `json:"invalid_at,omitempty"`
+	ExpiredAt      *string        `json:"expired_at,omitempty"`
+	Score          *float64       `json:"score,omitempty"`
+	CreatedAt      string         `json:"created_at"`
+}
+
+type episode struct {
+	UUID              string   `json:"uuid"`
+	Content           string   `json:"content"`
+	Source            string   `json:"source,omitempty"`
+	SourceDescription *string  `json:"source_description,omitempty"`
+	Role              *string  `json:"role,omitempty"`
+	RoleType          *string  `json:"role_type,omitempty"`
+	Processed         bool     `json:"processed"`
+	Score             *float64 `json:"score,omitempty"`
+	CreatedAt         string   `json:"created_at"`
+}
+
+type task struct {
+	TaskID      string `json:"task_id"`
+	Type        string `json:"type"`
+	Status      string `json:"status"`
+	CreatedAt   string `json:"created_at"`
+	StartedAt   string `json:"started_at"`
+	CompletedAt string `json:"completed_at"`
+}
+
+type userInstruction struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
+
+// Stored records wrap API objects with the bookkeeping the emulator needs.
+// Graph-scoped records carry a graph key: "user:<user-id>" for user graphs
+// and "graph:<graph-id>" for standalone graphs.
+
+type userRecord struct {
+	Seq  int64 `json:"seq"`
+	User user  `json:"user"`
+}
+
+type threadRecord struct {
+	Seq      int64      `json:"seq"`
+	Thread   thread     `json:"thread"`
+	Messages []*message `json:"messages"`
+}
+
+type graphRecord struct {
+	Seq   int64 `json:"seq"`
+	Graph graph `json:"graph"`
+}
+
+type nodeRecord struct {
+	Seq   int64  `json:"seq"`
+	Graph string `json:"graph"`
+	Node  node   `json:"node"`
+}
+
+type edgeRecord struct {
+	Seq   int64  `json:"seq"`
+	Graph string `json:"graph"`
+	Edge  edge   `json:"edge"`
+}
+
+type episodeRecord struct {
+	Seq     int64   `json:"seq"`
+	Graph   string  `json:"graph"`
+	Episode episode `json:"episode"`
+}
+
+func userGraphKey(userID string) string {
+	return "user:" + userID
+}
+
+func standaloneGraphKey(graphID string) string {
+	return "graph:" + graphID
+}
@@ -0,0 +1,206 @@
+// Package sandbox implements an in-memory emulator of the Zep API endpoints
+// used by zepctl. It lets commands run offline against throwaway state,
+// optionally persisted to a local file.
+package sandbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// BasePath is the path prefix the emulator serves the API under, matching
+// the path of the hosted Zep API.
+const BasePath = "/api/v2"
+
+// Server is a running sandbox emulator.
+type Server struct {
+	mu        sync.Mutex
+	state     *state
+	statePath string
+	listener  net.Listener
+	srv       *http.Server
+}
+
+// state holds every resource known to the emulator. It is serialized as-is
+// when the sandbox is persisted to a file.
+type state struct {
+	ProjectUUID  string                        `json:"project_uuid"`
+	Seq          int64                         `json:"seq"`
+	Users        map[string]*userRecord        `json:"users"`
+	Threads      map[string]*threadRecord      `json:"threads"`
+	Graphs       map[string]*graphRecord       `json:"graphs"`
+	Nodes        map[string]*nodeRecord        `json:"nodes"`
+	Edges        map[string]*edgeRecord        `json:"edges"`
+	Episodes     map[string]*episodeRecord     `json:"episodes"`
+	Tasks        map[string]*task              `json:"tasks"`
+	Instructions map[string][]*userInstruction `json:"instructions"`
+	EntityTypes  []json.RawMessage             `json:"entity_types"`
+	EdgeTypes    []json.RawMessage             `json:"edge_types"`
+}
+
+func newState() *state {
+	return &state{
+		ProjectUUID:  newUUID(),
+		Users:        map[string]*userRecord{},
+		Threads:      map[string]*threadRecord{},
+		Graphs:       map[string]*graphRecord{},
+		Nodes:        map[string]*nodeRecord{},
+		Edges:        map[string]*edgeRecord{},
+		Episodes:     map[string]*episodeRecord{},
+		Tasks:        map[string]*task{},
+		Instructions: map[string][]*userInstruction{},
+	}
+}
+
+// nextSeq returns a monotonically increasing sequence number used to keep
+// list results in insertion order.
+func (st *state) nextSeq() int64 {
+	st.Seq++
+	return st.Seq
+}
+
+// Start launches an emulator on a random loopback port. If statePath is not
+// empty, state is loaded from that file (when it exists) and saved back to it
+// after every change.
+func Start(statePath string) (*Server, error) {
+	s := &Server{state: newState(), statePath: statePath}
+	if statePath != "" {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("starting sandbox listener: %w", err)
+	}
+	s.listener = listener
+	s.srv = &http.Server{
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() { _ = s.srv.Serve(listener) }()
+	return s, nil
+}
+
+// URL returns the base URL of the emulated API, including BasePath.
+func (s *Server) URL() string {
+	return "http://" + s.listener.Addr().String() + BasePath
+}
+
+// Close stops the emulator.
+func (s *Server) Close() error {
+	return s.srv.Shutdown(context.Background())
+}
+
+func (s *Server) load() error {
+	data, err := os.ReadFile(s.statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading sandbox state: %w", err)
+	}
+
+	st := newState()
+	if err := json.Unmarshal(data, st); err != nil {
+		return fmt.Errorf("parsing sandbox state: %w", err)
+	}
+	s.state = st
+	return nil
+}
+
+// save writes the state file atomically. The caller must hold s.mu.
+func (s *Server) save() error {
+	if s.statePath == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling sandbox state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.statePath), 0o700); err != nil {
+		return fmt.Errorf("creating sandbox state directory: %w", err)
+	}
+	tmp := s.statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing sandbox state: %w", err)
+	}
+	if err := os.Rename(tmp, s.statePath); err != nil {
+		return fmt.Errorf("writing sandbox state: %w", err)
+	}
+	return nil
+}
+
+// apiError is an error with an HTTP status, returned by handlers.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func errNotFound(format string, args ...any) error {
+	return &apiError{status: http.StatusNotFound, message: fmt.Sprintf(format, args...)}
+}
+
+func errBadRequest(format string, args ...any) error {
+	return &apiError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		status = http.StatusInternalServerError
+		data, _ = json.Marshal(map[string]string{"message": err.Error()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		status = apiErr.status
+	}
+	writeJSON(w, status, map[string]string{"message": err.Error()})
+}
+
+func decodeBody(r *http.Request, v any) error {
+	if r.Body == nil {
+		return nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return errBadRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,164 @@
+package sandbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func call(t *testing.T, s *Server, method, path string, body any) (int, map[string]any) {
+	t.Helper()
+
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, s.URL()+path, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var out any
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("%s %s: decoding response: %v", method, path, err)
+	}
+	if m, ok := out.(map[string]any); ok {
+		return resp.StatusCode, m
+	}
+	return resp.StatusCode, map[string]any{"items": out}
+}
+
+func start(t *testing.T, statePath string) *Server {
+	t.Helper()
+	s, err := Start(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestEndpoints(t *testing.T) {
+	s := start(t, "")
+
+	tooManyEpisodes := make([]map[string]any, maxBatchEpisodes+1)
+	for i := range tooManyEpisodes {
+		tooManyEpisodes[i] = map[string]any{"type": "text", "data": "hello"}
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       any
+		wantStatus int
+		wantKey    string
+	}{
+		{"create user", "POST", "/users", map[string]any{"user_id": "alice"}, 200, "uuid"},
+		{"duplicate user", "POST", "/users", map[string]any{"user_id": "alice"}, 400, "message"},
+		{"list users", "GET", "/users-ordered?pageNumber=1&pageSize=10", nil, 200, "users"},
+		{"user node", "GET", "/users/alice/node", nil, 200, "node"},
+		{"missing user", "GET", "/users/bob", nil, 404, "message"},
+		{"create thread", "POST", "/threads", map[string]any{"thread_id": "t1", "user_id": "alice"}, 200, "thread_id"},
+		{"add messages", "POST", "/threads/t1/messages", map[string]any{"messages": []map[string]any{{"role": "user", "content": "hello"}}}, 200, "message_uuids"},
+		{"add messages batch", "POST", "/threads/t1/messages-batch", map[string]any{"messages": []map[string]any{{"role": "user", "content": "again"}}}, 200, "task_id"},
+		{"get messages", "GET", "/threads/t1/messages?lastn=1", nil, 200, "messages"},
+		{"thread context", "GET", "/threads/t1/context", nil, 200, "context"},
+		{"create graph", "POST", "/graph/create", map[string]any{"graph_id": "g1"}, 200, "graph_id"},
+		{"list graphs", "GET", "/graph/list-all", nil, 200, "graphs"},
+		{"add data", "POST", "/graph", map[string]any{"graph_id": "g1", "type": "text", "data": "hello"}, 200, "uuid"},
+		{"add data bad type", "POST", "/graph", map[string]any{"graph_id": "g1", "type": "xml", "data": "hello"}, 400, "message"},
+		{"add data too long", "POST", "/graph", map[string]any{"graph_id": "g1", "type": "text", "data": strings.Repeat("x", maxEpisodeLength+1)}, 400, "message"},
+		{"add data batch too large", "POST", "/graph-batch", map[string]any{"graph_id": "g1", "episodes": tooManyEpisodes}, 400, "message"},
+		{"add data no target", "POST", "/graph", map[string]any{"type": "text", "data": "hello"}, 400, "message"},
+		{"add fact", "POST", "/graph/add-fact-triple", map[string]any{"graph_id": "g1", "fact": "Alice knows Bob", "fact_name": "KNOWS", "source_node_name": "Alice", "target_node_name": "Bob"}, 200, "edge"},
+		{"list nodes", "POST", "/graph/node/graph/g1", map[string]any{"limit": 10}, 200, "items"},
+		{"list edges", "POST", "/graph/edge/graph/g1", map[string]any{}, 200, "items"},
+		{"search", "POST", "/graph/search", map[string]any{"graph_id": "g1", "query": "knows"}, 200, "edges"},
+		{"user episodes", "GET", "/graph/episodes/user/alice?lastn=5", nil, 200, "episodes"},
+		{"graph episodes", "GET", "/graph/episodes/graph/g1", nil, 200, "episodes"},
+		{"clone graph", "POST", "/graph/clone", map[string]any{"source_graph_id": "g1", "target_graph_id": "g2"}, 200, "task_id"},
+		{"set entity types", "PUT", "/entity-types", map[string]any{"entity_types": []map[string]any{{"name": "Person", "description": "A person"}}}, 200, "message"},
+		{"list entity types", "GET", "/entity-types", nil, 200, "entity_types"},
+		{"add instructions", "POST", "/user-summary-instructions", map[string]any{"instructions": []map[string]any{{"name": "tone", "text": "Be brief"}}}, 200, "message"},
+		{"list instructions", "GET", "/user-summary-instructions", nil, 200, "instructions"},
+		{"project", "GET", "/projects/info", nil, 200, "project"},
+		{"unknown endpoint", "GET", "/nope", nil, 404, "message"},
+		{"delete user", "DELETE", "/users/alice", nil, 200, "message"},
+		{"deleted user threads", "DELETE", "/threads/t1", nil, 404, "message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := call(t, s, tt.method, tt.path, tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %v)", status, tt.wantStatus, body)
+			}
+			if _, ok := body[tt.wantKey]; !ok {
+				t.Errorf("response %v has no %q", body, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestNodeCursorPagination(t *testing.T) {
+	s := start(t, "")
+	call(t, s, "POST", "/graph/create", map[string]any{"graph_id": "g"})
+	for _, target := range []string{"B", "C", "D"} {
+		call(t, s, "POST", "/graph/add-fact-triple", map[string]any{
+			"graph_id": "g", "fact": "A relates to " + target, "fact_name": "RELATES_TO",
+			"source_node_name": "A", "target_node_name": target,
+		})
+	}
+
+	var names []string
+	cursor := ""
+	for {
+		_, body := call(t, s, "POST", "/graph/node/graph/g", map[string]any{"limit": 3, "uuid_cursor": cursor})
+		items, _ := body["items"].([]any)
+		for _, item := range items {
+			names = append(names, item.(map[string]any)["name"].(string))
+		}
+		if len(items) < 3 {
+			break
+		}
+		cursor = items[len(items)-1].(map[string]any)["uuid"].(string)
+	}
+
+	if got, want := len(names), 4; got != want {
+		t.Fatalf("got %d nodes %v, want %d", got, names, want)
+	}
+	for i, want := range []string{"A", "B", "C", "D"} {
+		if names[i] != want {
+			t.Errorf("names[%d] = %q, want %q", i, names[i], want)
+		}
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s := start(t, path)
+	if status, _ := call(t, s, "POST", "/users", map[string]any{"user_id": "alice"}); status != 200 {
+		t.Fatalf("creating user: status %d", status)
+	}
+	_ = s.Close()
+
+	s = start(t, path)
+	if status, body := call(t, s, "GET", "/users/alice", nil); status != 200 || body["user_id"] != "alice" {
+		t.Errorf("after restart: status %d, body %v", status, body)
+	}
+}